@@ -0,0 +1,229 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"strconv"
+
+	"learn-hub-backend/config"
+	"learn-hub-backend/database"
+	"learn-hub-backend/logging"
+	"learn-hub-backend/services"
+)
+
+const usage = `用法:
+  learn-hub [serve]                                   启动服务
+  learn-hub check-config                              校验配置并列出全部问题
+  learn-hub config print [--redacted]                 输出合并后的配置（--redacted 隐藏密码和密钥）
+  learn-hub migrate up                                执行全部未执行的数据库迁移
+  learn-hub migrate down [数量]                        回滚最近的迁移（默认 1 个）
+  learn-hub migrate status                            查看迁移执行状态
+  learn-hub seed                                      重新写入基础数据（默认角色、菜单，无用户时创建管理员）
+  learn-hub user create -username <用户名> [选项]       创建用户（选项见 learn-hub user create -h）
+  learn-hub user reset-password <用户名> [-password 密码] 重置密码，不指定时随机生成
+  learn-hub user unlock <用户名>                       启用被禁用的用户
+  learn-hub user grant-role <用户名> <角色代码>          为用户追加角色
+  learn-hub course export <课程ID> <文件.zip>           导出课程包
+  learn-hub course import <文件.zip>                   导入课程包（草稿）`
+
+// commandHandlers 需要加载配置的子命令，参数为子命令之后的部分
+var commandHandlers = map[string]func(args []string){
+	"serve":   func([]string) { serve() },
+	"config":  runConfigCommand,
+	"migrate": runMigrateCommand,
+	"seed":    runSeedCommand,
+	"user":    runUserCommand,
+	"course":  runCourseCommand,
+}
+
+// commandsWithoutArgs 不需要二级子命令的子命令
+var commandsWithoutArgs = map[string]bool{"serve": true, "seed": true}
+
+// runCommand 执行命令行子命令
+func runCommand(args []string) {
+	switch args[0] {
+	case "help", "-h", "--help":
+		fmt.Println(usage)
+		return
+	case "check-config":
+		runCheckConfigCommand()
+		return
+	}
+
+	handler, ok := commandHandlers[args[0]]
+	if !ok || (len(args) < 2 && !commandsWithoutArgs[args[0]]) {
+		exitUsage()
+	}
+
+	// 服务日志以 JSON 格式输出到标准输出，管理命令保持文本输出
+	if args[0] == "serve" {
+		logging.Setup()
+	}
+	config.LoadConfig()
+	handler(args[1:])
+}
+
+// exitUsage 输出用法并以参数错误退出
+func exitUsage() {
+	fmt.Fprintln(os.Stderr, usage)
+	os.Exit(2)
+}
+
+// runCheckConfigCommand 校验配置，有问题时全部列出并以非零状态退出
+func runCheckConfigCommand() {
+	cfg, err := config.Load("")
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+
+	source := "环境变量"
+	if cfg.Source() != "" {
+		source = cfg.Source() + " 和环境变量"
+	}
+	fmt.Printf("配置校验通过（来源: %s，环境: %s，数据库: %s）\n", source, cfg.AppEnv, cfg.DBDriver)
+}
+
+// runSeedCommand 重新写入基础数据
+func runSeedCommand([]string) {
+	if err := database.Connect(); err != nil {
+		log.Fatal(err)
+	}
+
+	seeded, err := database.Seed()
+	if err != nil {
+		log.Fatalf("写入基础数据失败: %v", err)
+	}
+	log.Printf("基础数据写入完成，共执行 %d 个基础数据迁移", seeded)
+}
+
+// runMigrateCommand 执行数据库迁移子命令
+func runMigrateCommand(args []string) {
+	if err := database.Connect(); err != nil {
+		log.Fatal(err)
+	}
+
+	switch args[0] {
+	case "up":
+		applied, err := database.Migrate()
+		if err != nil {
+			log.Fatalf("迁移失败: %v", err)
+		}
+		log.Printf("迁移完成，本次执行 %d 个迁移", applied)
+
+	case "down":
+		steps := 1
+		if len(args) > 1 {
+			n, err := strconv.Atoi(args[1])
+			if err != nil || n < 1 {
+				log.Fatalf("回滚数量错误: %s", args[1])
+			}
+			steps = n
+		}
+		rolledBack, err := database.MigrateDown(steps)
+		if err != nil {
+			log.Fatalf("回滚失败: %v", err)
+		}
+		log.Printf("已回滚 %d 个迁移", rolledBack)
+
+	case "status":
+		states, err := database.MigrationStatus()
+		if err != nil {
+			log.Fatalf("查询迁移状态失败: %v", err)
+		}
+		for _, state := range states {
+			status := "未执行"
+			if state.Applied {
+				status = "已执行 " + state.AppliedAt.Format("2006-01-02 15:04:05")
+			}
+			fmt.Printf("%04d_%-30s %s\n", state.Version, state.Name, status)
+		}
+
+	default:
+		exitUsage()
+	}
+}
+
+// runConfigCommand 执行配置相关子命令
+func runConfigCommand(args []string) {
+	switch args[0] {
+	case "print":
+		cfg := config.AppConfig
+		if len(args) > 1 {
+			if args[1] != "--redacted" {
+				exitUsage()
+			}
+			cfg = cfg.Redacted()
+		}
+		out, err := cfg.YAML()
+		if err != nil {
+			log.Fatalf("输出配置失败: %v", err)
+		}
+		os.Stdout.Write(out)
+
+	default:
+		exitUsage()
+	}
+}
+
+// runCourseCommand 执行课程导入导出子命令
+func runCourseCommand(args []string) {
+	if err := database.InitDB(); err != nil {
+		log.Fatal(err)
+	}
+	bundleService := &services.CourseBundleService{}
+	ctx := context.Background()
+
+	switch args[0] {
+	case "export":
+		if len(args) != 3 {
+			exitUsage()
+		}
+		id, err := strconv.ParseUint(args[1], 10, 32)
+		if err != nil || id == 0 {
+			log.Fatalf("课程ID错误: %s", args[1])
+		}
+
+		f, err := os.Create(args[2])
+		if err != nil {
+			log.Fatalf("创建文件失败: %v", err)
+		}
+		if err := bundleService.Export(ctx, uint(id), f); err != nil {
+			f.Close()
+			os.Remove(args[2])
+			log.Fatalf("导出失败: %v", err)
+		}
+		if err := f.Close(); err != nil {
+			log.Fatalf("写入文件失败: %v", err)
+		}
+		log.Printf("课程 %d 已导出到 %s", id, args[2])
+
+	case "import":
+		if len(args) != 2 {
+			exitUsage()
+		}
+		f, err := os.Open(args[1])
+		if err != nil {
+			log.Fatalf("打开文件失败: %v", err)
+		}
+		defer f.Close()
+		info, err := f.Stat()
+		if err != nil {
+			log.Fatalf("读取文件失败: %v", err)
+		}
+
+		result, err := bundleService.Import(ctx, f, info.Size(), 0)
+		if err != nil {
+			log.Fatalf("导入失败: %v", err)
+		}
+		log.Printf("已导入课程 %q，新课程ID: %d", result.Title, result.CourseID)
+		for _, conflict := range result.Conflicts {
+			log.Printf("冲突: %s", conflict)
+		}
+
+	default:
+		exitUsage()
+	}
+}
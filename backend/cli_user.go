@@ -0,0 +1,147 @@
+package main
+
+import (
+	"context"
+	"crypto/rand"
+	"errors"
+	"flag"
+	"fmt"
+	"log"
+	"math/big"
+	"os"
+	"strings"
+
+	"learn-hub-backend/database"
+	"learn-hub-backend/models"
+	"learn-hub-backend/services"
+
+	"gorm.io/gorm"
+)
+
+// runUserCommand 执行用户管理子命令，与 HTTP 接口共用 services 中的逻辑
+func runUserCommand(args []string) {
+	if err := database.InitDB(); err != nil {
+		log.Fatal(err)
+	}
+	userService := &services.UserService{}
+	ctx := context.Background()
+
+	switch args[0] {
+	case "create":
+		fs := flag.NewFlagSet("user create", flag.ExitOnError)
+		username := fs.String("username", "", "用户名（必填）")
+		password := fs.String("password", "", "密码，不指定时随机生成")
+		name := fs.String("name", "", "姓名")
+		email := fs.String("email", "", "邮箱")
+		phone := fs.String("phone", "", "手机号")
+		roles := fs.String("roles", "", "角色代码，多个以逗号分隔，如 admin,user")
+		fs.Parse(args[1:])
+		if *username == "" || fs.NArg() > 0 {
+			fs.Usage()
+			os.Exit(2)
+		}
+
+		roleIDs, err := (&services.RoleService{}).RoleIDsByCodes(ctx, splitList(*roles))
+		if err != nil {
+			log.Fatalf("查询角色失败: %v", err)
+		}
+		pwd, generated := passwordOrRandom(*password)
+		user, err := userService.CreateUser(ctx, services.CreateUserInput{
+			Username: *username,
+			Password: pwd,
+			Name:     *name,
+			Email:    *email,
+			Phone:    *phone,
+			RoleIDs:  roleIDs,
+		})
+		if err != nil {
+			log.Fatalf("创建用户失败: %v", err)
+		}
+		log.Printf("已创建用户 %s（ID: %d，工号: %s，访问级别: %s）", user.Username, user.ID, user.UserID, user.Access)
+		printGeneratedPassword(pwd, generated)
+
+	case "reset-password":
+		user := findUserArg(userService, args)
+		fs := flag.NewFlagSet("user reset-password", flag.ExitOnError)
+		password := fs.String("password", "", "新密码，不指定时随机生成")
+		fs.Parse(args[2:])
+
+		pwd, generated := passwordOrRandom(*password)
+		if _, err := userService.UpdateUser(ctx, user.ID, services.UpdateUserInput{Password: pwd}); err != nil {
+			log.Fatalf("重置密码失败: %v", err)
+		}
+		log.Printf("已重置用户 %s 的密码", user.Username)
+		printGeneratedPassword(pwd, generated)
+
+	case "unlock":
+		user := findUserArg(userService, args)
+		status := 1
+		if _, err := userService.UpdateUser(ctx, user.ID, services.UpdateUserInput{Status: &status}); err != nil {
+			log.Fatalf("启用用户失败: %v", err)
+		}
+		log.Printf("已启用用户 %s", user.Username)
+
+	case "grant-role":
+		if len(args) != 3 {
+			exitUsage()
+		}
+		user := findUserArg(userService, args)
+		updated, err := userService.GrantRole(ctx, user.ID, args[2])
+		if err != nil {
+			log.Fatalf("分配角色失败: %v", err)
+		}
+		log.Printf("用户 %s 已拥有角色 %s（访问级别: %s）", updated.Username, args[2], updated.Access)
+
+	default:
+		exitUsage()
+	}
+}
+
+// findUserArg 按子命令的第一个参数（用户名）查找用户，找不到时退出
+func findUserArg(userService *services.UserService, args []string) *models.User {
+	if len(args) < 2 || strings.HasPrefix(args[1], "-") {
+		exitUsage()
+	}
+	user, err := userService.GetByUsername(context.Background(), args[1])
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		log.Fatalf("用户 %s 不存在", args[1])
+	}
+	if err != nil {
+		log.Fatalf("查询用户失败: %v", err)
+	}
+	return user
+}
+
+// passwordOrRandom 未指定密码时生成随机密码，第二个返回值表示是否为生成的密码
+func passwordOrRandom(password string) (string, bool) {
+	if password != "" {
+		return password, false
+	}
+
+	const chars = "ABCDEFGHJKLMNPQRSTUVWXYZabcdefghijkmnopqrstuvwxyz23456789"
+	b := make([]byte, 16)
+	for i := range b {
+		n, err := rand.Int(rand.Reader, big.NewInt(int64(len(chars))))
+		if err != nil {
+			log.Fatalf("生成随机密码失败: %v", err)
+		}
+		b[i] = chars[n.Int64()]
+	}
+	return string(b), true
+}
+
+func printGeneratedPassword(password string, generated bool) {
+	if generated {
+		fmt.Printf("生成的密码: %s\n请妥善保管，首次登录后尽快修改\n", password)
+	}
+}
+
+func splitList(value string) []string {
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
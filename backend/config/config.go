@@ -1,50 +1,194 @@
 package config
 
 import (
-	"log"
+	"log/slog"
 	"os"
 
 	"github.com/joho/godotenv"
 )
 
+// 运行环境
+const (
+	EnvDev        = "dev"
+	EnvProduction = "production"
+)
+
+// DefaultJWTSecret 内置的示例密钥，仅允许在开发环境使用
+const DefaultJWTSecret = "your-secret-key-change-in-production"
+
+// Config 应用配置
+// 加载顺序：内置默认值 -> 配置文件（YAML/TOML）-> 环境变量，后者覆盖前者
+// yaml/toml 标签为配置文件中的键名，env 标签为对应的环境变量，secret 标记的字段在打印时脱敏
 type Config struct {
-	DBHost     string
-	DBPort     string
-	DBUser     string
-	DBPassword string
-	DBName     string
-	JWTSecret  string
-	JWTExpire  int // 小时
-	Port       string
+	AppEnv   string `yaml:"app_env" toml:"app_env" env:"APP_ENV"`       // 运行环境：dev 为开发环境，其他值（如 production、staging）均按生产环境校验
+	LogLevel string `yaml:"log_level" toml:"log_level" env:"LOG_LEVEL"` // 日志级别：debug、info、warn、error
+
+	DBDriver   string `yaml:"db_driver" toml:"db_driver" env:"DB_DRIVER"` // mysql、postgres 或 sqlite
+	DBHost     string `yaml:"db_host" toml:"db_host" env:"DB_HOST"`
+	DBPort     string `yaml:"db_port" toml:"db_port" env:"DB_PORT"` // 为空时按数据库类型取默认端口
+	DBUser     string `yaml:"db_user" toml:"db_user" env:"DB_USER"`
+	DBPassword string `yaml:"db_password" toml:"db_password" env:"DB_PASSWORD" secret:"true"`
+	DBName     string `yaml:"db_name" toml:"db_name" env:"DB_NAME"`
+	DBSSLMode  string `yaml:"db_sslmode" toml:"db_sslmode" env:"DB_SSLMODE"` // PostgreSQL sslmode
+	DBPath     string `yaml:"db_path" toml:"db_path" env:"DB_PATH"`          // SQLite 数据库文件路径，:memory: 为内存数据库
+
+	DBMaxIdleConns       int `yaml:"db_max_idle_conns" toml:"db_max_idle_conns" env:"DB_MAX_IDLE_CONNS"`                         // 空闲连接池中连接的最大数量
+	DBMaxOpenConns       int `yaml:"db_max_open_conns" toml:"db_max_open_conns" env:"DB_MAX_OPEN_CONNS"`                         // 打开数据库连接的最大数量
+	DBConnMaxLifetimeMin int `yaml:"db_conn_max_lifetime_min" toml:"db_conn_max_lifetime_min" env:"DB_CONN_MAX_LIFETIME_MIN"`    // 连接可复用的最大时间（分钟）
+	DBConnMaxIdleTimeMin int `yaml:"db_conn_max_idle_time_min" toml:"db_conn_max_idle_time_min" env:"DB_CONN_MAX_IDLE_TIME_MIN"` // 空闲连接的最大存活时间（分钟）
+	DBConnectRetries     int `yaml:"db_connect_retries" toml:"db_connect_retries" env:"DB_CONNECT_RETRIES"`                      // 启动时连接失败的重试次数（间隔 2 秒），默认不重试
+	DBSlowQueryMs        int `yaml:"db_slow_query_ms" toml:"db_slow_query_ms" env:"DB_SLOW_QUERY_MS"`                            // 执行时间超过该值（毫秒）的 SQL 以 warn 级别记录，0 表示不记录
+
+	JWTSecret string `yaml:"jwt_secret" toml:"jwt_secret" env:"JWT_SECRET" secret:"true"`
+	JWTExpire int    `yaml:"jwt_expire_hours" toml:"jwt_expire_hours" env:"JWT_EXPIRE_HOURS"` // 小时
+
+	Port        string   `yaml:"port" toml:"port" env:"PORT"`
+	CORSOrigins []string `yaml:"cors_origins" toml:"cors_origins" env:"CORS_ORIGINS"` // 允许跨域访问的来源，* 表示全部；环境变量以逗号分隔
+	UploadDir   string   `yaml:"upload_dir" toml:"upload_dir" env:"UPLOAD_DIR"`       // 上传文件存储目录
+
+	APICompat      bool `yaml:"api_compat" toml:"api_compat" env:"API_COMPAT"`                // 兼容旧版前端：业务错误仍以 HTTP 200 返回、登录失败返回 status=error，前端迁移完成后关闭
+	MetricsEnabled bool `yaml:"metrics_enabled" toml:"metrics_enabled" env:"METRICS_ENABLED"` // 是否开放 /metrics（Prometheus 指标）
+
+	TracingExporter    string  `yaml:"tracing_exporter" toml:"tracing_exporter" env:"TRACING_EXPORTER"`             // 链路导出方式：none（不记录）、otlp 或 stdout（输出到标准输出，用于调试和测试）
+	TracingSampleRatio float64 `yaml:"tracing_sample_ratio" toml:"tracing_sample_ratio" env:"TRACING_SAMPLE_RATIO"` // 采样比例（0 到 1），上游已采样的请求始终记录
+	OTLPEndpoint       string  `yaml:"otlp_endpoint" toml:"otlp_endpoint" env:"OTLP_ENDPOINT"`                      // OTLP/HTTP collector 地址，如 http://otel-collector:4318；为空时使用 OTEL_EXPORTER_OTLP_* 环境变量或 localhost:4318
+
+	ShutdownTimeoutSec int `yaml:"shutdown_timeout_sec" toml:"shutdown_timeout_sec" env:"SHUTDOWN_TIMEOUT_SEC"` // 收到退出信号后等待进行中的请求和后台任务完成的最长时间（秒）
+
+	AnalyticsRollup bool `yaml:"analytics_rollup" toml:"analytics_rollup" env:"ANALYTICS_ROLLUP"` // 是否启用学习数据日汇总任务
+
+	ExportAsyncThreshold int64 `yaml:"export_async_threshold" toml:"export_async_threshold" env:"EXPORT_ASYNC_THRESHOLD"` // 导出行数超过该值时转为后台任务
+
+	UserIDPrefix string `yaml:"user_id_prefix" toml:"user_id_prefix" env:"USER_ID_PREFIX"` // 自动生成的工号前缀
+	UserIDWidth  int    `yaml:"user_id_width" toml:"user_id_width" env:"USER_ID_WIDTH"`    // 工号数字部分宽度（不足补零）
+
+	RecycleRetentionDays int `yaml:"recycle_retention_days" toml:"recycle_retention_days" env:"RECYCLE_RETENTION_DAYS"` // 回收站保留天数，超过后彻底删除；0 表示不自动清理
+
+	MigrateOnStart bool `yaml:"migrate_on_start" toml:"migrate_on_start" env:"MIGRATE_ON_START"` // 启动时自动执行未执行的数据库迁移
+
+	source string // 加载的配置文件路径，未使用配置文件时为空
 }
 
 var AppConfig *Config
 
-func LoadConfig() {
+// Default 内置默认配置
+func Default() *Config {
+	return &Config{
+		AppEnv:   EnvProduction,
+		LogLevel: "info",
+
+		DBDriver:  "mysql",
+		DBHost:    "localhost",
+		DBUser:    "root",
+		DBName:    "learn_hub",
+		DBSSLMode: "disable",
+		DBPath:    "learn_hub.db",
+
+		DBMaxIdleConns:       10,
+		DBMaxOpenConns:       100,
+		DBConnMaxLifetimeMin: 60,
+		DBConnMaxIdleTimeMin: 10,
+		DBSlowQueryMs:        200,
+
+		JWTSecret: DefaultJWTSecret,
+		JWTExpire: 24,
+
+		Port:        "8080",
+		CORSOrigins: []string{"*"},
+		UploadDir:   "uploads",
+
+		APICompat:      true,
+		MetricsEnabled: true,
+
+		TracingExporter:    "none",
+		TracingSampleRatio: 1,
+
+		ShutdownTimeoutSec: 30,
+
+		ExportAsyncThreshold: 5000,
+
+		UserIDWidth: 8,
+
+		RecycleRetentionDays: 30,
+
+		MigrateOnStart: true,
+	}
+}
+
+// Load 加载并校验配置
+// path 为配置文件路径；为空时依次读取环境变量 CONFIG_FILE 和当前目录下的 config.yaml、config.yml、config.toml（存在时）
+func Load(path string) (*Config, error) {
 	// 加载 .env 文件（如果存在）
 	_ = godotenv.Load()
 
-	AppConfig = &Config{
-		DBHost:     getEnv("DB_HOST", "localhost"),
-		DBPort:     getEnv("DB_PORT", "3306"),
-		DBUser:     getEnv("DB_USER", "root"),
-		DBPassword: getEnv("DB_PASSWORD", ""),
-		DBName:     getEnv("DB_NAME", "learn_hub"),
-		JWTSecret:  getEnv("JWT_SECRET", "your-secret-key-change-in-production"),
-		JWTExpire:  24, // 24小时
-		Port:       getEnv("PORT", "8080"),
+	cfg := Default()
+	if path == "" {
+		path = findConfigFile()
+	}
+	if path != "" {
+		if err := cfg.loadFile(path); err != nil {
+			return nil, err
+		}
+	}
+	if err := cfg.applyEnv(os.LookupEnv); err != nil {
+		return nil, err
+	}
+
+	if cfg.DBPort == "" {
+		cfg.DBPort = defaultDBPort(cfg.DBDriver)
 	}
 
-	// 调试输出（生产环境可删除）
-	log.Printf("数据库配置: host=%s, port=%s, user=%s, dbname=%s",
-		AppConfig.DBHost, AppConfig.DBPort, AppConfig.DBUser, AppConfig.DBName)
-	log.Println("配置加载完成")
+	if err := cfg.Validate(); err != nil {
+		return cfg, err
+	}
+	return cfg, nil
 }
 
-func getEnv(key, defaultValue string) string {
-	value := os.Getenv(key)
-	if value == "" {
-		return defaultValue
+// LoadConfig 加载配置到 AppConfig，配置有误时退出
+func LoadConfig() {
+	cfg, err := Load("")
+	if err != nil {
+		slog.Error("配置加载失败", "error", err)
+		os.Exit(1)
+	}
+	AppConfig = cfg
+
+	source := "环境变量"
+	if cfg.source != "" {
+		source = cfg.source + " 和环境变量"
+	}
+	slog.Info("配置加载完成", "source", source, "env", cfg.AppEnv)
+}
+
+// IsDev 是否为开发环境
+func (c *Config) IsDev() bool {
+	return c.AppEnv == EnvDev
+}
+
+// Source 加载的配置文件路径，未使用配置文件时为空
+func (c *Config) Source() string {
+	return c.source
+}
+
+func defaultDBPort(driver string) string {
+	switch driver {
+	case "postgres":
+		return "5432"
+	case "mysql":
+		return "3306"
+	default:
+		return ""
+	}
+}
+
+func findConfigFile() string {
+	if path := os.Getenv("CONFIG_FILE"); path != "" {
+		return path
+	}
+	for _, name := range []string{"config.yaml", "config.yml", "config.toml"} {
+		if _, err := os.Stat(name); err == nil {
+			return name
+		}
 	}
-	return value
+	return ""
 }
@@ -0,0 +1,49 @@
+package config
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+// 非开发环境拒绝示例 jwt_secret，开发环境和随机密钥可以通过校验
+func TestValidateJWTSecret(t *testing.T) {
+	cases := []struct {
+		env    map[string]string
+		reject bool
+	}{
+		{map[string]string{}, true},
+		{map[string]string{"APP_ENV": "staging"}, true},
+		{map[string]string{"APP_ENV": "staging", "JWT_SECRET": "change_me"}, true},
+		{map[string]string{"APP_ENV": EnvDev}, false},
+		{map[string]string{"JWT_SECRET": "3f9c1a7e5b2d4c6f8a0e"}, false},
+	}
+
+	for _, tc := range cases {
+		cfg := Default()
+		lookup := func(key string) (string, bool) {
+			value, ok := tc.env[key]
+			return value, ok
+		}
+		if err := cfg.applyEnv(lookup); err != nil {
+			t.Fatal(err)
+		}
+		cfg.DBPort = defaultDBPort(cfg.DBDriver)
+
+		err := cfg.Validate()
+		if !tc.reject {
+			if err != nil {
+				t.Errorf("环境变量 %v 校验失败: %v", tc.env, err)
+			}
+			continue
+		}
+		var verr *ValidationError
+		if !errors.As(err, &verr) || !errors.Is(err, ErrInvalidConfig) {
+			t.Errorf("环境变量 %v 校验返回 %v，期望 ValidationError", tc.env, err)
+			continue
+		}
+		if len(verr.Problems) != 1 || !strings.Contains(verr.Problems[0], "jwt_secret") {
+			t.Errorf("环境变量 %v 校验问题为 %v，期望只有 jwt_secret", tc.env, verr.Problems)
+		}
+	}
+}
@@ -0,0 +1,144 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// insecureJWTSecrets 示例配置中出现过的密钥，非开发环境禁止使用
+var insecureJWTSecrets = map[string]bool{
+	DefaultJWTSecret: true,
+	"change_me":      true,
+}
+
+var validLogLevels = map[string]bool{"debug": true, "info": true, "warn": true, "error": true}
+
+// ValidationError 配置校验错误，包含全部问题
+type ValidationError struct {
+	Problems []string
+}
+
+func (e *ValidationError) Error() string {
+	return "配置校验失败:\n  - " + strings.Join(e.Problems, "\n  - ")
+}
+
+// ErrInvalidConfig 配置校验失败，可用 errors.Is 判断
+var ErrInvalidConfig = errors.New("配置无效")
+
+func (e *ValidationError) Unwrap() error {
+	return ErrInvalidConfig
+}
+
+// Validate 校验配置，一次返回全部问题
+func (c *Config) Validate() error {
+	var problems []string
+	add := func(format string, args ...interface{}) {
+		problems = append(problems, fmt.Sprintf(format, args...))
+	}
+
+	if c.AppEnv == "" {
+		add("app_env 不能为空")
+	}
+	if !validLogLevels[c.LogLevel] {
+		add("log_level 只能是 debug、info、warn 或 error，当前为 %q", c.LogLevel)
+	}
+
+	switch c.DBDriver {
+	case "mysql", "postgres":
+		if c.DBHost == "" {
+			add("db_host 不能为空")
+		}
+		if !validPort(c.DBPort) {
+			add("db_port 无效: %q", c.DBPort)
+		}
+		if c.DBName == "" {
+			add("db_name 不能为空")
+		}
+	case "sqlite":
+		if c.DBPath == "" {
+			add("db_path 不能为空")
+		}
+	default:
+		add("db_driver 只能是 mysql、postgres 或 sqlite，当前为 %q", c.DBDriver)
+	}
+	if c.DBMaxOpenConns < 1 {
+		add("db_max_open_conns 必须大于 0")
+	}
+	if c.DBMaxIdleConns < 0 || c.DBMaxIdleConns > c.DBMaxOpenConns {
+		add("db_max_idle_conns 必须在 0 到 db_max_open_conns 之间")
+	}
+	if c.DBConnMaxLifetimeMin < 0 || c.DBConnMaxIdleTimeMin < 0 {
+		add("db_conn_max_lifetime_min 和 db_conn_max_idle_time_min 不能为负数")
+	}
+	if c.DBConnectRetries < 0 {
+		add("db_connect_retries 不能为负数")
+	}
+	if c.DBSlowQueryMs < 0 {
+		add("db_slow_query_ms 不能为负数")
+	}
+
+	if c.JWTSecret == "" {
+		add("jwt_secret 不能为空")
+	} else if !c.IsDev() && insecureJWTSecrets[c.JWTSecret] {
+		add("%s 环境不能使用示例 jwt_secret，请设置 JWT_SECRET 为随机字符串（开发环境可设置 APP_ENV=dev）", c.AppEnv)
+	}
+	if c.JWTExpire < 1 {
+		add("jwt_expire_hours 必须大于 0")
+	}
+
+	if !validPort(c.Port) {
+		add("port 无效: %q", c.Port)
+	}
+	for _, origin := range c.CORSOrigins {
+		if origin == "*" {
+			continue
+		}
+		u, err := url.Parse(origin)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" || (u.Path != "" && u.Path != "/") {
+			add("cors_origins 中的 %q 无效，应为 * 或 scheme://host[:port]", origin)
+		}
+	}
+	if c.UploadDir == "" {
+		add("upload_dir 不能为空")
+	}
+	switch c.TracingExporter {
+	case "none", "stdout":
+	case "otlp":
+		if c.OTLPEndpoint != "" {
+			if u, err := url.Parse(c.OTLPEndpoint); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+				add("otlp_endpoint 无效: %q，应为 http(s)://host:port", c.OTLPEndpoint)
+			}
+		}
+	default:
+		add("tracing_exporter 只能是 none、otlp 或 stdout，当前为 %q", c.TracingExporter)
+	}
+	if c.TracingSampleRatio < 0 || c.TracingSampleRatio > 1 {
+		add("tracing_sample_ratio 必须在 0 到 1 之间")
+	}
+	if c.ShutdownTimeoutSec < 1 {
+		add("shutdown_timeout_sec 必须大于 0")
+	}
+
+	if c.ExportAsyncThreshold < 1 {
+		add("export_async_threshold 必须大于 0")
+	}
+	if c.UserIDWidth < 1 || c.UserIDWidth > 20 {
+		add("user_id_width 必须在 1 到 20 之间")
+	}
+	if c.RecycleRetentionDays < 0 {
+		add("recycle_retention_days 不能为负数")
+	}
+
+	if len(problems) > 0 {
+		return &ValidationError{Problems: problems}
+	}
+	return nil
+}
+
+func validPort(port string) bool {
+	n, err := strconv.Atoi(port)
+	return err == nil && n > 0 && n < 65536
+}
@@ -0,0 +1,176 @@
+package controllers
+
+import (
+	"errors"
+	"learn-hub-backend/services"
+	"learn-hub-backend/utils"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+type AnalyticsController struct {
+	analyticsService *services.AnalyticsService
+	accessService    *services.CourseAccessService
+}
+
+func NewAnalyticsController() *AnalyticsController {
+	return &AnalyticsController{
+		analyticsService: &services.AnalyticsService{},
+		accessService:    &services.CourseAccessService{},
+	}
+}
+
+// GetCourseCompletion 课程完成率统计
+func (ctrl *AnalyticsController) GetCourseCompletion(c *gin.Context) {
+	filter, ok := ctrl.parseFilter(c)
+	if !ok {
+		return
+	}
+
+	stats, err := ctrl.analyticsService.CourseCompletion(c.Request.Context(), filter)
+	if err != nil {
+		utils.InternalError(c, "统计失败: "+err.Error())
+		return
+	}
+
+	utils.Success(c, stats)
+}
+
+// GetDepartmentCompletion 部门完成率统计
+func (ctrl *AnalyticsController) GetDepartmentCompletion(c *gin.Context) {
+	filter, ok := ctrl.parseFilter(c)
+	if !ok {
+		return
+	}
+
+	stats, err := ctrl.analyticsService.DepartmentCompletion(c.Request.Context(), filter)
+	if err != nil {
+		utils.InternalError(c, "统计失败: "+err.Error())
+		return
+	}
+
+	utils.Success(c, stats)
+}
+
+// GetActiveLearners 活跃学员趋势，granularity 为 day（默认最近 30 天）或 week（默认最近 12 周）
+func (ctrl *AnalyticsController) GetActiveLearners(c *gin.Context) {
+	filter, ok := ctrl.parseFilter(c)
+	if !ok {
+		return
+	}
+
+	granularity := c.DefaultQuery("granularity", services.GranularityDay)
+	now := time.Now()
+	if filter.EndTime == nil {
+		filter.EndTime = &now
+	}
+	if filter.StartTime == nil {
+		start := filter.EndTime.AddDate(0, 0, -29)
+		if granularity == services.GranularityWeek {
+			start = filter.EndTime.AddDate(0, 0, -7*11)
+		}
+		filter.StartTime = &start
+	}
+
+	points, err := ctrl.analyticsService.ActiveLearners(c.Request.Context(), filter, granularity)
+	if err != nil {
+		if errors.Is(err, services.ErrInvalidAnalyticsRange) {
+			utils.BadRequest(c, err.Error())
+			return
+		}
+		utils.InternalError(c, "统计失败: "+err.Error())
+		return
+	}
+
+	utils.Success(c, ActiveLearnersResponse{Granularity: granularity, Points: points})
+}
+
+// GetDropOff 学习进度分布（流失点）
+func (ctrl *AnalyticsController) GetDropOff(c *gin.Context) {
+	filter, ok := ctrl.parseFilter(c)
+	if !ok {
+		return
+	}
+
+	buckets, err := ctrl.analyticsService.DropOff(c.Request.Context(), filter)
+	if err != nil {
+		utils.InternalError(c, "统计失败: "+err.Error())
+		return
+	}
+
+	utils.Success(c, buckets)
+}
+
+// GetCourseRankings 完成率排名前后的课程
+func (ctrl *AnalyticsController) GetCourseRankings(c *gin.Context) {
+	filter, ok := ctrl.parseFilter(c)
+	if !ok {
+		return
+	}
+
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "5"))
+	minLearners, _ := strconv.ParseInt(c.DefaultQuery("minLearners", "1"), 10, 64)
+	if limit < 1 {
+		limit = 5
+	}
+
+	top, bottom, err := ctrl.analyticsService.Rankings(c.Request.Context(), filter, limit, minLearners)
+	if err != nil {
+		utils.InternalError(c, "统计失败: "+err.Error())
+		return
+	}
+
+	utils.Success(c, CourseRankingsResponse{Top: top, Bottom: bottom})
+}
+
+// parseFilter 解析统计筛选参数：courseId、startTime、endTime
+func (ctrl *AnalyticsController) parseFilter(c *gin.Context) (services.AnalyticsFilter, bool) {
+	filter := services.AnalyticsFilter{Scope: courseScope(c)}
+
+	courseID, _ := strconv.ParseUint(c.Query("courseId"), 10, 32)
+	if courseID > 0 {
+		ok, err := ctrl.accessService.CanManage(c.Request.Context(), filter.Scope, uint(courseID))
+		if err != nil {
+			utils.Fail(c, utils.CodeCourseNotFound, "课程不存在")
+			return filter, false
+		}
+		if !ok {
+			utils.Forbidden(c, "无权查看该课程")
+			return filter, false
+		}
+		filter.CourseID = uint(courseID)
+	}
+
+	if startTime := c.Query("startTime"); startTime != "" {
+		t, err := utils.ParseDateTime(startTime)
+		if err != nil {
+			utils.BadRequest(c, "startTime "+err.Error())
+			return filter, false
+		}
+		filter.StartTime = &t
+	}
+	if endTime := c.Query("endTime"); endTime != "" {
+		t, err := utils.ParseDateTime(endTime)
+		if err != nil {
+			utils.BadRequest(c, "endTime "+err.Error())
+			return filter, false
+		}
+		filter.EndTime = &t
+	}
+
+	return filter, true
+}
+
+// ActiveLearnersResponse 活跃学员趋势
+type ActiveLearnersResponse struct {
+	Granularity string                        `json:"granularity"` // day 或 week
+	Points      []services.ActiveLearnerPoint `json:"points"`
+}
+
+// CourseRankingsResponse 完成率排名前后的课程
+type CourseRankingsResponse struct {
+	Top    []services.CourseCompletionStat `json:"top"`
+	Bottom []services.CourseCompletionStat `json:"bottom"`
+}
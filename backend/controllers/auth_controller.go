@@ -1,7 +1,9 @@
 package controllers
 
 import (
-	"log"
+	"log/slog"
+
+	"learn-hub-backend/metrics"
 	"learn-hub-backend/services"
 	"learn-hub-backend/utils"
 
@@ -43,42 +45,25 @@ func (ctrl *AuthController) Login(c *gin.Context) {
 	}
 
 	// 获取用户
-	user, err := ctrl.userService.GetByUsername(req.Username)
+	user, err := ctrl.userService.GetByUsername(c.Request.Context(), req.Username)
 	if err != nil {
-		c.JSON(200, LoginResponse{
-			Status:          "error",
-			Type:            req.Type,
-			CurrentAuthority: "guest",
-		})
+		metrics.Logins.WithLabelValues(metrics.LoginFailure).Inc()
+		ctrl.loginFailed(c, req.Type, utils.CodeInvalidCredentials)
 		return
 	}
 
 	// 验证密码
 	if !ctrl.userService.VerifyPassword(user, req.Password) {
-		// 调试日志（生产环境应删除）
-		log.Printf("密码验证失败: username=%s, status=%d, password_hash_preview=%s", 
-			user.Username, user.Status, 
-			func() string {
-				if len(user.Password) > 30 {
-					return user.Password[:30] + "..."
-				}
-				return user.Password
-			}())
-		c.JSON(200, LoginResponse{
-			Status:          "error",
-			Type:            req.Type,
-			CurrentAuthority: "guest",
-		})
+		slog.WarnContext(c.Request.Context(), "登录失败：密码错误", "username", user.Username)
+		metrics.Logins.WithLabelValues(metrics.LoginFailure).Inc()
+		ctrl.loginFailed(c, req.Type, utils.CodeInvalidCredentials)
 		return
 	}
 
 	// 检查用户状态
 	if user.Status != 1 {
-		c.JSON(200, LoginResponse{
-			Status:          "error",
-			Type:            req.Type,
-			CurrentAuthority: "guest",
-		})
+		metrics.Logins.WithLabelValues(metrics.LoginDisabled).Inc()
+		ctrl.loginFailed(c, req.Type, utils.CodeUserDisabled)
 		return
 	}
 
@@ -89,6 +74,8 @@ func (ctrl *AuthController) Login(c *gin.Context) {
 		return
 	}
 
+	metrics.Logins.WithLabelValues(metrics.LoginSuccess).Inc()
+
 	// 返回登录结果
 	loginType := req.Type
 	if loginType == "" {
@@ -104,6 +91,44 @@ func (ctrl *AuthController) Login(c *gin.Context) {
 	})
 }
 
+// loginFailed 登录失败响应：兼容模式下沿用旧格式（HTTP 200、status 为 error），否则按错误码返回
+func (ctrl *AuthController) loginFailed(c *gin.Context, loginType string, code utils.ErrorCode) {
+	if utils.CompatMode() {
+		c.JSON(200, LoginResponse{
+			Status:          "error",
+			Type:            loginType,
+			CurrentAuthority: "guest",
+		})
+		return
+	}
+	utils.Fail(c, code, "")
+}
+
+// CurrentUserResponse 当前用户信息
+type CurrentUserResponse struct {
+	Name        string `json:"name"`
+	Avatar      string `json:"avatar"`
+	UserID      string `json:"userid"`
+	Email       string `json:"email"`
+	Signature   string `json:"signature"`
+	Title       string `json:"title"`
+	Access      string `json:"access"`
+	Country     string `json:"country"`
+	Province    string `json:"province"`
+	City        string `json:"city"`
+	Address     string `json:"address"`
+	Phone       string `json:"phone"`
+	NotifyCount int    `json:"notifyCount"`
+	UnreadCount int    `json:"unreadCount"`
+}
+
+// CaptchaResponse 验证码
+type CaptchaResponse struct {
+	Code    int    `json:"code"`
+	Status  string `json:"status"`
+	Captcha string `json:"captcha"` // 开发环境返回，生产环境通过短信发送
+}
+
 // GetCurrentUser 获取当前用户信息
 func (ctrl *AuthController) GetCurrentUser(c *gin.Context) {
 	userID, exists := c.Get("userId")
@@ -112,9 +137,9 @@ func (ctrl *AuthController) GetCurrentUser(c *gin.Context) {
 		return
 	}
 
-	user, err := ctrl.userService.GetByID(userID.(uint))
+	user, err := ctrl.userService.GetByID(c.Request.Context(), userID.(uint))
 	if err != nil {
-		utils.Error(c, 200, "USER_NOT_FOUND", "用户不存在")
+		utils.Fail(c, utils.CodeUserNotFound, "")
 		return
 	}
 
@@ -125,21 +150,21 @@ func (ctrl *AuthController) GetCurrentUser(c *gin.Context) {
 		displayName = user.Username
 	}
 
-	userInfo := map[string]interface{}{
-		"name":        displayName,
-		"avatar":      user.Avatar,
-		"userid":      user.UserID,
-		"email":       user.Email,
-		"signature":   user.Signature,
-		"title":       user.Title,
-		"access":      user.Access,
-		"country":     user.Country,
-		"province":    user.Province,
-		"city":        user.City,
-		"address":     user.Address,
-		"phone":       user.Phone,
-		"notifyCount": 12, // 示例数据
-		"unreadCount": 11, // 示例数据
+	userInfo := CurrentUserResponse{
+		Name:        displayName,
+		Avatar:      user.Avatar,
+		UserID:      user.UserID,
+		Email:       user.Email,
+		Signature:   user.Signature,
+		Title:       user.Title,
+		Access:      user.Access,
+		Country:     user.Country,
+		Province:    user.Province,
+		City:        user.City,
+		Address:     user.Address,
+		Phone:       user.Phone,
+		NotifyCount: 12, // 示例数据
+		UnreadCount: 11, // 示例数据
 	}
 
 	utils.Success(c, userInfo)
@@ -161,10 +186,10 @@ func (ctrl *AuthController) GetCaptcha(c *gin.Context) {
 
 	// 这里应该实现真实的验证码发送逻辑
 	// 目前返回固定验证码用于开发测试
-	utils.Success(c, gin.H{
-		"code":   200,
-		"status": "ok",
-		"captcha": "1234", // 开发环境返回，生产环境通过短信发送
+	utils.Success(c, CaptchaResponse{
+		Code:    200,
+		Status:  "ok",
+		Captcha: "1234",
 	})
 }
 
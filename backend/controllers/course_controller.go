@@ -1,45 +1,43 @@
 package controllers
 
 import (
+	"errors"
+	"fmt"
 	"learn-hub-backend/database"
 	"learn-hub-backend/models"
+	"learn-hub-backend/services"
 	"learn-hub-backend/utils"
+	"log/slog"
 	"strconv"
+	"time"
 
 	"github.com/gin-gonic/gin"
 )
 
-type CourseController struct{}
+type CourseController struct {
+	bundleService   *services.CourseBundleService
+	revisionService *services.CourseRevisionService
+	reviewService   *services.CourseReviewService
+	accessService   *services.CourseAccessService
+	deletionService *services.DeletionService
+}
 
 func NewCourseController() *CourseController {
-	return &CourseController{}
+	return &CourseController{
+		bundleService:   &services.CourseBundleService{},
+		revisionService: &services.CourseRevisionService{},
+		reviewService:   &services.CourseReviewService{},
+		accessService:   &services.CourseAccessService{},
+		deletionService: &services.DeletionService{},
+	}
 }
 
-// GetCourseList 获取课程列表（管理员）
+// GetCourseList 获取课程列表（管理员查看全部，讲师只能看到自己负责或协作的课程）
 func (ctrl *CourseController) GetCourseList(c *gin.Context) {
-	current, _ := strconv.Atoi(c.DefaultQuery("current", "1"))
-	pageSize, _ := strconv.Atoi(c.DefaultQuery("pageSize", "20"))
-	title := c.Query("title")
-	status := c.Query("status")
-
-	if current < 1 {
-		current = 1
-	}
-	if pageSize < 1 {
-		pageSize = 20
-	}
+	page := utils.GetPagination(c)
 
-	offset := (current - 1) * pageSize
-
-	// 构建查询
-	query := database.DB.Model(&models.Course{})
-
-	if title != "" {
-		query = query.Where("title LIKE ?", "%"+title+"%")
-	}
-	if status != "" {
-		query = query.Where("status = ?", status)
-	}
+	// 构建查询（与导出共用筛选条件）
+	query := services.CourseListQuery(c.Request.Context(), services.ReportParams{Title: c.Query("title"), Status: c.Query("status")}, courseScope(c))
 
 	// 查询总数
 	var total int64
@@ -47,32 +45,14 @@ func (ctrl *CourseController) GetCourseList(c *gin.Context) {
 
 	// 查询列表
 	var courses []models.Course
-	query.Offset(offset).Limit(pageSize).Order("sort_order ASC, created_at DESC").Find(&courses)
-
-	// 转换为前端格式
-	var list []map[string]interface{}
-	for _, course := range courses {
-		list = append(list, map[string]interface{}{
-			"id":          course.ID,
-			"title":       course.Title,
-			"description": course.Description,
-			"coverImage":  course.CoverImage,
-			"contentType": course.ContentType,
-			"videoUrl":    course.VideoURL,
-			"textContent": course.TextContent,
-			"duration":    course.Duration,
-			"status":      course.Status,
-			"sortOrder":   course.SortOrder,
-			"createdAt":   course.CreatedAt.Format("2006-01-02 15:04:05"),
-			"updatedAt":   course.UpdatedAt.Format("2006-01-02 15:04:05"),
-		})
-	}
-
-	c.JSON(200, gin.H{
-		"data":    list,
-		"total":   total,
-		"success": true,
-	})
+	query.Offset(page.Offset()).Limit(page.PageSize).Order("sort_order ASC, created_at DESC").Find(&courses)
+
+	list := make([]CourseResponse, 0, len(courses))
+	for i := range courses {
+		list = append(list, newCourseResponse(&courses[i]))
+	}
+
+	utils.SuccessPage(c, list, total, page)
 }
 
 // GetCourseDetail 获取课程详情
@@ -84,41 +64,42 @@ func (ctrl *CourseController) GetCourseDetail(c *gin.Context) {
 	}
 
 	var course models.Course
-	if err := database.DB.First(&course, id).Error; err != nil {
-		utils.Error(c, 200, "COURSE_NOT_FOUND", "课程不存在")
-		return
-	}
-
-	utils.Success(c, map[string]interface{}{
-		"id":          course.ID,
-		"title":       course.Title,
-		"description": course.Description,
-		"coverImage":  course.CoverImage,
-		"contentType": course.ContentType,
-		"videoUrl":    course.VideoURL,
-		"textContent": course.TextContent,
-		"duration":    course.Duration,
-		"status":      course.Status,
-		"sortOrder":   course.SortOrder,
-		"createdAt":   course.CreatedAt.Format("2006-01-02 15:04:05"),
-		"updatedAt":   course.UpdatedAt.Format("2006-01-02 15:04:05"),
+	if err := database.WithContext(c.Request.Context()).First(&course, id).Error; err != nil {
+		utils.Fail(c, utils.CodeCourseNotFound, "课程不存在")
+		return
+	}
+
+	_, draftErr := ctrl.revisionService.GetDraft(c.Request.Context(), course.ID)
+	hasDraft := draftErr == nil
+
+	instructorIDs, err := ctrl.accessService.Instructors(c.Request.Context(), course.ID)
+	if err != nil {
+		utils.InternalError(c, "查询讲师失败: "+err.Error())
+		return
+	}
+
+	utils.Success(c, CourseDetailResponse{
+		CourseResponse: newCourseResponse(&course),
+		InstructorIDs:  instructorIDs,
+		HasDraft:       hasDraft,
 	})
 }
 
+// CreateCourseRequest 创建课程请求参数
+type CreateCourseRequest struct {
+	Title       string `json:"title" binding:"required"`
+	Description string `json:"description"`
+	CoverImage  string `json:"coverImage"`
+	ContentType int    `json:"contentType" binding:"required"` // 1-视频，2-文本，3-混合
+	VideoURL    string `json:"videoUrl"`
+	TextContent string `json:"textContent"`
+	Duration    int    `json:"duration"`
+	SortOrder   int    `json:"sortOrder"`
+}
+
 // CreateCourse 创建课程
 func (ctrl *CourseController) CreateCourse(c *gin.Context) {
-	var req struct {
-		Title       string `json:"title" binding:"required"`
-		Description string `json:"description"`
-		CoverImage  string `json:"coverImage"`
-		ContentType int    `json:"contentType" binding:"required"` // 1-视频，2-文本，3-混合
-		VideoURL    string `json:"videoUrl"`
-		TextContent string `json:"textContent"`
-		Duration    int    `json:"duration"`
-		Status      int    `json:"status"`
-		SortOrder   int    `json:"sortOrder"`
-	}
-
+	var req CreateCourseRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
 		utils.BadRequest(c, "参数错误: "+err.Error())
 		return
@@ -126,7 +107,7 @@ func (ctrl *CourseController) CreateCourse(c *gin.Context) {
 
 	// 验证内容类型
 	if req.ContentType < 1 || req.ContentType > 3 {
-		utils.BadRequest(c, "内容类型错误，必须是1-视频，2-文本，3-混合")
+		utils.BadRequest(c, "内容类型错误，必须是1-视频，2-文本，3-混合（SCORM 课程请导入课件包）")
 		return
 	}
 
@@ -148,22 +129,30 @@ func (ctrl *CourseController) CreateCourse(c *gin.Context) {
 		VideoURL:    req.VideoURL,
 		TextContent: req.TextContent,
 		Duration:    req.Duration,
-		Status:      req.Status,
+		Status:      models.CourseStatusDraft, // 新课程一律为草稿，发布需走审核流程
 		SortOrder:   req.SortOrder,
+		OwnerID:     courseScope(c).UserID, // 创建人为课程负责人
 	}
 
-	if err := database.DB.Create(&course).Error; err != nil {
+	if err := database.WithContext(c.Request.Context()).Create(&course).Error; err != nil {
 		utils.InternalError(c, "创建失败: "+err.Error())
 		return
 	}
 
-	utils.Success(c, map[string]interface{}{
-		"id":          course.ID,
-		"title":       course.Title,
-		"description": course.Description,
-		"contentType": course.ContentType,
-		"status":      course.Status,
-	})
+	utils.Success(c, newCourseResponse(&course))
+}
+
+// UpdateCourseRequest 更新课程请求参数
+type UpdateCourseRequest struct {
+	Title       string `json:"title"`
+	Description string `json:"description"`
+	CoverImage  string `json:"coverImage"`
+	ContentType *int   `json:"contentType"`
+	VideoURL    string `json:"videoUrl"`
+	TextContent string `json:"textContent"`
+	Duration    *int   `json:"duration"`
+	Status      *int   `json:"status"` // 仅用于兼容旧前端，不能通过修改接口变更状态
+	SortOrder   *int   `json:"sortOrder"`
 }
 
 // UpdateCourse 更新课程
@@ -175,72 +164,84 @@ func (ctrl *CourseController) UpdateCourse(c *gin.Context) {
 	}
 
 	var course models.Course
-	if err := database.DB.First(&course, id).Error; err != nil {
-		utils.Error(c, 200, "COURSE_NOT_FOUND", "课程不存在")
+	if err := database.WithContext(c.Request.Context()).First(&course, id).Error; err != nil {
+		utils.Fail(c, utils.CodeCourseNotFound, "课程不存在")
 		return
 	}
 
-	var req struct {
-		Title       string `json:"title"`
-		Description string `json:"description"`
-		CoverImage  string `json:"coverImage"`
-		ContentType *int   `json:"contentType"`
-		VideoURL    string `json:"videoUrl"`
-		TextContent string `json:"textContent"`
-		Duration    *int   `json:"duration"`
-		Status      *int   `json:"status"`
-		SortOrder   *int   `json:"sortOrder"`
-	}
-
+	var req UpdateCourseRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
 		utils.BadRequest(c, "参数错误: "+err.Error())
 		return
 	}
 
-	// 更新字段
-	if req.Title != "" {
-		course.Title = req.Title
-	}
-	if req.Description != "" {
-		course.Description = req.Description
-	}
-	if req.CoverImage != "" {
-		course.CoverImage = req.CoverImage
-	}
-	if req.ContentType != nil {
-		course.ContentType = *req.ContentType
-	}
-	if req.VideoURL != "" {
-		course.VideoURL = req.VideoURL
-	}
-	if req.TextContent != "" {
-		course.TextContent = req.TextContent
+	if req.Status != nil && *req.Status != course.Status {
+		utils.BadRequest(c, "课程状态不能直接修改，请使用提交审核或发布/下架接口")
+		return
 	}
-	if req.Duration != nil {
-		course.Duration = *req.Duration
+	if course.Status == models.CourseStatusPending {
+		utils.Fail(c, utils.CodeInvalidCourseStatus, "课程审核中，请先撤回审核再修改")
+		return
 	}
-	if req.Status != nil {
-		course.Status = *req.Status
+
+	// 内容字段：已发布过的课程写入草稿，未发布过的直接修改
+	editContent := func(content *models.CourseContent) {
+		if req.Title != "" {
+			content.Title = req.Title
+		}
+		if req.Description != "" {
+			content.Description = req.Description
+		}
+		if req.CoverImage != "" {
+			content.CoverImage = req.CoverImage
+		}
+		if req.ContentType != nil {
+			content.ContentType = *req.ContentType
+		}
+		if req.VideoURL != "" {
+			content.VideoURL = req.VideoURL
+		}
+		if req.TextContent != "" {
+			content.TextContent = req.TextContent
+		}
+		if req.Duration != nil {
+			content.Duration = *req.Duration
+		}
+	}
+
+	isDraft := course.CurrentRevision > 0
+	if isDraft {
+		userID, _ := c.Get("userId")
+		if _, err := ctrl.revisionService.SaveDraft(c.Request.Context(), &course, userID.(uint), editContent); err != nil {
+			if errors.Is(err, services.ErrDraftInReview) {
+				utils.Fail(c, utils.CodeInvalidCourseStatus, err.Error())
+				return
+			}
+			utils.InternalError(c, "保存草稿失败: "+err.Error())
+			return
+		}
+	} else {
+		content := models.ContentOf(&course)
+		editContent(&content)
+		content.ApplyTo(&course)
 	}
+
 	if req.SortOrder != nil {
 		course.SortOrder = *req.SortOrder
 	}
 
-	if err := database.DB.Save(&course).Error; err != nil {
+	if err := database.WithContext(c.Request.Context()).Save(&course).Error; err != nil {
 		utils.InternalError(c, "更新失败: "+err.Error())
 		return
 	}
 
-	utils.Success(c, map[string]interface{}{
-		"id":          course.ID,
-		"title":       course.Title,
-		"description": course.Description,
-		"contentType": course.ContentType,
-		"status":      course.Status,
+	utils.Success(c, UpdateCourseResponse{
+		CourseResponse: newCourseResponse(&course),
+		Draft:          isDraft,
 	})
 }
 
-// DeleteCourse 删除课程
+// DeleteCourse 删除课程（放入回收站，可恢复），policy 为 block（有学习记录时拒绝）或 cascade（学习记录一并删除）
 func (ctrl *CourseController) DeleteCourse(c *gin.Context) {
 	id, _ := strconv.ParseUint(c.Param("id"), 10, 32)
 	if id == 0 {
@@ -248,15 +249,22 @@ func (ctrl *CourseController) DeleteCourse(c *gin.Context) {
 		return
 	}
 
-	if err := database.DB.Delete(&models.Course{}, id).Error; err != nil {
-		utils.InternalError(c, "删除失败: "+err.Error())
+	if err := ctrl.deletionService.DeleteCourse(c.Request.Context(), uint(id), deleteOptions(c)); err != nil {
+		handleDeletionError(c, err)
 		return
 	}
 
 	utils.Success(c, gin.H{})
 }
 
+// PublishCourseRequest 发布/下架课程请求参数
+type PublishCourseRequest struct {
+	Status  int    `json:"status" binding:"required"` // 1-发布，2-下架
+	Comment string `json:"comment"`
+}
+
 // PublishCourse 发布/下架课程
+// 课程需经审核通过才能发布：status=1 只能立即发布已审核通过、等待定时发布的课程；status=2 下架
 func (ctrl *CourseController) PublishCourse(c *gin.Context) {
 	id, _ := strconv.ParseUint(c.Param("id"), 10, 32)
 	if id == 0 {
@@ -264,34 +272,281 @@ func (ctrl *CourseController) PublishCourse(c *gin.Context) {
 		return
 	}
 
+	var req PublishCourseRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.BadRequest(c, "参数错误: "+err.Error())
+		return
+	}
+
+	userID, _ := c.Get("userId")
+	var course *models.Course
+	var err error
+	switch req.Status {
+	case models.CourseStatusPublished:
+		course, err = ctrl.reviewService.Publish(c.Request.Context(), uint(id), userID.(uint), req.Comment)
+		if errors.Is(err, services.ErrInvalidCourseTransition) {
+			utils.Fail(c, utils.CodeReviewRequired, "")
+			return
+		}
+	case models.CourseStatusUnpublished:
+		course, err = ctrl.reviewService.Unpublish(c.Request.Context(), uint(id), userID.(uint), req.Comment)
+	default:
+		utils.BadRequest(c, "状态错误，必须是1-发布或2-下架")
+		return
+	}
+	if err != nil {
+		handleCourseTransitionError(c, err)
+		return
+	}
+
+	utils.Success(c, newCourseResponse(course))
+}
+
+// ExportCourse 导出课程包（zip）
+func (ctrl *CourseController) ExportCourse(c *gin.Context) {
+	id, _ := strconv.ParseUint(c.Param("id"), 10, 32)
+	if id == 0 {
+		utils.BadRequest(c, "课程ID不能为空")
+		return
+	}
+
+	var course models.Course
+	if err := database.WithContext(c.Request.Context()).First(&course, id).Error; err != nil {
+		utils.Fail(c, utils.CodeCourseNotFound, "课程不存在")
+		return
+	}
+
+	c.Header("Content-Type", "application/zip")
+	c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="course-%d.zip"`, course.ID))
+
+	// 已开始写入响应体，出错时只能记录日志
+	if err := ctrl.bundleService.Export(c.Request.Context(), course.ID, c.Writer); err != nil {
+		slog.ErrorContext(c.Request.Context(), "导出课程包失败", "course_id", course.ID, "error", err)
+	}
+}
+
+// ImportCourse 导入课程包，课程以草稿状态创建
+func (ctrl *CourseController) ImportCourse(c *gin.Context) {
+	fileHeader, err := c.FormFile("file")
+	if err != nil {
+		utils.BadRequest(c, "请上传课程包(zip)")
+		return
+	}
+
+	file, err := fileHeader.Open()
+	if err != nil {
+		utils.BadRequest(c, "读取上传文件失败")
+		return
+	}
+	defer file.Close()
+
+	result, err := ctrl.bundleService.Import(c.Request.Context(), file, fileHeader.Size, courseScope(c).UserID)
+	if err != nil {
+		if errors.Is(err, services.ErrInvalidCourseBundle) || errors.Is(err, services.ErrScormInvalidPackage) {
+			utils.BadRequest(c, err.Error())
+			return
+		}
+		utils.InternalError(c, "导入失败: "+err.Error())
+		return
+	}
+
+	utils.Success(c, result)
+}
+
+// ScheduleCourseRequest 设置定时发布/下架时间请求参数，传空字符串表示取消
+type ScheduleCourseRequest struct {
+	PublishAt   *string `json:"publishAt"`   // 定时发布时间
+	UnpublishAt *string `json:"unpublishAt"` // 定时下架时间
+}
+
+// ScheduleCourse 设置定时发布/下架时间，传空字符串表示取消
+func (ctrl *CourseController) ScheduleCourse(c *gin.Context) {
+	id, _ := strconv.ParseUint(c.Param("id"), 10, 32)
+	if id == 0 {
+		utils.BadRequest(c, "课程ID不能为空")
+		return
+	}
+
 	var course models.Course
-	if err := database.DB.First(&course, id).Error; err != nil {
-		utils.Error(c, 200, "COURSE_NOT_FOUND", "课程不存在")
+	if err := database.WithContext(c.Request.Context()).First(&course, id).Error; err != nil {
+		utils.Fail(c, utils.CodeCourseNotFound, "课程不存在")
 		return
 	}
 
-	var req struct {
-		Status int `json:"status" binding:"required"` // 1-发布，2-下架
+	var req ScheduleCourseRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.BadRequest(c, "参数错误: "+err.Error())
+		return
+	}
+
+	parse := func(value *string, current *time.Time) (*time.Time, error) {
+		if value == nil {
+			return current, nil
+		}
+		if *value == "" {
+			return nil, nil
+		}
+		t, err := utils.ParseDateTime(*value)
+		if err != nil {
+			return nil, err
+		}
+		return &t, nil
+	}
+
+	publishAt, err := parse(req.PublishAt, course.PublishAt)
+	if err != nil {
+		utils.BadRequest(c, "publishAt "+err.Error())
+		return
+	}
+	unpublishAt, err := parse(req.UnpublishAt, course.UnpublishAt)
+	if err != nil {
+		utils.BadRequest(c, "unpublishAt "+err.Error())
+		return
 	}
+	if publishAt != nil && unpublishAt != nil && !unpublishAt.After(*publishAt) {
+		utils.BadRequest(c, "下架时间必须晚于发布时间")
+		return
+	}
+
+	err = database.WithContext(c.Request.Context()).Model(&course).Updates(map[string]interface{}{
+		"publish_at":   publishAt,
+		"unpublish_at": unpublishAt,
+	}).Error
+	if err != nil {
+		utils.InternalError(c, "操作失败: "+err.Error())
+		return
+	}
+
+	course.PublishAt, course.UnpublishAt = publishAt, unpublishAt
+	utils.Success(c, newCourseResponse(&course))
+}
 
+// formatOptionalTime 格式化可能为空的时间，空值返回空字符串
+func formatOptionalTime(t *time.Time) string {
+	if t == nil {
+		return ""
+	}
+	return t.Format(utils.DateTimeLayout)
+}
+
+// GetInstructors 获取课程负责人和协作讲师
+func (ctrl *CourseController) GetInstructors(c *gin.Context) {
+	course, ok := findCourse(c)
+	if !ok {
+		return
+	}
+
+	instructorIDs, err := ctrl.accessService.Instructors(c.Request.Context(), course.ID)
+	if err != nil {
+		utils.InternalError(c, "查询讲师失败: "+err.Error())
+		return
+	}
+
+	utils.Success(c, CourseInstructorsResponse{CourseID: course.ID, OwnerID: course.OwnerID, InstructorIDs: instructorIDs})
+}
+
+// UpdateInstructorsRequest 修改课程负责人和协作讲师请求参数
+type UpdateInstructorsRequest struct {
+	OwnerID       *uint  `json:"ownerId"`       // 课程负责人，不传则不修改
+	InstructorIds []uint `json:"instructorIds"` // 协作讲师，整体替换
+}
+
+// UpdateInstructors 修改课程负责人和协作讲师（管理员或课程负责人）
+func (ctrl *CourseController) UpdateInstructors(c *gin.Context) {
+	id, _ := strconv.ParseUint(c.Param("id"), 10, 32)
+	if id == 0 {
+		utils.BadRequest(c, "课程ID不能为空")
+		return
+	}
+
+	var req UpdateInstructorsRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
 		utils.BadRequest(c, "参数错误: "+err.Error())
 		return
 	}
 
-	if req.Status != 1 && req.Status != 2 {
-		utils.BadRequest(c, "状态错误，必须是1-发布或2-下架")
+	scope := courseScope(c)
+	if req.OwnerID != nil && !scope.All {
+		utils.Forbidden(c, "只有管理员可以转移课程负责人")
 		return
 	}
 
-	course.Status = req.Status
-	if err := database.DB.Save(&course).Error; err != nil {
+	course, err := ctrl.accessService.SetInstructors(c.Request.Context(), scope, uint(id), req.OwnerID, req.InstructorIds)
+	if err != nil {
+		if errors.Is(err, services.ErrNotCourseOwner) {
+			utils.Forbidden(c, err.Error())
+			return
+		}
 		utils.InternalError(c, "操作失败: "+err.Error())
 		return
 	}
 
-	utils.Success(c, map[string]interface{}{
-		"id":     course.ID,
-		"status": course.Status,
-	})
+	instructorIDs, _ := ctrl.accessService.Instructors(c.Request.Context(), course.ID)
+	utils.Success(c, CourseInstructorsResponse{CourseID: course.ID, OwnerID: course.OwnerID, InstructorIDs: instructorIDs})
+}
+
+// CourseResponse 课程信息
+type CourseResponse struct {
+	ID              uint   `json:"id"`
+	Title           string `json:"title"`
+	Description     string `json:"description"`
+	CoverImage      string `json:"coverImage"`
+	ContentType     int    `json:"contentType"` // 1-视频，2-文本，3-混合，4-SCORM
+	VideoURL        string `json:"videoUrl"`
+	TextContent     string `json:"textContent"`
+	Duration        int    `json:"duration"` // 秒
+	Status          int    `json:"status"`   // 0-草稿，1-已发布，2-已下架，3-待审核，4-已驳回，5-审核通过待发布
+	SortOrder       int    `json:"sortOrder"`
+	OwnerID         uint   `json:"ownerId"`
+	CurrentRevision int    `json:"currentRevision"` // 线上修订版本号，0 表示从未发布
+	PublishAt       string `json:"publishAt"`       // 定时发布时间，未设置时为空字符串
+	UnpublishAt     string `json:"unpublishAt"`     // 定时下架时间，未设置时为空字符串
+	CreatedAt       string `json:"createdAt"`
+	UpdatedAt       string `json:"updatedAt"`
+}
+
+func newCourseResponse(course *models.Course) CourseResponse {
+	return CourseResponse{
+		ID:              course.ID,
+		Title:           course.Title,
+		Description:     course.Description,
+		CoverImage:      course.CoverImage,
+		ContentType:     course.ContentType,
+		VideoURL:        course.VideoURL,
+		TextContent:     course.TextContent,
+		Duration:        course.Duration,
+		Status:          course.Status,
+		SortOrder:       course.SortOrder,
+		OwnerID:         course.OwnerID,
+		CurrentRevision: course.CurrentRevision,
+		PublishAt:       formatOptionalTime(course.PublishAt),
+		UnpublishAt:     formatOptionalTime(course.UnpublishAt),
+		CreatedAt:       course.CreatedAt.Format(utils.DateTimeLayout),
+		UpdatedAt:       course.UpdatedAt.Format(utils.DateTimeLayout),
+	}
+}
+
+// CourseDetailResponse 课程详情
+type CourseDetailResponse struct {
+	CourseResponse
+	InstructorIDs []uint `json:"instructorIds"` // 协作讲师
+	HasDraft      bool   `json:"hasDraft"`      // 是否有未发布的草稿
+}
+
+// UpdateCourseResponse 更新课程结果
+type UpdateCourseResponse struct {
+	CourseResponse
+	Draft bool `json:"draft"` // 已发布过的课程修改写入草稿，课程信息仍为线上内容
+}
+
+// CourseInstructorsResponse 课程负责人和协作讲师
+type CourseInstructorsResponse struct {
+	CourseID      uint   `json:"courseId"`
+	OwnerID       uint   `json:"ownerId"`
+	InstructorIDs []uint `json:"instructorIds"`
+}
+
+// courseScope 获取 CourseScopeMiddleware 解析的课程管理范围
+func courseScope(c *gin.Context) *services.CourseScope {
+	return c.MustGet("courseScope").(*services.CourseScope)
 }
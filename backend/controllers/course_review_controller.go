@@ -0,0 +1,181 @@
+package controllers
+
+import (
+	"context"
+	"errors"
+	"learn-hub-backend/models"
+	"learn-hub-backend/services"
+	"learn-hub-backend/utils"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+type CourseReviewController struct {
+	reviewService *services.CourseReviewService
+}
+
+func NewCourseReviewController() *CourseReviewController {
+	return &CourseReviewController{
+		reviewService: &services.CourseReviewService{},
+	}
+}
+
+// SubmitReviewRequest 提交课程发布审核请求参数
+type SubmitReviewRequest struct {
+	Comment     string `json:"comment"`     // 提交说明
+	ReviewerIds []uint `json:"reviewerIds"` // 指定审核人，不传则由审核员角色审核
+}
+
+// SubmitReview 提交课程发布审核
+func (ctrl *CourseReviewController) SubmitReview(c *gin.Context) {
+	course, ok := findCourse(c)
+	if !ok {
+		return
+	}
+
+	var req SubmitReviewRequest
+	_ = c.ShouldBindJSON(&req)
+
+	userID, _ := c.Get("userId")
+	course, err := ctrl.reviewService.Submit(c.Request.Context(), course.ID, userID.(uint), req.Comment, req.ReviewerIds)
+	if err != nil {
+		handleCourseTransitionError(c, err)
+		return
+	}
+
+	utils.Success(c, newCourseResponse(course))
+}
+
+// WithdrawReview 撤回审核
+func (ctrl *CourseReviewController) WithdrawReview(c *gin.Context) {
+	ctrl.runTransition(c, ctrl.reviewService.Withdraw)
+}
+
+// ApproveReview 审核通过
+func (ctrl *CourseReviewController) ApproveReview(c *gin.Context) {
+	ctrl.runTransition(c, ctrl.reviewService.Approve)
+}
+
+// RejectReview 审核驳回
+func (ctrl *CourseReviewController) RejectReview(c *gin.Context) {
+	ctrl.runTransition(c, ctrl.reviewService.Reject)
+}
+
+// GetReviewHistory 获取课程状态流转记录
+func (ctrl *CourseReviewController) GetReviewHistory(c *gin.Context) {
+	course, ok := findCourse(c)
+	if !ok {
+		return
+	}
+
+	transitions, err := ctrl.reviewService.History(c.Request.Context(), course.ID)
+	if err != nil {
+		utils.InternalError(c, "查询审核记录失败: "+err.Error())
+		return
+	}
+	reviewers, err := ctrl.reviewService.Reviewers(c.Request.Context(), course.ID)
+	if err != nil {
+		utils.InternalError(c, "查询审核人失败: "+err.Error())
+		return
+	}
+
+	history := make([]CourseTransitionResponse, 0, len(transitions))
+	for _, transition := range transitions {
+		history = append(history, CourseTransitionResponse{
+			ID:         transition.ID,
+			Action:     transition.Action,
+			FromStatus: transition.FromStatus,
+			ToStatus:   transition.ToStatus,
+			OperatorID: transition.OperatorID,
+			Comment:    transition.Comment,
+			CreatedAt:  transition.CreatedAt.Format(utils.DateTimeLayout),
+		})
+	}
+
+	utils.Success(c, ReviewHistoryResponse{
+		CourseID:    course.ID,
+		Status:      course.Status,
+		ReviewerIDs: reviewers,
+		History:     history,
+	})
+}
+
+// GetPendingReviews 获取待当前用户审核的课程（分页）
+func (ctrl *CourseReviewController) GetPendingReviews(c *gin.Context) {
+	page := utils.GetPagination(c)
+	userID, _ := c.Get("userId")
+	courses, err := ctrl.reviewService.PendingCourses(c.Request.Context(), userID.(uint))
+	if err != nil {
+		utils.InternalError(c, "查询待审核课程失败: "+err.Error())
+		return
+	}
+
+	start, end := page.Bounds(len(courses))
+	list := make([]CourseResponse, 0, end-start)
+	for i := start; i < end; i++ {
+		list = append(list, newCourseResponse(&courses[i]))
+	}
+
+	utils.SuccessPage(c, list, int64(len(courses)), page)
+}
+
+// ReviewCommentRequest 审核通过/驳回请求参数
+type ReviewCommentRequest struct {
+	Comment string `json:"comment"` // 审核意见
+}
+
+// runTransition 执行带审核意见的状态流转
+func (ctrl *CourseReviewController) runTransition(c *gin.Context, action func(ctx context.Context, courseID, userID uint, comment string) (*models.Course, error)) {
+	course, ok := findCourse(c)
+	if !ok {
+		return
+	}
+
+	var req ReviewCommentRequest
+	_ = c.ShouldBindJSON(&req)
+
+	userID, _ := c.Get("userId")
+	course, err := action(c.Request.Context(), course.ID, userID.(uint), req.Comment)
+	if err != nil {
+		handleCourseTransitionError(c, err)
+		return
+	}
+
+	utils.Success(c, newCourseResponse(course))
+}
+
+// ReviewHistoryResponse 课程审核人和状态流转记录
+type ReviewHistoryResponse struct {
+	CourseID    uint                       `json:"courseId"`
+	Status      int                        `json:"status"`
+	ReviewerIDs []uint                     `json:"reviewerIds"` // 指定的审核人，为空时由审核员角色审核
+	History     []CourseTransitionResponse `json:"history"`
+}
+
+// CourseTransitionResponse 课程状态流转记录
+type CourseTransitionResponse struct {
+	ID         uint   `json:"id"`
+	Action     string `json:"action"`
+	FromStatus int    `json:"fromStatus"`
+	ToStatus   int    `json:"toStatus"`
+	OperatorID uint   `json:"operatorId"`
+	Comment    string `json:"comment"`
+	CreatedAt  string `json:"createdAt"`
+}
+
+// handleCourseTransitionError 转换课程状态流转相关错误
+func handleCourseTransitionError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		utils.Fail(c, utils.CodeCourseNotFound, "课程不存在")
+	case errors.Is(err, services.ErrInvalidCourseTransition):
+		utils.Fail(c, utils.CodeInvalidCourseStatus, err.Error())
+	case errors.Is(err, services.ErrNotCourseReviewer):
+		utils.Forbidden(c, err.Error())
+	case errors.Is(err, services.ErrReviewCommentRequired), errors.Is(err, services.ErrInvalidCourseContent):
+		utils.BadRequest(c, err.Error())
+	default:
+		utils.InternalError(c, "操作失败: "+err.Error())
+	}
+}
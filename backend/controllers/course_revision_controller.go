@@ -0,0 +1,293 @@
+package controllers
+
+import (
+	"errors"
+	"learn-hub-backend/database"
+	"learn-hub-backend/models"
+	"learn-hub-backend/services"
+	"learn-hub-backend/utils"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+type CourseRevisionController struct {
+	revisionService *services.CourseRevisionService
+}
+
+func NewCourseRevisionController() *CourseRevisionController {
+	return &CourseRevisionController{
+		revisionService: &services.CourseRevisionService{},
+	}
+}
+
+// GetDraft 获取课程草稿
+func (ctrl *CourseRevisionController) GetDraft(c *gin.Context) {
+	course, ok := findCourse(c)
+	if !ok {
+		return
+	}
+
+	draft, err := ctrl.revisionService.GetDraft(c.Request.Context(), course.ID)
+	if err != nil {
+		ctrl.handleError(c, err)
+		return
+	}
+
+	utils.Success(c, newDraftResponse(draft))
+}
+
+// DiscardDraft 丢弃课程草稿
+func (ctrl *CourseRevisionController) DiscardDraft(c *gin.Context) {
+	course, ok := findCourse(c)
+	if !ok {
+		return
+	}
+
+	if err := ctrl.revisionService.DiscardDraft(c.Request.Context(), course.ID); err != nil {
+		ctrl.handleError(c, err)
+		return
+	}
+
+	utils.Success(c, gin.H{})
+}
+
+// DiffDraft 比较草稿与线上内容，传入 revision 参数时与指定修订版本比较
+func (ctrl *CourseRevisionController) DiffDraft(c *gin.Context) {
+	course, ok := findCourse(c)
+	if !ok {
+		return
+	}
+
+	draft, err := ctrl.revisionService.GetDraft(c.Request.Context(), course.ID)
+	if err != nil {
+		ctrl.handleError(c, err)
+		return
+	}
+
+	base := models.ContentOf(course)
+	baseRevision := course.CurrentRevision
+	if rev := c.Query("revision"); rev != "" {
+		revision, _ := strconv.Atoi(rev)
+		target, err := ctrl.revisionService.GetRevision(c.Request.Context(), course.ID, revision)
+		if err != nil {
+			utils.Fail(c, utils.CodeRevisionNotFound, "")
+			return
+		}
+		base = target.CourseContent
+		baseRevision = target.Revision
+	}
+
+	utils.Success(c, DraftDiffResponse{
+		BaseRevision:      baseRevision,
+		DraftBaseRevision: draft.BaseRevision,
+		Changes:           ctrl.revisionService.Diff(base, draft.CourseContent),
+	})
+}
+
+// PublishDraftRequest 发布草稿请求参数
+type PublishDraftRequest struct {
+	Note string `json:"note"` // 发布说明
+}
+
+// PublishDraft 将草稿发布为新的修订版本，线上课程的草稿提交审核，审核通过后生效
+func (ctrl *CourseRevisionController) PublishDraft(c *gin.Context) {
+	course, ok := findCourse(c)
+	if !ok {
+		return
+	}
+
+	var req PublishDraftRequest
+	_ = c.ShouldBindJSON(&req)
+
+	userID, _ := c.Get("userId")
+	revision, draft, err := ctrl.revisionService.PublishDraft(c.Request.Context(), course.ID, userID.(uint), req.Note)
+	if err != nil {
+		ctrl.handleError(c, err)
+		return
+	}
+
+	utils.Success(c, newPublishDraftResponse(revision, draft))
+}
+
+// GetRevisionList 获取课程修订版本列表
+func (ctrl *CourseRevisionController) GetRevisionList(c *gin.Context) {
+	course, ok := findCourse(c)
+	if !ok {
+		return
+	}
+
+	revisions, err := ctrl.revisionService.ListRevisions(c.Request.Context(), course.ID)
+	if err != nil {
+		utils.InternalError(c, "查询修订版本失败: "+err.Error())
+		return
+	}
+
+	list := make([]RevisionListItem, 0, len(revisions))
+	for _, revision := range revisions {
+		list = append(list, RevisionListItem{
+			Revision:    revision.Revision,
+			Title:       revision.Title,
+			Note:        revision.Note,
+			PublishedBy: revision.PublishedBy,
+			PublishedAt: revision.CreatedAt.Format(utils.DateTimeLayout),
+			IsCurrent:   revision.Revision == course.CurrentRevision,
+		})
+	}
+
+	utils.Success(c, list)
+}
+
+// GetRevisionDetail 获取指定修订版本内容
+func (ctrl *CourseRevisionController) GetRevisionDetail(c *gin.Context) {
+	course, ok := findCourse(c)
+	if !ok {
+		return
+	}
+
+	revisionNo, _ := strconv.Atoi(c.Param("revision"))
+	revision, err := ctrl.revisionService.GetRevision(c.Request.Context(), course.ID, revisionNo)
+	if err != nil {
+		utils.Fail(c, utils.CodeRevisionNotFound, "")
+		return
+	}
+
+	utils.Success(c, newRevisionResponse(revision))
+}
+
+// RollbackRevision 回滚到指定修订版本（生成新的修订版本，线上课程提交审核）
+func (ctrl *CourseRevisionController) RollbackRevision(c *gin.Context) {
+	course, ok := findCourse(c)
+	if !ok {
+		return
+	}
+
+	revisionNo, _ := strconv.Atoi(c.Param("revision"))
+	if _, err := ctrl.revisionService.GetRevision(c.Request.Context(), course.ID, revisionNo); err != nil {
+		utils.Fail(c, utils.CodeRevisionNotFound, "")
+		return
+	}
+
+	userID, _ := c.Get("userId")
+	revision, draft, err := ctrl.revisionService.Rollback(c.Request.Context(), course.ID, revisionNo, userID.(uint))
+	if err != nil {
+		ctrl.handleError(c, err)
+		return
+	}
+
+	utils.Success(c, newPublishDraftResponse(revision, draft))
+}
+
+// handleError 转换修订版本服务错误
+func (ctrl *CourseRevisionController) handleError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, services.ErrNoCourseDraft):
+		utils.Fail(c, utils.CodeDraftNotFound, err.Error())
+	case errors.Is(err, services.ErrInvalidCourseContent):
+		utils.BadRequest(c, err.Error())
+	case errors.Is(err, services.ErrDraftInReview), errors.Is(err, services.ErrInvalidCourseTransition):
+		utils.Fail(c, utils.CodeInvalidCourseStatus, err.Error())
+	default:
+		utils.InternalError(c, "操作失败: "+err.Error())
+	}
+}
+
+// findCourse 根据路径参数 id 获取课程
+func findCourse(c *gin.Context) (*models.Course, bool) {
+	id, _ := strconv.ParseUint(c.Param("id"), 10, 32)
+	if id == 0 {
+		utils.BadRequest(c, "课程ID不能为空")
+		return nil, false
+	}
+
+	var course models.Course
+	if err := database.WithContext(c.Request.Context()).First(&course, id).Error; err != nil {
+		utils.Fail(c, utils.CodeCourseNotFound, "课程不存在")
+		return nil, false
+	}
+	return &course, true
+}
+
+// DraftResponse 课程草稿
+type DraftResponse struct {
+	CourseID     uint `json:"courseId"`
+	BaseRevision int  `json:"baseRevision"` // 草稿基于的修订版本号
+	models.CourseContent
+	UpdatedBy   uint   `json:"updatedBy"`
+	UpdatedAt   string `json:"updatedAt"`
+	InReview    bool   `json:"inReview"`    // 是否已提交审核，审核中不能修改
+	SubmittedBy uint   `json:"submittedBy"` // 提交审核人
+	SubmittedAt string `json:"submittedAt"`
+	Note        string `json:"note"` // 发布说明
+}
+
+func newDraftResponse(draft *models.CourseDraft) DraftResponse {
+	resp := DraftResponse{
+		CourseID:      draft.CourseID,
+		BaseRevision:  draft.BaseRevision,
+		CourseContent: draft.CourseContent,
+		UpdatedBy:     draft.UpdatedBy,
+		UpdatedAt:     draft.UpdatedAt.Format(utils.DateTimeLayout),
+		InReview:      draft.InReview(),
+		Note:          draft.Note,
+	}
+	if draft.InReview() {
+		resp.SubmittedBy = draft.SubmittedBy
+		resp.SubmittedAt = draft.SubmittedAt.Format(utils.DateTimeLayout)
+	}
+	return resp
+}
+
+// PublishDraftResponse 发布草稿或回滚结果：线上课程返回提交审核的草稿，否则返回新的修订版本
+type PublishDraftResponse struct {
+	PendingReview bool              `json:"pendingReview"` // 是否已提交审核，审核通过后才生效
+	Revision      *RevisionResponse `json:"revision,omitempty"`
+	Draft         *DraftResponse    `json:"draft,omitempty"`
+}
+
+func newPublishDraftResponse(revision *models.CourseRevision, draft *models.CourseDraft) PublishDraftResponse {
+	if draft != nil {
+		resp := newDraftResponse(draft)
+		return PublishDraftResponse{PendingReview: true, Draft: &resp}
+	}
+	resp := newRevisionResponse(revision)
+	return PublishDraftResponse{Revision: &resp}
+}
+
+// RevisionResponse 修订版本内容
+type RevisionResponse struct {
+	CourseID uint   `json:"courseId"`
+	Revision int    `json:"revision"`
+	Note     string `json:"note"`
+	models.CourseContent
+	PublishedBy uint   `json:"publishedBy"`
+	PublishedAt string `json:"publishedAt"`
+}
+
+func newRevisionResponse(revision *models.CourseRevision) RevisionResponse {
+	return RevisionResponse{
+		CourseID:      revision.CourseID,
+		Revision:      revision.Revision,
+		Note:          revision.Note,
+		CourseContent: revision.CourseContent,
+		PublishedBy:   revision.PublishedBy,
+		PublishedAt:   revision.CreatedAt.Format(utils.DateTimeLayout),
+	}
+}
+
+// RevisionListItem 修订版本列表项
+type RevisionListItem struct {
+	Revision    int    `json:"revision"`
+	Title       string `json:"title"`
+	Note        string `json:"note"`
+	PublishedBy uint   `json:"publishedBy"`
+	PublishedAt string `json:"publishedAt"`
+	IsCurrent   bool   `json:"isCurrent"` // 是否为线上版本
+}
+
+// DraftDiffResponse 草稿与线上内容（或指定修订版本）的差异
+type DraftDiffResponse struct {
+	BaseRevision      int                  `json:"baseRevision"`      // 比较基准的修订版本号
+	DraftBaseRevision int                  `json:"draftBaseRevision"` // 草稿基于的修订版本号
+	Changes           []services.FieldDiff `json:"changes"`
+}
@@ -0,0 +1,81 @@
+package controllers
+
+import (
+	"errors"
+	"learn-hub-backend/services"
+	"learn-hub-backend/utils"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+type DeletionController struct {
+	deletionService *services.DeletionService
+}
+
+func NewDeletionController() *DeletionController {
+	return &DeletionController{
+		deletionService: &services.DeletionService{},
+	}
+}
+
+// GetUserImpact 预览删除用户的影响
+func (ctrl *DeletionController) GetUserImpact(c *gin.Context) {
+	ctrl.impact(c, services.RecycleKindUser)
+}
+
+// GetRoleImpact 预览删除角色的影响
+func (ctrl *DeletionController) GetRoleImpact(c *gin.Context) {
+	ctrl.impact(c, services.RecycleKindRole)
+}
+
+// GetCourseImpact 预览删除课程的影响
+func (ctrl *DeletionController) GetCourseImpact(c *gin.Context) {
+	ctrl.impact(c, services.RecycleKindCourse)
+}
+
+func (ctrl *DeletionController) impact(c *gin.Context, kind string) {
+	id, _ := strconv.ParseUint(c.Param("id"), 10, 32)
+	if id == 0 {
+		utils.BadRequest(c, "ID不能为空")
+		return
+	}
+
+	userID, _ := c.Get("userId")
+	impact, err := ctrl.deletionService.Impact(c.Request.Context(), kind, uint(id), userID.(uint))
+	if err != nil {
+		handleDeletionError(c, err)
+		return
+	}
+
+	utils.Success(c, impact)
+}
+
+// deleteOptions 解析删除参数：policy=block|cascade|reassign，targetId 为转移目标
+func deleteOptions(c *gin.Context) services.DeleteOptions {
+	targetID, _ := strconv.ParseUint(c.Query("targetId"), 10, 32)
+	userID, _ := c.Get("userId")
+	return services.DeleteOptions{
+		Policy:     c.DefaultQuery("policy", services.DeletePolicyBlock),
+		TargetID:   uint(targetID),
+		OperatorID: userID.(uint),
+	}
+}
+
+// handleDeletionError 将删除服务错误转换为响应
+func handleDeletionError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, services.ErrUserNotFound):
+		utils.Fail(c, utils.CodeUserNotFound, err.Error())
+	case errors.Is(err, services.ErrRoleNotFound):
+		utils.Fail(c, utils.CodeRoleNotFound, err.Error())
+	case errors.Is(err, services.ErrDeletionBlocked):
+		utils.Fail(c, utils.CodeInUse, err.Error())
+	case errors.Is(err, services.ErrProtectedRole), errors.Is(err, services.ErrLastAdmin), errors.Is(err, services.ErrDeleteSelf):
+		utils.Fail(c, utils.CodeProtected, err.Error())
+	case errors.Is(err, services.ErrInvalidDeletePolicy), errors.Is(err, services.ErrReassignTarget), errors.Is(err, services.ErrRecycleKind):
+		utils.BadRequest(c, err.Error())
+	default:
+		utils.InternalError(c, "删除失败: "+err.Error())
+	}
+}
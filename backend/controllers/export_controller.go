@@ -0,0 +1,193 @@
+package controllers
+
+import (
+	"fmt"
+	"learn-hub-backend/config"
+	"learn-hub-backend/models"
+	"learn-hub-backend/services"
+	"learn-hub-backend/utils"
+	"log/slog"
+	"os"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+type ExportController struct {
+	exportService *services.ExportService
+}
+
+func NewExportController() *ExportController {
+	return &ExportController{
+		exportService: &services.ExportService{},
+	}
+}
+
+// ExportCourseProgress 导出课程学习进度，筛选条件与 GetCourseProgress 相同
+func (ctrl *ExportController) ExportCourseProgress(c *gin.Context) {
+	id, _ := strconv.ParseUint(c.Param("id"), 10, 32)
+	if id == 0 {
+		utils.BadRequest(c, "课程ID不能为空")
+		return
+	}
+
+	params := services.ReportParams{ID: uint(id), Username: c.Query("username")}
+	ctrl.export(c, services.ExportCourseProgress, params, courseScope(c))
+}
+
+// ExportUserProgress 导出用户学习进度，筛选条件与 GetUserProgress 相同
+func (ctrl *ExportController) ExportUserProgress(c *gin.Context) {
+	id, _ := strconv.ParseUint(c.Param("id"), 10, 32)
+	if id == 0 {
+		utils.BadRequest(c, "用户ID不能为空")
+		return
+	}
+
+	ctrl.export(c, services.ExportUserProgress, services.ReportParams{ID: uint(id)}, courseScope(c))
+}
+
+// ExportUsers 导出用户列表，筛选条件与 GetUserList 相同
+func (ctrl *ExportController) ExportUsers(c *gin.Context) {
+	params := services.ReportParams{Username: c.Query("username"), Status: c.Query("status")}
+	ctrl.export(c, services.ExportUsers, params, nil)
+}
+
+// ExportCourses 导出课程列表，筛选条件与 GetCourseList 相同
+func (ctrl *ExportController) ExportCourses(c *gin.Context) {
+	params := services.ReportParams{Title: c.Query("title"), Status: c.Query("status")}
+	ctrl.export(c, services.ExportCourses, params, courseScope(c))
+}
+
+// GetJobList 获取当前用户的导出任务
+func (ctrl *ExportController) GetJobList(c *gin.Context) {
+	userID, _ := c.Get("userId")
+	jobs, err := ctrl.exportService.ListJobs(c.Request.Context(), userID.(uint), 50)
+	if err != nil {
+		utils.InternalError(c, "查询导出任务失败: "+err.Error())
+		return
+	}
+
+	list := make([]ExportJobResponse, 0, len(jobs))
+	for i := range jobs {
+		list = append(list, newExportJobResponse(&jobs[i]))
+	}
+
+	utils.Success(c, list)
+}
+
+// GetJob 获取导出任务状态
+func (ctrl *ExportController) GetJob(c *gin.Context) {
+	job, ok := ctrl.findJob(c)
+	if !ok {
+		return
+	}
+
+	utils.Success(c, newExportJobResponse(job))
+}
+
+// DownloadJob 下载已完成的导出文件
+func (ctrl *ExportController) DownloadJob(c *gin.Context) {
+	job, ok := ctrl.findJob(c)
+	if !ok {
+		return
+	}
+
+	filePath, err := ctrl.exportService.JobFile(job)
+	if err != nil {
+		utils.Fail(c, utils.CodeExportNotReady, err.Error())
+		return
+	}
+	if _, err := os.Stat(filePath); err != nil {
+		utils.Fail(c, utils.CodeExportFileNotFound, "导出文件不存在")
+		return
+	}
+
+	c.FileAttachment(filePath, job.FileName)
+}
+
+// export 数据量小时直接流式下载；超过阈值或 async=true 时创建后台任务并返回任务ID
+func (ctrl *ExportController) export(c *gin.Context, kind string, params services.ReportParams, scope *services.CourseScope) {
+	format := c.DefaultQuery("format", services.ExportFormatCSV)
+	if err := ctrl.exportService.Validate(kind, format); err != nil {
+		utils.BadRequest(c, err.Error())
+		return
+	}
+
+	count, err := ctrl.exportService.Count(c.Request.Context(), kind, params, scope)
+	if err != nil {
+		utils.InternalError(c, "统计导出数据失败: "+err.Error())
+		return
+	}
+
+	if c.Query("async") == "true" || count > config.AppConfig.ExportAsyncThreshold {
+		userID, _ := c.Get("userId")
+		job, err := ctrl.exportService.CreateJob(c.Request.Context(), userID.(uint), kind, format, params)
+		if err != nil {
+			utils.InternalError(c, "创建导出任务失败: "+err.Error())
+			return
+		}
+		utils.Success(c, newExportJobResponse(job))
+		return
+	}
+
+	contentType := "text/csv; charset=utf-8"
+	if format == services.ExportFormatXLSX {
+		contentType = "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
+	}
+	c.Header("Content-Type", contentType)
+	c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="%s"`, ctrl.exportService.FileName(kind, format)))
+
+	// 已开始写入响应体，出错时只能记录日志
+	if _, err := ctrl.exportService.Export(c.Request.Context(), kind, format, params, scope, c.Writer); err != nil {
+		slog.ErrorContext(c.Request.Context(), "导出失败", "kind", kind, "error", err)
+	}
+}
+
+// findJob 根据路径参数 id 获取当前用户的导出任务
+func (ctrl *ExportController) findJob(c *gin.Context) (*models.ExportJob, bool) {
+	id, _ := strconv.ParseUint(c.Param("id"), 10, 32)
+	if id == 0 {
+		utils.BadRequest(c, "任务ID不能为空")
+		return nil, false
+	}
+
+	userID, _ := c.Get("userId")
+	job, err := ctrl.exportService.GetJob(c.Request.Context(), userID.(uint), uint(id))
+	if err != nil {
+		utils.Fail(c, utils.CodeExportJobNotFound, "")
+		return nil, false
+	}
+	return job, true
+}
+
+// ExportJobResponse 导出任务
+type ExportJobResponse struct {
+	ID          uint   `json:"id"`
+	Kind        string `json:"kind"`   // course-progress、user-progress、users 或 courses
+	Format      string `json:"format"` // csv 或 xlsx
+	Status      string `json:"status"` // pending、running、done 或 failed
+	RowCount    int64  `json:"rowCount"`
+	FileName    string `json:"fileName"`
+	Error       string `json:"error"` // 失败原因
+	CreatedAt   string `json:"createdAt"`
+	FinishedAt  string `json:"finishedAt"`  // 未完成时为空字符串
+	DownloadURL string `json:"downloadUrl"` // 完成后的下载地址，未完成时为空字符串
+}
+
+func newExportJobResponse(job *models.ExportJob) ExportJobResponse {
+	resp := ExportJobResponse{
+		ID:         job.ID,
+		Kind:       job.Kind,
+		Format:     job.Format,
+		Status:     job.Status,
+		RowCount:   job.RowCount,
+		FileName:   job.FileName,
+		Error:      job.Error,
+		CreatedAt:  job.CreatedAt.Format(utils.DateTimeLayout),
+		FinishedAt: formatOptionalTime(job.FinishedAt),
+	}
+	if job.Status == models.ExportJobDone {
+		resp.DownloadURL = fmt.Sprintf("/api/export/jobs/%d/download", job.ID)
+	}
+	return resp
+}
@@ -0,0 +1,73 @@
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sync/atomic"
+	"time"
+
+	"learn-hub-backend/database"
+
+	"github.com/gin-gonic/gin"
+)
+
+// shuttingDown 服务正在退出，就绪检查返回失败使负载均衡不再转发新请求
+var shuttingDown atomic.Bool
+
+// MarkShuttingDown 标记服务正在退出
+func MarkShuttingDown() {
+	shuttingDown.Store(true)
+}
+
+// readyCheckTimeout 就绪检查中数据库检查的超时时间
+const readyCheckTimeout = 2 * time.Second
+
+type HealthController struct{}
+
+func NewHealthController() *HealthController {
+	return &HealthController{}
+}
+
+// Healthz 存活检查：进程能处理请求即返回成功，不检查依赖
+func (ctrl *HealthController) Healthz(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{"status": "ok"})
+}
+
+// Readyz 就绪检查：数据库可连接且迁移已全部执行时返回 200，否则返回 503 及失败原因
+func (ctrl *HealthController) Readyz(c *gin.Context) {
+	checks := gin.H{}
+	ready := true
+	fail := func(name string, reason string) {
+		checks[name] = reason
+		ready = false
+	}
+
+	if shuttingDown.Load() {
+		fail("server", "服务正在退出")
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), readyCheckTimeout)
+	defer cancel()
+	if err := database.Ping(ctx); err != nil {
+		fail("database", err.Error())
+	} else {
+		checks["database"] = "ok"
+
+		pending, err := database.PendingMigrations()
+		switch {
+		case err != nil:
+			fail("migrations", err.Error())
+		case pending > 0:
+			fail("migrations", fmt.Sprintf("%d 个迁移未执行", pending))
+		default:
+			checks["migrations"] = "ok"
+		}
+	}
+
+	if !ready {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"status": "unavailable", "checks": checks})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"status": "ok", "checks": checks})
+}
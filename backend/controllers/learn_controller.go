@@ -1,19 +1,28 @@
 package controllers
 
 import (
+	"context"
 	"learn-hub-backend/database"
 	"learn-hub-backend/models"
+	"learn-hub-backend/services"
 	"learn-hub-backend/utils"
+	"log/slog"
 	"strconv"
 	"time"
 
 	"github.com/gin-gonic/gin"
 )
 
-type LearnController struct{}
+type LearnController struct {
+	eventService    *services.LearningEventService
+	revisionService *services.CourseRevisionService
+}
 
 func NewLearnController() *LearnController {
-	return &LearnController{}
+	return &LearnController{
+		eventService:    &services.LearningEventService{},
+		revisionService: &services.CourseRevisionService{},
+	}
 }
 
 // GetCourses 获取可学习课程列表（员工端）
@@ -24,24 +33,14 @@ func (ctrl *LearnController) GetCourses(c *gin.Context) {
 		return
 	}
 
-	current, _ := strconv.Atoi(c.DefaultQuery("current", "1"))
-	pageSize, _ := strconv.Atoi(c.DefaultQuery("pageSize", "20"))
+	page := utils.GetPagination(c)
 	title := c.Query("title")
 
-	if current < 1 {
-		current = 1
-	}
-	if pageSize < 1 {
-		pageSize = 20
-	}
-
-	offset := (current - 1) * pageSize
-
-	// 构建查询（只查询已发布的课程）
-	query := database.DB.Model(&models.Course{}).Where("status = ?", 1)
+	// 构建查询（只查询已发布且在上下架时间窗口内的课程）
+	query := database.WithContext(c.Request.Context()).Model(&models.Course{}).Scopes(services.AvailableCourses(time.Now()))
 
 	if title != "" {
-		query = query.Where("title LIKE ?", "%"+title+"%")
+		query = query.Scopes(database.Contains("title", title))
 	}
 
 	// 查询总数
@@ -50,12 +49,12 @@ func (ctrl *LearnController) GetCourses(c *gin.Context) {
 
 	// 查询列表
 	var courses []models.Course
-	query.Offset(offset).Limit(pageSize).Order("sort_order ASC, created_at DESC").Find(&courses)
+	query.Offset(page.Offset()).Limit(page.PageSize).Order("sort_order ASC, created_at DESC").Find(&courses)
 
 	// 获取用户的学习记录
 	userIDUint := userID.(uint)
 	var records []models.CourseRecord
-	database.DB.Where("user_id = ?", userIDUint).Find(&records)
+	database.WithContext(c.Request.Context()).Where("user_id = ?", userIDUint).Find(&records)
 
 	// 构建课程ID到记录的映射
 	recordMap := make(map[uint]*models.CourseRecord)
@@ -63,38 +62,20 @@ func (ctrl *LearnController) GetCourses(c *gin.Context) {
 		recordMap[records[i].CourseID] = &records[i]
 	}
 
-	// 转换为前端格式
-	var list []map[string]interface{}
+	list := make([]LearnCourseItem, 0, len(courses))
 	for _, course := range courses {
-		record, hasRecord := recordMap[course.ID]
-
-		item := map[string]interface{}{
-			"id":          course.ID,
-			"title":       course.Title,
-			"description": course.Description,
-			"coverImage":  course.CoverImage,
-			"contentType": course.ContentType,
-			"duration":    course.Duration,
-		}
-
-		if hasRecord {
-			item["progress"] = record.Progress
-			item["isCompleted"] = record.IsCompleted
-			item["lastStudyAt"] = record.LastStudyAt.Format("2006-01-02 15:04:05")
-		} else {
-			item["progress"] = 0
-			item["isCompleted"] = false
-			item["lastStudyAt"] = ""
-		}
-
-		list = append(list, item)
+		list = append(list, LearnCourseItem{
+			ID:            course.ID,
+			Title:         course.Title,
+			Description:   course.Description,
+			CoverImage:    course.CoverImage,
+			ContentType:   course.ContentType,
+			Duration:      course.Duration,
+			LearningState: newLearningState(recordMap[course.ID]),
+		})
 	}
 
-	c.JSON(200, gin.H{
-		"data":    list,
-		"total":   total,
-		"success": true,
-	})
+	utils.SuccessPage(c, list, total, page)
 }
 
 // GetCourseDetail 获取课程详情（员工端）
@@ -105,49 +86,44 @@ func (ctrl *LearnController) GetCourseDetail(c *gin.Context) {
 		return
 	}
 
-	id, _ := strconv.ParseUint(c.Param("id"), 10, 32)
-	if id == 0 {
-		utils.BadRequest(c, "课程ID不能为空")
-		return
-	}
-
-	var course models.Course
-	if err := database.DB.Where("status = ?", 1).First(&course, id).Error; err != nil {
-		utils.Error(c, 200, "COURSE_NOT_FOUND", "课程不存在或已下架")
+	course, ok := findAvailableCourse(c)
+	if !ok {
 		return
 	}
+	id := course.ID
 
 	// 获取用户的学习记录
 	userIDUint := userID.(uint)
-	var record models.CourseRecord
-	hasRecord := database.DB.Where("user_id = ? AND course_id = ?", userIDUint, id).First(&record).Error == nil
-
-	result := map[string]interface{}{
-		"id":          course.ID,
-		"title":       course.Title,
-		"description": course.Description,
-		"coverImage":  course.CoverImage,
-		"contentType": course.ContentType,
-		"videoUrl":    course.VideoURL,
-		"textContent": course.TextContent,
-		"duration":    course.Duration,
-	}
-
-	if hasRecord {
-		result["progress"] = record.Progress
-		result["duration"] = record.Duration
-		result["isCompleted"] = record.IsCompleted
-		result["lastStudyAt"] = record.LastStudyAt.Format("2006-01-02 15:04:05")
-	} else {
-		result["progress"] = 0
-		result["duration"] = 0
-		result["isCompleted"] = false
-		result["lastStudyAt"] = ""
+	var record *models.CourseRecord
+	var found models.CourseRecord
+	if database.WithContext(c.Request.Context()).Where("user_id = ? AND course_id = ?", userIDUint, id).First(&found).Error == nil {
+		record = &found
+	}
+
+	result := LearnCourseDetail{
+		ID:            course.ID,
+		Title:         course.Title,
+		Description:   course.Description,
+		CoverImage:    course.CoverImage,
+		ContentType:   course.ContentType,
+		VideoURL:      course.VideoURL,
+		TextContent:   course.TextContent,
+		LearningState: newLearningState(record),
+	}
+	if record != nil {
+		result.Duration = record.Duration
 	}
 
 	utils.Success(c, result)
 }
 
+// UpdateProgressRequest 更新学习进度请求参数
+type UpdateProgressRequest struct {
+	Progress int `json:"progress" binding:"required"` // 0-100
+	Duration int `json:"duration"`                    // 已学习时长（秒）
+	Position int `json:"position"`                    // 当前播放位置（秒）
+}
+
 // UpdateProgress 更新学习进度
 func (ctrl *LearnController) UpdateProgress(c *gin.Context) {
 	userID, exists := c.Get("userId")
@@ -156,17 +132,13 @@ func (ctrl *LearnController) UpdateProgress(c *gin.Context) {
 		return
 	}
 
-	id, _ := strconv.ParseUint(c.Param("id"), 10, 32)
-	if id == 0 {
-		utils.BadRequest(c, "课程ID不能为空")
+	course, ok := findAvailableCourse(c)
+	if !ok {
 		return
 	}
+	id := course.ID
 
-	var req struct {
-		Progress int `json:"progress" binding:"required"` // 0-100
-		Duration int `json:"duration"`                    // 已学习时长（秒）
-	}
-
+	var req UpdateProgressRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
 		utils.BadRequest(c, "参数错误: "+err.Error())
 		return
@@ -181,13 +153,14 @@ func (ctrl *LearnController) UpdateProgress(c *gin.Context) {
 
 	// 查找或创建学习记录
 	var record models.CourseRecord
-	err := database.DB.Where("user_id = ? AND course_id = ?", userIDUint, id).First(&record).Error
+	err := database.WithContext(c.Request.Context()).Where("user_id = ? AND course_id = ?", userIDUint, id).First(&record).Error
+	wasCompleted := err == nil && record.IsCompleted
 
 	if err != nil {
 		// 创建新记录
 		record = models.CourseRecord{
 			UserID:      userIDUint,
-			CourseID:    uint(id),
+			CourseID:    id,
 			Progress:    req.Progress,
 			Duration:    req.Duration,
 			IsCompleted: req.Progress >= 100,
@@ -196,11 +169,13 @@ func (ctrl *LearnController) UpdateProgress(c *gin.Context) {
 		if record.IsCompleted {
 			now := time.Now()
 			record.CompletedAt = &now
+			record.CompletedRevision = ctrl.revisionService.CurrentRevision(c.Request.Context(), id)
 		}
-		if err := database.DB.Create(&record).Error; err != nil {
+		if err := database.WithContext(c.Request.Context()).Create(&record).Error; err != nil {
 			utils.InternalError(c, "创建学习记录失败: "+err.Error())
 			return
 		}
+		ctrl.recordEvent(c.Request.Context(), &record, models.VerbStarted, req.Position)
 	} else {
 		// 更新记录
 		record.Progress = req.Progress
@@ -214,19 +189,28 @@ func (ctrl *LearnController) UpdateProgress(c *gin.Context) {
 			record.IsCompleted = true
 			now := time.Now()
 			record.CompletedAt = &now
+			record.CompletedRevision = ctrl.revisionService.CurrentRevision(c.Request.Context(), id)
 		}
 
-		if err := database.DB.Save(&record).Error; err != nil {
+		if err := database.WithContext(c.Request.Context()).Save(&record).Error; err != nil {
 			utils.InternalError(c, "更新学习记录失败: "+err.Error())
 			return
 		}
 	}
 
-	utils.Success(c, map[string]interface{}{
-		"progress":    record.Progress,
-		"duration":    record.Duration,
-		"isCompleted": record.IsCompleted,
-	})
+	ctrl.recordEvent(c.Request.Context(), &record, models.VerbProgressed, req.Position)
+	if record.IsCompleted && !wasCompleted {
+		ctrl.recordEvent(c.Request.Context(), &record, models.VerbCompleted, req.Position)
+	}
+
+	utils.Success(c, newLearningRecordResponse(&record))
+}
+
+// CompleteCourseRequest 标记课程完成请求参数
+type CompleteCourseRequest struct {
+	Progress int `json:"progress"` // 可选，默认100
+	Duration int `json:"duration"` // 可选
+	Position int `json:"position"` // 可选，当前播放位置（秒）
 }
 
 // CompleteCourse 标记课程完成
@@ -237,17 +221,13 @@ func (ctrl *LearnController) CompleteCourse(c *gin.Context) {
 		return
 	}
 
-	id, _ := strconv.ParseUint(c.Param("id"), 10, 32)
-	if id == 0 {
-		utils.BadRequest(c, "课程ID不能为空")
+	course, ok := findAvailableCourse(c)
+	if !ok {
 		return
 	}
+	id := course.ID
 
-	var req struct {
-		Progress int `json:"progress"` // 可选，默认100
-		Duration int `json:"duration"` // 可选
-	}
-
+	var req CompleteCourseRequest
 	if err := c.ShouldBindJSON(&req); err == nil {
 		// 如果提供了参数，使用提供的值
 	} else {
@@ -259,21 +239,23 @@ func (ctrl *LearnController) CompleteCourse(c *gin.Context) {
 
 	// 查找或创建学习记录
 	var record models.CourseRecord
-	err := database.DB.Where("user_id = ? AND course_id = ?", userIDUint, id).First(&record).Error
+	err := database.WithContext(c.Request.Context()).Where("user_id = ? AND course_id = ?", userIDUint, id).First(&record).Error
+	wasCompleted := err == nil && record.IsCompleted
 
 	now := time.Now()
 	if err != nil {
 		// 创建新记录
 		record = models.CourseRecord{
-			UserID:      userIDUint,
-			CourseID:    uint(id),
-			Progress:    req.Progress,
-			Duration:    req.Duration,
-			IsCompleted: true,
-			CompletedAt: &now,
-			LastStudyAt: now,
+			UserID:            userIDUint,
+			CourseID:          id,
+			Progress:          req.Progress,
+			Duration:          req.Duration,
+			IsCompleted:       true,
+			CompletedAt:       &now,
+			LastStudyAt:       now,
+			CompletedRevision: ctrl.revisionService.CurrentRevision(c.Request.Context(), id),
 		}
-		if err := database.DB.Create(&record).Error; err != nil {
+		if err := database.WithContext(c.Request.Context()).Create(&record).Error; err != nil {
 			utils.InternalError(c, "创建学习记录失败: "+err.Error())
 			return
 		}
@@ -286,19 +268,172 @@ func (ctrl *LearnController) CompleteCourse(c *gin.Context) {
 		record.IsCompleted = true
 		if record.CompletedAt == nil {
 			record.CompletedAt = &now
+			record.CompletedRevision = ctrl.revisionService.CurrentRevision(c.Request.Context(), id)
 		}
 		record.LastStudyAt = now
 
-		if err := database.DB.Save(&record).Error; err != nil {
+		if err := database.WithContext(c.Request.Context()).Save(&record).Error; err != nil {
 			utils.InternalError(c, "更新学习记录失败: "+err.Error())
 			return
 		}
 	}
 
-	utils.Success(c, map[string]interface{}{
-		"progress":    record.Progress,
-		"duration":    record.Duration,
-		"isCompleted": record.IsCompleted,
-		"completedAt": record.CompletedAt.Format("2006-01-02 15:04:05"),
-	})
+	// 重复标记完成不再产生完成事件，避免重复计入完成数
+	if !wasCompleted {
+		ctrl.recordEvent(c.Request.Context(), &record, models.VerbCompleted, req.Position)
+	}
+
+	utils.Success(c, newLearningRecordResponse(&record))
+}
+
+// RecordEventRequest 上报学习事件请求参数
+type RecordEventRequest struct {
+	Verb         string `json:"verb" binding:"required"` // started, paused, seeked
+	Position     int    `json:"position"`                // 当前播放位置（秒）
+	FromPosition *int   `json:"fromPosition"`            // 拖动前的播放位置（秒），仅 seeked
+}
+
+// RecordEvent 上报学习事件（开始、暂停、拖动）
+func (ctrl *LearnController) RecordEvent(c *gin.Context) {
+	userID, exists := c.Get("userId")
+	if !exists {
+		utils.Unauthorized(c, "未登录")
+		return
+	}
+
+	var req RecordEventRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.BadRequest(c, "参数错误: "+err.Error())
+		return
+	}
+
+	// 进度和完成事件由 progress/complete 接口产生，不允许直接上报
+	if req.Verb != models.VerbStarted && req.Verb != models.VerbPaused && req.Verb != models.VerbSeeked {
+		utils.BadRequest(c, "事件类型错误，必须是 started、paused 或 seeked")
+		return
+	}
+	if req.Verb == models.VerbSeeked && req.FromPosition == nil {
+		utils.BadRequest(c, "拖动事件必须提供 fromPosition")
+		return
+	}
+
+	course, ok := findAvailableCourse(c)
+	if !ok {
+		return
+	}
+	id := course.ID
+
+	// 附带当前学习记录的进度快照
+	var record models.CourseRecord
+	database.WithContext(c.Request.Context()).Where("user_id = ? AND course_id = ?", userID.(uint), id).First(&record)
+	record.UserID = userID.(uint)
+	record.CourseID = id
+
+	event := models.LearningEvent{
+		UserID:       record.UserID,
+		CourseID:     record.CourseID,
+		Verb:         req.Verb,
+		Position:     req.Position,
+		FromPosition: req.FromPosition,
+		Progress:     record.Progress,
+		Duration:     record.Duration,
+	}
+	if err := ctrl.eventService.Record(c.Request.Context(), &event); err != nil {
+		utils.InternalError(c, "记录学习事件失败: "+err.Error())
+		return
+	}
+
+	utils.Success(c, newLearningEventResponse(&event))
+}
+
+// findAvailableCourse 根据路径参数 id 获取员工可学习的课程（已发布且在上下架时间窗口内）
+func findAvailableCourse(c *gin.Context) (*models.Course, bool) {
+	id, _ := strconv.ParseUint(c.Param("id"), 10, 32)
+	if id == 0 {
+		utils.BadRequest(c, "课程ID不能为空")
+		return nil, false
+	}
+
+	var course models.Course
+	if err := database.WithContext(c.Request.Context()).Scopes(services.AvailableCourses(time.Now())).First(&course, id).Error; err != nil {
+		utils.Fail(c, utils.CodeCourseNotFound, "")
+		return nil, false
+	}
+	return &course, true
+}
+
+// recordEvent 根据学习记录追加学习事件，失败只记录日志，不影响进度更新
+func (ctrl *LearnController) recordEvent(ctx context.Context, record *models.CourseRecord, verb string, position int) {
+	event := models.LearningEvent{
+		UserID:   record.UserID,
+		CourseID: record.CourseID,
+		Verb:     verb,
+		Position: position,
+		Progress: record.Progress,
+		Duration: record.Duration,
+	}
+	if err := ctrl.eventService.Record(ctx, &event); err != nil {
+		slog.ErrorContext(ctx, "记录学习事件失败", "user_id", record.UserID, "course_id", record.CourseID, "verb", verb, "error", err)
+	}
+}
+
+// LearningState 当前用户的学习状态，没有学习记录时为零值
+type LearningState struct {
+	Progress    int    `json:"progress"` // 0-100
+	IsCompleted bool   `json:"isCompleted"`
+	LastStudyAt string `json:"lastStudyAt"` // 没有学习记录时为空字符串
+}
+
+func newLearningState(record *models.CourseRecord) LearningState {
+	if record == nil {
+		return LearningState{}
+	}
+	return LearningState{
+		Progress:    record.Progress,
+		IsCompleted: record.IsCompleted,
+		LastStudyAt: record.LastStudyAt.Format(utils.DateTimeLayout),
+	}
+}
+
+// LearnCourseItem 可学习课程列表项
+type LearnCourseItem struct {
+	ID          uint   `json:"id"`
+	Title       string `json:"title"`
+	Description string `json:"description"`
+	CoverImage  string `json:"coverImage"`
+	ContentType int    `json:"contentType"`
+	Duration    int    `json:"duration"` // 课程时长（秒）
+	LearningState
+}
+
+// LearnCourseDetail 课程详情和当前用户的学习状态
+type LearnCourseDetail struct {
+	ID          uint   `json:"id"`
+	Title       string `json:"title"`
+	Description string `json:"description"`
+	CoverImage  string `json:"coverImage"`
+	ContentType int    `json:"contentType"`
+	VideoURL    string `json:"videoUrl"`
+	TextContent string `json:"textContent"`
+	Duration    int    `json:"duration"` // 当前用户已学习时长（秒）
+	LearningState
+}
+
+// LearningRecordResponse 更新后的学习记录
+type LearningRecordResponse struct {
+	Progress          int    `json:"progress"`
+	Duration          int    `json:"duration"` // 已学习时长（秒）
+	IsCompleted       bool   `json:"isCompleted"`
+	CompletedAt       string `json:"completedAt"`       // 未完成时为空字符串
+	CompletedRevision int    `json:"completedRevision"` // 完成时课程的修订版本号
+}
+
+func newLearningRecordResponse(record *models.CourseRecord) LearningRecordResponse {
+	return LearningRecordResponse{
+		Progress:          record.Progress,
+		Duration:          record.Duration,
+		IsCompleted:       record.IsCompleted,
+		CompletedAt:       formatOptionalTime(record.CompletedAt),
+		CompletedRevision: record.CompletedRevision,
+	}
 }
@@ -0,0 +1,125 @@
+package controllers
+
+import (
+	"learn-hub-backend/models"
+	"learn-hub-backend/services"
+	"learn-hub-backend/utils"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+type LearningEventController struct {
+	eventService *services.LearningEventService
+}
+
+func NewLearningEventController() *LearningEventController {
+	return &LearningEventController{
+		eventService: &services.LearningEventService{},
+	}
+}
+
+// GetEventList 查询学习事件（管理员）
+func (ctrl *LearningEventController) GetEventList(c *gin.Context) {
+	filter, ok := parseLearningEventFilter(c)
+	if !ok {
+		return
+	}
+
+	page := utils.GetPagination(c)
+	filter.Offset = page.Offset()
+	filter.Limit = page.PageSize
+
+	events, total, err := ctrl.eventService.Query(c.Request.Context(), filter)
+	if err != nil {
+		utils.InternalError(c, "查询学习事件失败: "+err.Error())
+		return
+	}
+
+	list := make([]LearningEventResponse, 0, len(events))
+	for i := range events {
+		list = append(list, newLearningEventResponse(&events[i]))
+	}
+
+	utils.SuccessPage(c, list, total, page)
+}
+
+// GetEventSummary 学习事件汇总（管理员）
+func (ctrl *LearningEventController) GetEventSummary(c *gin.Context) {
+	filter, ok := parseLearningEventFilter(c)
+	if !ok {
+		return
+	}
+
+	summary, err := ctrl.eventService.Summary(c.Request.Context(), filter)
+	if err != nil {
+		utils.InternalError(c, "统计学习事件失败: "+err.Error())
+		return
+	}
+
+	utils.Success(c, summary)
+}
+
+// parseLearningEventFilter 解析查询参数：userId、courseId、verb、startTime、endTime
+func parseLearningEventFilter(c *gin.Context) (services.LearningEventFilter, bool) {
+	var filter services.LearningEventFilter
+
+	userID, _ := strconv.ParseUint(c.Query("userId"), 10, 32)
+	courseID, _ := strconv.ParseUint(c.Query("courseId"), 10, 32)
+	filter.UserID = uint(userID)
+	filter.CourseID = uint(courseID)
+
+	if verb := c.Query("verb"); verb != "" {
+		if !models.IsValidLearningVerb(verb) {
+			utils.BadRequest(c, "不支持的事件类型: "+verb)
+			return filter, false
+		}
+		filter.Verb = verb
+	}
+
+	if startTime := c.Query("startTime"); startTime != "" {
+		t, err := utils.ParseDateTime(startTime)
+		if err != nil {
+			utils.BadRequest(c, "startTime "+err.Error())
+			return filter, false
+		}
+		filter.StartTime = &t
+	}
+	if endTime := c.Query("endTime"); endTime != "" {
+		t, err := utils.ParseDateTime(endTime)
+		if err != nil {
+			utils.BadRequest(c, "endTime "+err.Error())
+			return filter, false
+		}
+		filter.EndTime = &t
+	}
+
+	return filter, true
+}
+
+// LearningEventResponse 学习事件
+type LearningEventResponse struct {
+	ID           uint   `json:"id"`
+	UserID       uint   `json:"userId"`
+	CourseID     uint   `json:"courseId"`
+	Verb         string `json:"verb"`
+	Position     int    `json:"position"`               // 事件发生时的播放位置（秒）
+	FromPosition *int   `json:"fromPosition,omitempty"` // 拖动前的播放位置（秒），仅 seeked
+	Progress     int    `json:"progress"`
+	Duration     int    `json:"duration"`
+	OccurredAt   string `json:"occurredAt"`
+}
+
+func newLearningEventResponse(event *models.LearningEvent) LearningEventResponse {
+	return LearningEventResponse{
+		ID:           event.ID,
+		UserID:       event.UserID,
+		CourseID:     event.CourseID,
+		Verb:         event.Verb,
+		Position:     event.Position,
+		FromPosition: event.FromPosition,
+		Progress:     event.Progress,
+		Duration:     event.Duration,
+		OccurredAt:   event.OccurredAt.Format(utils.DateTimeLayout),
+	}
+}
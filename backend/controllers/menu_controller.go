@@ -25,7 +25,7 @@ func (ctrl *MenuController) GetMenuList(c *gin.Context) {
 		return
 	}
 
-	menus, err := ctrl.userService.GetUserMenus(userID.(uint))
+	menus, err := ctrl.userService.GetUserMenus(c.Request.Context(), userID.(uint))
 	if err != nil {
 		utils.InternalError(c, "获取菜单失败")
 		return
@@ -34,6 +34,13 @@ func (ctrl *MenuController) GetMenuList(c *gin.Context) {
 	utils.Success(c, menus)
 }
 
+// UserPermissionsResponse 用户权限
+type UserPermissionsResponse struct {
+	Access      []string `json:"access"`      // 前端 access.ts 使用的权限标识，如 canadmin
+	Roles       []string `json:"roles"`       // 角色编码
+	Permissions []string `json:"permissions"` // 细粒度权限（暂未使用）
+}
+
 // GetUserPermissions 获取用户权限列表
 func (ctrl *MenuController) GetUserPermissions(c *gin.Context) {
 	userID, exists := c.Get("userId")
@@ -42,27 +49,27 @@ func (ctrl *MenuController) GetUserPermissions(c *gin.Context) {
 		return
 	}
 
-	user, err := ctrl.userService.GetByID(userID.(uint))
+	user, err := ctrl.userService.GetByID(c.Request.Context(), userID.(uint))
 	if err != nil {
-		utils.Error(c, 200, "USER_NOT_FOUND", "用户不存在")
+		utils.Fail(c, utils.CodeUserNotFound, "")
 		return
 	}
 
 	// 收集权限
-	var accessList []string
+	accessList := []string{}
 	if user.Access != "" {
 		accessList = append(accessList, "can"+user.Access) // 转换为前端格式
 	}
 
-	var roles []string
+	roles := make([]string, 0, len(user.Roles))
 	for _, role := range user.Roles {
 		roles = append(roles, role.Code)
 	}
 
-	utils.Success(c, gin.H{
-		"access":      accessList,
-		"roles":       roles,
-		"permissions": []string{}, // 可以扩展细粒度权限
+	utils.Success(c, UserPermissionsResponse{
+		Access:      accessList,
+		Roles:       roles,
+		Permissions: []string{}, // 可以扩展细粒度权限
 	})
 }
 
@@ -0,0 +1,55 @@
+package controllers
+
+import (
+	"net/http"
+	"sync"
+
+	"learn-hub-backend/openapi"
+
+	"github.com/gin-gonic/gin"
+)
+
+// swaggerUIVersion Swagger UI 页面加载的 swagger-ui-dist 版本
+const swaggerUIVersion = "5.9.0"
+
+// swaggerUIPage 加载 /api/openapi.json 的 Swagger UI 页面
+const swaggerUIPage = `<!DOCTYPE html>
+<html lang="zh-CN">
+<head>
+  <meta charset="utf-8">
+  <title>Learn Hub API</title>
+  <link rel="stylesheet" href="https://unpkg.com/swagger-ui-dist@` + swaggerUIVersion + `/swagger-ui.css">
+</head>
+<body>
+  <div id="swagger-ui"></div>
+  <script src="https://unpkg.com/swagger-ui-dist@` + swaggerUIVersion + `/swagger-ui-bundle.js"></script>
+  <script>
+    window.ui = SwaggerUIBundle({ url: "openapi.json", dom_id: "#swagger-ui", persistAuthorization: true });
+  </script>
+</body>
+</html>`
+
+type OpenAPIController struct {
+	build func() *openapi.Document
+
+	once sync.Once
+	doc  *openapi.Document
+}
+
+// NewOpenAPIController build 在第一次请求时调用，此时全部路由已注册
+func NewOpenAPIController(build func() *openapi.Document) *OpenAPIController {
+	return &OpenAPIController{build: build}
+}
+
+// GetSpec 输出 OpenAPI 文档
+func (ctrl *OpenAPIController) GetSpec(c *gin.Context) {
+	ctrl.once.Do(func() {
+		ctrl.doc = ctrl.build()
+	})
+	c.JSON(http.StatusOK, ctrl.doc)
+}
+
+// SwaggerUI 接口文档页面
+func (ctrl *OpenAPIController) SwaggerUI(c *gin.Context) {
+	c.Data(http.StatusOK, "text/html; charset=utf-8", []byte(swaggerUIPage))
+}
@@ -1,8 +1,8 @@
 package controllers
 
 import (
-	"learn-hub-backend/database"
 	"learn-hub-backend/models"
+	"learn-hub-backend/services"
 	"learn-hub-backend/utils"
 	"strconv"
 
@@ -15,7 +15,7 @@ func NewProgressController() *ProgressController {
 	return &ProgressController{}
 }
 
-// GetCourseProgress 查看课程学习进度（管理员）
+// GetCourseProgress 查看课程学习进度（管理员和课程讲师）
 func (ctrl *ProgressController) GetCourseProgress(c *gin.Context) {
 	id, _ := strconv.ParseUint(c.Param("id"), 10, 32)
 	if id == 0 {
@@ -23,31 +23,12 @@ func (ctrl *ProgressController) GetCourseProgress(c *gin.Context) {
 		return
 	}
 
-	current, _ := strconv.Atoi(c.DefaultQuery("current", "1"))
-	pageSize, _ := strconv.Atoi(c.DefaultQuery("pageSize", "20"))
+	page := utils.GetPagination(c)
 	username := c.Query("username")
 
-	if current < 1 {
-		current = 1
-	}
-	if pageSize < 1 {
-		pageSize = 20
-	}
-
-	offset := (current - 1) * pageSize
-
-	// 构建查询
-	query := database.DB.Model(&models.CourseRecord{}).
-		Where("course_id = ?", id)
-
-	if username != "" {
-		// 如果有用户名搜索，使用 JOIN
-		query = query.Joins("JOIN sys_user ON sys_course_record.user_id = sys_user.id").
-			Where("sys_user.username LIKE ?", "%"+username+"%")
-	}
-
-	// 预加载用户信息
-	query = query.Preload("User")
+	// 构建查询（与导出共用筛选条件）
+	params := services.ReportParams{ID: uint(id), Username: username}
+	query := services.CourseProgressQuery(c.Request.Context(), params, courseScope(c)).Preload("User")
 
 	// 查询总数
 	var total int64
@@ -55,38 +36,23 @@ func (ctrl *ProgressController) GetCourseProgress(c *gin.Context) {
 
 	// 查询列表
 	var records []models.CourseRecord
-	query.Offset(offset).Limit(pageSize).Order("last_study_at DESC").Find(&records)
-
-	// 转换为前端格式
-	var list []map[string]interface{}
-	for _, record := range records {
-		item := map[string]interface{}{
-			"userId":      record.UserID,
-			"username":    record.User.Username,
-			"name":        record.User.Name,
-			"progress":    record.Progress,
-			"duration":    record.Duration,
-			"isCompleted": record.IsCompleted,
-			"lastStudyAt": record.LastStudyAt.Format("2006-01-02 15:04:05"),
-		}
-
-		if record.CompletedAt != nil {
-			item["completedAt"] = record.CompletedAt.Format("2006-01-02 15:04:05")
-		} else {
-			item["completedAt"] = ""
-		}
-
-		list = append(list, item)
+	query.Offset(page.Offset()).Limit(page.PageSize).Order("last_study_at DESC").Find(&records)
+
+	list := make([]CourseProgressItem, 0, len(records))
+	for i := range records {
+		record := &records[i]
+		list = append(list, CourseProgressItem{
+			UserID:         record.UserID,
+			Username:       record.User.Username,
+			Name:           record.User.Name,
+			ProgressRecord: newProgressRecord(record),
+		})
 	}
 
-	c.JSON(200, gin.H{
-		"data":    list,
-		"total":   total,
-		"success": true,
-	})
+	utils.SuccessPage(c, list, total, page)
 }
 
-// GetUserProgress 查看用户学习进度（管理员）
+// GetUserProgress 查看用户学习进度（管理员；讲师只能看到自己课程的记录）
 func (ctrl *ProgressController) GetUserProgress(c *gin.Context) {
 	id, _ := strconv.ParseUint(c.Param("id"), 10, 32)
 	if id == 0 {
@@ -94,21 +60,10 @@ func (ctrl *ProgressController) GetUserProgress(c *gin.Context) {
 		return
 	}
 
-	current, _ := strconv.Atoi(c.DefaultQuery("current", "1"))
-	pageSize, _ := strconv.Atoi(c.DefaultQuery("pageSize", "20"))
-
-	if current < 1 {
-		current = 1
-	}
-	if pageSize < 1 {
-		pageSize = 20
-	}
-
-	offset := (current - 1) * pageSize
+	page := utils.GetPagination(c)
 
 	// 构建查询
-	query := database.DB.Model(&models.CourseRecord{}).
-		Where("user_id = ?", id).
+	query := services.UserProgressQuery(c.Request.Context(), services.ReportParams{ID: uint(id)}, courseScope(c)).
 		Preload("Course")
 
 	// 查询总数
@@ -117,32 +72,51 @@ func (ctrl *ProgressController) GetUserProgress(c *gin.Context) {
 
 	// 查询列表
 	var records []models.CourseRecord
-	query.Offset(offset).Limit(pageSize).Order("last_study_at DESC").Find(&records)
-
-	// 转换为前端格式
-	var list []map[string]interface{}
-	for _, record := range records {
-		item := map[string]interface{}{
-			"courseId":    record.CourseID,
-			"courseTitle": record.Course.Title,
-			"progress":    record.Progress,
-			"duration":    record.Duration,
-			"isCompleted": record.IsCompleted,
-			"lastStudyAt": record.LastStudyAt.Format("2006-01-02 15:04:05"),
-		}
-
-		if record.CompletedAt != nil {
-			item["completedAt"] = record.CompletedAt.Format("2006-01-02 15:04:05")
-		} else {
-			item["completedAt"] = ""
-		}
-
-		list = append(list, item)
+	query.Offset(page.Offset()).Limit(page.PageSize).Order("last_study_at DESC").Find(&records)
+
+	list := make([]UserProgressItem, 0, len(records))
+	for i := range records {
+		record := &records[i]
+		list = append(list, UserProgressItem{
+			CourseID:       record.CourseID,
+			CourseTitle:    record.Course.Title,
+			ProgressRecord: newProgressRecord(record),
+		})
+	}
+
+	utils.SuccessPage(c, list, total, page)
+}
+
+// ProgressRecord 学习记录
+type ProgressRecord struct {
+	Progress    int    `json:"progress"` // 0-100
+	Duration    int    `json:"duration"` // 已学习时长（秒）
+	IsCompleted bool   `json:"isCompleted"`
+	LastStudyAt string `json:"lastStudyAt"`
+	CompletedAt string `json:"completedAt"` // 未完成时为空字符串
+}
+
+func newProgressRecord(record *models.CourseRecord) ProgressRecord {
+	return ProgressRecord{
+		Progress:    record.Progress,
+		Duration:    record.Duration,
+		IsCompleted: record.IsCompleted,
+		LastStudyAt: record.LastStudyAt.Format(utils.DateTimeLayout),
+		CompletedAt: formatOptionalTime(record.CompletedAt),
 	}
+}
+
+// CourseProgressItem 课程学习进度列表项（按学员）
+type CourseProgressItem struct {
+	UserID   uint   `json:"userId"`
+	Username string `json:"username"`
+	Name     string `json:"name"`
+	ProgressRecord
+}
 
-	c.JSON(200, gin.H{
-		"data":    list,
-		"total":   total,
-		"success": true,
-	})
+// UserProgressItem 用户学习进度列表项（按课程）
+type UserProgressItem struct {
+	CourseID    uint   `json:"courseId"`
+	CourseTitle string `json:"courseTitle"`
+	ProgressRecord
 }
@@ -0,0 +1,122 @@
+package controllers
+
+import (
+	"errors"
+	"learn-hub-backend/services"
+	"learn-hub-backend/utils"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+type RecycleController struct {
+	recycleService *services.RecycleService
+}
+
+func NewRecycleController() *RecycleController {
+	return &RecycleController{
+		recycleService: &services.RecycleService{},
+	}
+}
+
+// GetRecycleList 获取回收站列表，kind 为 user/role/course/rule，为空时查询全部
+func (ctrl *RecycleController) GetRecycleList(c *gin.Context) {
+	page := utils.GetPagination(c)
+	entries, total, err := ctrl.recycleService.List(c.Request.Context(), c.Query("kind"), page.Offset(), page.PageSize)
+	if err != nil {
+		handleRecycleError(c, "查询回收站失败", err)
+		return
+	}
+
+	list := make([]RecycleEntryResponse, 0, len(entries))
+	for i := range entries {
+		entry := &entries[i]
+		list = append(list, RecycleEntryResponse{
+			ID:         entry.RecordID,
+			Kind:       entry.Kind,
+			Name:       entry.Name,
+			Keys:       ctrl.recycleService.EntryKeys(entry),
+			OperatorID: entry.OperatorID,
+			DeletedAt:  entry.CreatedAt.Format(utils.DateTimeLayout),
+		})
+	}
+
+	utils.SuccessPage(c, list, total, page)
+}
+
+// RestoreRecycleRequest 恢复数据请求参数，唯一键冲突时可在 keys 中为冲突字段指定新值
+type RestoreRecycleRequest struct {
+	Keys map[string]string `json:"keys"`
+}
+
+// RestoreRecycle 恢复数据；唯一键冲突时可在 keys 中为冲突字段指定新值后重试
+func (ctrl *RecycleController) RestoreRecycle(c *gin.Context) {
+	id, _ := strconv.ParseUint(c.Param("id"), 10, 32)
+	if id == 0 {
+		utils.BadRequest(c, "数据ID不能为空")
+		return
+	}
+
+	var req RestoreRecycleRequest
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			utils.BadRequest(c, "参数错误: "+err.Error())
+			return
+		}
+	}
+
+	if err := ctrl.recycleService.Restore(c.Request.Context(), c.Param("kind"), uint(id), req.Keys); err != nil {
+		var conflict *services.RestoreConflictError
+		if errors.As(err, &conflict) {
+			utils.FailWithData(c, utils.CodeRestoreConflict, conflict.Error(), RestoreConflictResponse{Conflicts: conflict.Fields})
+			return
+		}
+		handleRecycleError(c, "恢复失败", err)
+		return
+	}
+
+	utils.Success(c, gin.H{})
+}
+
+// PurgeRecycle 彻底删除回收站中的数据
+func (ctrl *RecycleController) PurgeRecycle(c *gin.Context) {
+	id, _ := strconv.ParseUint(c.Param("id"), 10, 32)
+	if id == 0 {
+		utils.BadRequest(c, "数据ID不能为空")
+		return
+	}
+
+	if err := ctrl.recycleService.Purge(c.Request.Context(), c.Param("kind"), uint(id)); err != nil {
+		handleRecycleError(c, "彻底删除失败", err)
+		return
+	}
+
+	utils.Success(c, gin.H{})
+}
+
+// handleRecycleError 将回收站服务错误转换为响应
+func handleRecycleError(c *gin.Context, action string, err error) {
+	switch {
+	case errors.Is(err, services.ErrRecycleKind):
+		utils.BadRequest(c, err.Error())
+	case errors.Is(err, services.ErrRecycleNotFound):
+		utils.Fail(c, utils.CodeRecycleNotFound, err.Error())
+	default:
+		utils.InternalError(c, action+": "+err.Error())
+	}
+}
+
+// RecycleEntryResponse 回收站中的数据
+type RecycleEntryResponse struct {
+	ID         uint              `json:"id"`   // 原数据 ID
+	Kind       string            `json:"kind"` // user、role、course 或 rule
+	Name       string            `json:"name"`
+	Keys       map[string]string `json:"keys"` // 唯一字段的原值
+	OperatorID uint              `json:"operatorId"`
+	DeletedAt  string            `json:"deletedAt"`
+}
+
+// RestoreConflictResponse 恢复时唯一字段冲突的详情
+type RestoreConflictResponse struct {
+	Conflicts map[string]string `json:"conflicts"` // 冲突字段及其原值
+}
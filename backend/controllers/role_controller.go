@@ -1,41 +1,39 @@
 package controllers
 
 import (
+	"errors"
 	"learn-hub-backend/database"
 	"learn-hub-backend/models"
+	"learn-hub-backend/services"
 	"learn-hub-backend/utils"
 	"strconv"
 
 	"github.com/gin-gonic/gin"
 )
 
-type RoleController struct{}
+type RoleController struct {
+	roleService     *services.RoleService
+	deletionService *services.DeletionService
+}
 
 func NewRoleController() *RoleController {
-	return &RoleController{}
+	return &RoleController{
+		roleService:     &services.RoleService{},
+		deletionService: &services.DeletionService{},
+	}
 }
 
 // GetRoleList 获取角色列表
 func (ctrl *RoleController) GetRoleList(c *gin.Context) {
-	current, _ := strconv.Atoi(c.DefaultQuery("current", "1"))
-	pageSize, _ := strconv.Atoi(c.DefaultQuery("pageSize", "20"))
+	page := utils.GetPagination(c)
 	code := c.Query("code")
 	status := c.Query("status")
 
-	if current < 1 {
-		current = 1
-	}
-	if pageSize < 1 {
-		pageSize = 20
-	}
-
-	offset := (current - 1) * pageSize
-
 	// 构建查询
-	query := database.DB.Model(&models.Role{})
+	query := database.WithContext(c.Request.Context()).Model(&models.Role{})
 
 	if code != "" {
-		query = query.Where("code LIKE ?", "%"+code+"%")
+		query = query.Scopes(database.Contains("code", code))
 	}
 	if status != "" {
 		query = query.Where("status = ?", status)
@@ -47,89 +45,59 @@ func (ctrl *RoleController) GetRoleList(c *gin.Context) {
 
 	// 查询列表
 	var roles []models.Role
-	query.Offset(offset).Limit(pageSize).Order("created_at DESC").Find(&roles)
-
-	// 转换为前端格式
-	var list []map[string]interface{}
-	for _, role := range roles {
-		// 统计用户数
-		userCount := database.DB.Model(&role).Association("Users").Count()
-
-		// 统计菜单数
-		menuCount := database.DB.Model(&role).Association("Menus").Count()
-
-		list = append(list, map[string]interface{}{
-			"id":          role.ID,
-			"code":        role.Code,
-			"name":        role.Name,
-			"description": role.Description,
-			"status":      role.Status,
-			"userCount":   userCount,
-			"menuCount":   menuCount,
-			"createdAt":  role.CreatedAt.Format("2006-01-02 15:04:05"),
-			"updatedAt":  role.UpdatedAt.Format("2006-01-02 15:04:05"),
+	query.Offset(page.Offset()).Limit(page.PageSize).Order("created_at DESC").Find(&roles)
+
+	list := make([]RoleListItem, 0, len(roles))
+	for i := range roles {
+		role := &roles[i]
+		list = append(list, RoleListItem{
+			RoleResponse: newRoleResponse(role),
+			UserCount:    database.WithContext(c.Request.Context()).Model(role).Association("Users").Count(),
+			MenuCount:    database.WithContext(c.Request.Context()).Model(role).Association("Menus").Count(),
 		})
 	}
 
-	c.JSON(200, gin.H{
-		"data":    list,
-		"total":   total,
-		"success": true,
-	})
+	utils.SuccessPage(c, list, total, page)
+}
+
+// CreateRoleRequest 创建角色请求参数
+type CreateRoleRequest struct {
+	Code        string `json:"code" binding:"required"`
+	Name        string `json:"name" binding:"required"`
+	Description string `json:"description"`
+	Status      int    `json:"status"`
+	MenuIds     []uint `json:"menuIds"`
 }
 
 // CreateRole 创建角色
 func (ctrl *RoleController) CreateRole(c *gin.Context) {
-	var req struct {
-		Code        string `json:"code" binding:"required"`
-		Name        string `json:"name" binding:"required"`
-		Description string `json:"description"`
-		Status      int    `json:"status"`
-		MenuIds     []uint `json:"menuIds"`
-	}
-
+	var req CreateRoleRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
 		utils.BadRequest(c, "参数错误: "+err.Error())
 		return
 	}
 
-	// 检查角色代码是否已存在
-	var existRole models.Role
-	if err := database.DB.Where("code = ?", req.Code).First(&existRole).Error; err == nil {
-		utils.Error(c, 200, "ROLE_EXISTS", "角色代码已存在")
-		return
-	}
-
-	// 创建角色
-	role := models.Role{
+	role, err := ctrl.roleService.CreateRole(c.Request.Context(), services.RoleInput{
 		Code:        req.Code,
 		Name:        req.Name,
 		Description: req.Description,
-		Status:      req.Status,
-	}
-	if role.Status == 0 {
-		role.Status = 1
-	}
-
-	if err := database.DB.Create(&role).Error; err != nil {
-		utils.InternalError(c, "创建失败: "+err.Error())
+		Status:      &req.Status,
+		MenuIDs:     req.MenuIds,
+	})
+	if err != nil {
+		handleRoleError(c, "创建失败", err)
 		return
 	}
 
-	// 分配菜单
-	if len(req.MenuIds) > 0 {
-		var menus []models.Menu
-		database.DB.Where("id IN ?", req.MenuIds).Find(&menus)
-		database.DB.Model(&role).Association("Menus").Append(&menus)
-	}
+	utils.Success(c, newRoleResponse(role))
+}
 
-	utils.Success(c, map[string]interface{}{
-		"id":          role.ID,
-		"code":        role.Code,
-		"name":        role.Name,
-		"description": role.Description,
-		"status":      role.Status,
-	})
+// UpdateRoleRequest 更新角色请求参数
+type UpdateRoleRequest struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	Status      *int   `json:"status"`
+	MenuIds     []uint `json:"menuIds"`
 }
 
 // UpdateRole 更新角色
@@ -140,57 +108,27 @@ func (ctrl *RoleController) UpdateRole(c *gin.Context) {
 		return
 	}
 
-	var role models.Role
-	if err := database.DB.First(&role, id).Error; err != nil {
-		utils.Error(c, 200, "ROLE_NOT_FOUND", "角色不存在")
-		return
-	}
-
-	var req struct {
-		Name        string `json:"name"`
-		Description string `json:"description"`
-		Status      *int   `json:"status"`
-		MenuIds     []uint `json:"menuIds"`
-	}
-
+	var req UpdateRoleRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
 		utils.BadRequest(c, "参数错误: "+err.Error())
 		return
 	}
 
-	// 更新字段
-	if req.Name != "" {
-		role.Name = req.Name
-	}
-	if req.Description != "" {
-		role.Description = req.Description
-	}
-	if req.Status != nil {
-		role.Status = *req.Status
-	}
-
-	if err := database.DB.Save(&role).Error; err != nil {
-		utils.InternalError(c, "更新失败: "+err.Error())
+	role, err := ctrl.roleService.UpdateRole(c.Request.Context(), uint(id), services.RoleInput{
+		Name:        req.Name,
+		Description: req.Description,
+		Status:      req.Status,
+		MenuIDs:     req.MenuIds,
+	})
+	if err != nil {
+		handleRoleError(c, "更新失败", err)
 		return
 	}
 
-	// 更新菜单
-	if req.MenuIds != nil {
-		var menus []models.Menu
-		database.DB.Where("id IN ?", req.MenuIds).Find(&menus)
-		database.DB.Model(&role).Association("Menus").Replace(&menus)
-	}
-
-	utils.Success(c, map[string]interface{}{
-		"id":          role.ID,
-		"code":        role.Code,
-		"name":        role.Name,
-		"description": role.Description,
-		"status":      role.Status,
-	})
+	utils.Success(c, newRoleResponse(role))
 }
 
-// DeleteRole 删除角色
+// DeleteRole 删除角色（放入回收站，可恢复），policy 为 block/cascade/reassign，reassign 时 targetId 为替代角色
 func (ctrl *RoleController) DeleteRole(c *gin.Context) {
 	id, _ := strconv.ParseUint(c.Param("id"), 10, 32)
 	if id == 0 {
@@ -198,8 +136,8 @@ func (ctrl *RoleController) DeleteRole(c *gin.Context) {
 		return
 	}
 
-	if err := database.DB.Delete(&models.Role{}, id).Error; err != nil {
-		utils.InternalError(c, "删除失败: "+err.Error())
+	if err := ctrl.deletionService.DeleteRole(c.Request.Context(), uint(id), deleteOptions(c)); err != nil {
+		handleDeletionError(c, err)
 		return
 	}
 
@@ -209,22 +147,76 @@ func (ctrl *RoleController) DeleteRole(c *gin.Context) {
 // GetAllMenus 获取所有菜单（用于角色分配菜单）
 func (ctrl *RoleController) GetAllMenus(c *gin.Context) {
 	var menus []models.Menu
-	database.DB.Where("status = ?", 1).Order("parent_id, sort_order").Find(&menus)
+	database.WithContext(c.Request.Context()).Where("status = ?", 1).Order("parent_id, sort_order").Find(&menus)
 
-	// 构建菜单树
-	var list []map[string]interface{}
+	// 前端按 parentId 构建菜单树
+	list := make([]MenuOption, 0, len(menus))
 	for _, menu := range menus {
-		list = append(list, map[string]interface{}{
-			"id":        menu.ID,
-			"parentId":  menu.ParentID,
-			"name":      menu.Name,
-			"path":      menu.Path,
-			"component": menu.Component,
-			"icon":      menu.Icon,
-			"access":    menu.Access,
+		list = append(list, MenuOption{
+			ID:        menu.ID,
+			ParentID:  menu.ParentID,
+			Name:      menu.Name,
+			Path:      menu.Path,
+			Component: menu.Component,
+			Icon:      menu.Icon,
+			Access:    menu.Access,
 		})
 	}
 
 	utils.Success(c, list)
 }
 
+// handleRoleError 将角色服务错误转换为响应
+func handleRoleError(c *gin.Context, action string, err error) {
+	switch {
+	case errors.Is(err, services.ErrRoleExists):
+		utils.Fail(c, utils.CodeRoleExists, err.Error())
+	case errors.Is(err, services.ErrRoleNotFound):
+		utils.Fail(c, utils.CodeRoleNotFound, err.Error())
+	case errors.Is(err, services.ErrMenuNotFound):
+		utils.Fail(c, utils.CodeMenuNotFound, err.Error())
+	default:
+		utils.InternalError(c, action+": "+err.Error())
+	}
+}
+
+// RoleResponse 角色信息
+type RoleResponse struct {
+	ID          uint   `json:"id"`
+	Code        string `json:"code"`
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	Status      int    `json:"status"`
+	CreatedAt   string `json:"createdAt"`
+	UpdatedAt   string `json:"updatedAt"`
+}
+
+func newRoleResponse(role *models.Role) RoleResponse {
+	return RoleResponse{
+		ID:          role.ID,
+		Code:        role.Code,
+		Name:        role.Name,
+		Description: role.Description,
+		Status:      role.Status,
+		CreatedAt:   role.CreatedAt.Format(utils.DateTimeLayout),
+		UpdatedAt:   role.UpdatedAt.Format(utils.DateTimeLayout),
+	}
+}
+
+// RoleListItem 角色列表项
+type RoleListItem struct {
+	RoleResponse
+	UserCount int64 `json:"userCount"`
+	MenuCount int64 `json:"menuCount"`
+}
+
+// MenuOption 角色分配菜单时的菜单选项
+type MenuOption struct {
+	ID        uint   `json:"id"`
+	ParentID  uint   `json:"parentId"`
+	Name      string `json:"name"`
+	Path      string `json:"path"`
+	Component string `json:"component"`
+	Icon      string `json:"icon"`
+	Access    string `json:"access"`
+}
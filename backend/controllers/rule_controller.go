@@ -1,109 +1,94 @@
 package controllers
 
 import (
+	"errors"
 	"learn-hub-backend/database"
 	"learn-hub-backend/models"
+	"learn-hub-backend/services"
 	"learn-hub-backend/utils"
-	"strconv"
 
 	"github.com/gin-gonic/gin"
 )
 
-type RuleController struct{}
+type RuleController struct {
+	recycleService *services.RecycleService
+}
 
 func NewRuleController() *RuleController {
-	return &RuleController{}
+	return &RuleController{
+		recycleService: &services.RecycleService{},
+	}
 }
 
 // GetRuleList 获取规则列表
 func (ctrl *RuleController) GetRuleList(c *gin.Context) {
-	// 获取分页参数
-	current, _ := strconv.Atoi(c.DefaultQuery("current", "1"))
-	pageSize, _ := strconv.Atoi(c.DefaultQuery("pageSize", "20"))
-
-	if current < 1 {
-		current = 1
-	}
-	if pageSize < 1 {
-		pageSize = 20
-	}
-
-	// 计算偏移量
-	offset := (current - 1) * pageSize
+	page := utils.GetPagination(c)
 
 	// 查询总数
 	var total int64
-	database.DB.Model(&models.Rule{}).Count(&total)
+	database.WithContext(c.Request.Context()).Model(&models.Rule{}).Count(&total)
 
 	// 查询列表
 	var rules []models.Rule
-	database.DB.Offset(offset).Limit(pageSize).Order("created_at DESC").Find(&rules)
-
-	// 转换为前端格式
-	var list []map[string]interface{}
-	for _, rule := range rules {
-		list = append(list, map[string]interface{}{
-			"key":       rule.ID,
-			"name":      rule.Name,
-			"desc":      rule.Desc,
-			"status":    rule.Status,
-			"callNo":    rule.CallNo,
-			"owner":     rule.Owner,
-			"avatar":    rule.Avatar,
-			"href":      rule.Href,
-			"disabled":  rule.Disabled,
-			"progress":  rule.Progress,
-			"updatedAt": rule.UpdatedAt.Format("2006-01-02 15:04:05"),
-			"createdAt": rule.CreatedAt.Format("2006-01-02 15:04:05"),
-		})
+	database.WithContext(c.Request.Context()).Offset(page.Offset()).Limit(page.PageSize).Order("created_at DESC").Find(&rules)
+
+	list := make([]RuleResponse, 0, len(rules))
+	for i := range rules {
+		list = append(list, newRuleResponse(&rules[i]))
 	}
 
-	// 前端期望的格式：RuleList = { data, total, success }
-	// 注意：不使用 utils.Success，因为前端期望的格式不同
-	c.JSON(200, gin.H{
-		"data":    list,
-		"total":   total,
-		"success": true,
-	})
+	utils.SuccessPage(c, list, total, page)
+}
+
+// RuleRequest 规则操作请求参数（Ant Design Pro 示例页面的格式），规则字段可放在 data 中
+type RuleRequest struct {
+	Method string    `json:"method"` // post（默认，创建）、update 或 delete
+	Key    uint      `json:"key"`    // 规则ID，也可放在 data.key
+	Name   string    `json:"name"`
+	Desc   string    `json:"desc"`
+	Data   *RuleData `json:"data"`
+}
+
+// RuleData 规则字段
+type RuleData struct {
+	Key    uint    `json:"key"`
+	Name   string  `json:"name"`
+	Desc   *string `json:"desc"`
+	Status *int    `json:"status"` // 0-关闭，1-运行中，2-已上线，3-异常
+}
+
+// key 请求中的规则ID
+func (req *RuleRequest) key() uint {
+	if req.Key == 0 && req.Data != nil {
+		return req.Data.Key
+	}
+	return req.Key
 }
 
-// CreateOrUpdateRule 创建或更新规则
+// CreateOrUpdateRule 创建、更新或删除规则
 func (ctrl *RuleController) CreateOrUpdateRule(c *gin.Context) {
-	var req map[string]interface{}
+	var req RuleRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
 		utils.BadRequest(c, "参数错误: "+err.Error())
 		return
 	}
 
-	// 获取 method
-	method, _ := req["method"].(string)
+	method := req.Method
 	if method == "" {
 		method = "post" // 默认创建
 	}
 
 	switch method {
 	case "post":
-		// 创建规则
-		var rule models.Rule
-		
-		// 从 data 字段或直接获取数据
-		if data, ok := req["data"].(map[string]interface{}); ok {
-			if name, ok := data["name"].(string); ok {
-				rule.Name = name
-			}
-			if desc, ok := data["desc"].(string); ok {
-				rule.Desc = desc
+		// 创建规则，字段取自 data，没有 data 时取自请求本身
+		rule := models.Rule{Name: req.Name, Desc: req.Desc}
+		if req.Data != nil {
+			rule = models.Rule{Name: req.Data.Name}
+			if req.Data.Desc != nil {
+				rule.Desc = *req.Data.Desc
 			}
-			if status, ok := data["status"].(float64); ok {
-				rule.Status = int(status)
-			}
-		} else {
-			// 直接绑定
-			if name, ok := req["name"].(string); ok {
-				rule.Name = name
-			}
-			if desc, ok := req["desc"].(string); ok {
-				rule.Desc = desc
+			if req.Data.Status != nil {
+				rule.Status = *req.Data.Status
 			}
 		}
 
@@ -115,92 +100,59 @@ func (ctrl *RuleController) CreateOrUpdateRule(c *gin.Context) {
 			rule.Status = 1
 		}
 
-		if err := database.DB.Create(&rule).Error; err != nil {
+		if err := database.WithContext(c.Request.Context()).Create(&rule).Error; err != nil {
 			utils.InternalError(c, "创建失败: "+err.Error())
 			return
 		}
 
-		utils.Success(c, map[string]interface{}{
-			"key":       rule.ID,
-			"name":      rule.Name,
-			"desc":      rule.Desc,
-			"status":    rule.Status,
-			"callNo":    rule.CallNo,
-			"updatedAt": rule.UpdatedAt.Format("2006-01-02 15:04:05"),
-			"createdAt": rule.CreatedAt.Format("2006-01-02 15:04:05"),
-		})
+		utils.Success(c, newRuleResponse(&rule))
 
 	case "update":
-		// 更新规则
-		var rule models.Rule
-		var key uint
-
-		// 获取 key
-		if k, ok := req["key"].(float64); ok {
-			key = uint(k)
-		} else if data, ok := req["data"].(map[string]interface{}); ok {
-			if k, ok := data["key"].(float64); ok {
-				key = uint(k)
-			}
-		}
-
+		key := req.key()
 		if key == 0 {
 			utils.BadRequest(c, "缺少规则ID")
 			return
 		}
 
-		if err := database.DB.First(&rule, key).Error; err != nil {
-			utils.Error(c, 200, "RULE_NOT_FOUND", "规则不存在")
+		var rule models.Rule
+		if err := database.WithContext(c.Request.Context()).First(&rule, key).Error; err != nil {
+			utils.Fail(c, utils.CodeRuleNotFound, "")
 			return
 		}
 
 		// 更新字段
-		if data, ok := req["data"].(map[string]interface{}); ok {
-			if name, ok := data["name"].(string); ok && name != "" {
-				rule.Name = name
+		if req.Data != nil {
+			if req.Data.Name != "" {
+				rule.Name = req.Data.Name
 			}
-			if desc, ok := data["desc"].(string); ok {
-				rule.Desc = desc
+			if req.Data.Desc != nil {
+				rule.Desc = *req.Data.Desc
 			}
-			if status, ok := data["status"].(float64); ok {
-				rule.Status = int(status)
+			if req.Data.Status != nil {
+				rule.Status = *req.Data.Status
 			}
 		}
 
-		if err := database.DB.Save(&rule).Error; err != nil {
+		if err := database.WithContext(c.Request.Context()).Save(&rule).Error; err != nil {
 			utils.InternalError(c, "更新失败: "+err.Error())
 			return
 		}
 
-		utils.Success(c, map[string]interface{}{
-			"key":       rule.ID,
-			"name":      rule.Name,
-			"desc":      rule.Desc,
-			"status":    rule.Status,
-			"callNo":    rule.CallNo,
-			"updatedAt": rule.UpdatedAt.Format("2006-01-02 15:04:05"),
-			"createdAt": rule.CreatedAt.Format("2006-01-02 15:04:05"),
-		})
+		utils.Success(c, newRuleResponse(&rule))
 
 	case "delete":
-		// 删除规则
-		var key uint
-
-		// 获取 key
-		if k, ok := req["key"].(float64); ok {
-			key = uint(k)
-		} else if data, ok := req["data"].(map[string]interface{}); ok {
-			if k, ok := data["key"].(float64); ok {
-				key = uint(k)
-			}
-		}
-
+		key := req.key()
 		if key == 0 {
 			utils.BadRequest(c, "缺少规则ID")
 			return
 		}
 
-		if err := database.DB.Delete(&models.Rule{}, key).Error; err != nil {
+		userID, _ := c.Get("userId")
+		if err := ctrl.recycleService.Delete(nil, services.RecycleKindRule, key, userID.(uint)); err != nil {
+			if errors.Is(err, services.ErrRecycleRecordNotFound) {
+				utils.Fail(c, utils.CodeRuleNotFound, "")
+				return
+			}
 			utils.InternalError(c, "删除失败: "+err.Error())
 			return
 		}
@@ -212,3 +164,35 @@ func (ctrl *RuleController) CreateOrUpdateRule(c *gin.Context) {
 	}
 }
 
+// RuleResponse 规则信息
+type RuleResponse struct {
+	Key       uint   `json:"key"`
+	Name      string `json:"name"`
+	Desc      string `json:"desc"`
+	Status    int    `json:"status"`
+	CallNo    int    `json:"callNo"`
+	Owner     string `json:"owner"`
+	Avatar    string `json:"avatar"`
+	Href      string `json:"href"`
+	Disabled  bool   `json:"disabled"`
+	Progress  int    `json:"progress"`
+	UpdatedAt string `json:"updatedAt"`
+	CreatedAt string `json:"createdAt"`
+}
+
+func newRuleResponse(rule *models.Rule) RuleResponse {
+	return RuleResponse{
+		Key:       rule.ID,
+		Name:      rule.Name,
+		Desc:      rule.Desc,
+		Status:    rule.Status,
+		CallNo:    rule.CallNo,
+		Owner:     rule.Owner,
+		Avatar:    rule.Avatar,
+		Href:      rule.Href,
+		Disabled:  rule.Disabled,
+		Progress:  rule.Progress,
+		UpdatedAt: rule.UpdatedAt.Format(utils.DateTimeLayout),
+		CreatedAt: rule.CreatedAt.Format(utils.DateTimeLayout),
+	}
+}
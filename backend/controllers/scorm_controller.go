@@ -0,0 +1,236 @@
+package controllers
+
+import (
+	"errors"
+	"learn-hub-backend/database"
+	"learn-hub-backend/models"
+	"learn-hub-backend/services"
+	"learn-hub-backend/utils"
+	"net/http"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+type ScormController struct {
+	scormService  *services.ScormService
+	userService   *services.UserService
+	accessService *services.CourseAccessService
+}
+
+func NewScormController() *ScormController {
+	return &ScormController{
+		scormService:  &services.ScormService{},
+		userService:   &services.UserService{},
+		accessService: &services.CourseAccessService{},
+	}
+}
+
+// ImportPackage 导入 SCORM 课件包（管理员和讲师）
+// 表单字段：file 为 zip 文件；courseId 可选，为空时新建草稿课程
+func (ctrl *ScormController) ImportPackage(c *gin.Context) {
+	fileHeader, err := c.FormFile("file")
+	if err != nil {
+		utils.BadRequest(c, "请上传 SCORM 课件包(zip)")
+		return
+	}
+
+	courseID, _ := strconv.ParseUint(c.PostForm("courseId"), 10, 32)
+	scope := courseScope(c)
+	if courseID > 0 {
+		ok, err := ctrl.accessService.CanManage(c.Request.Context(), scope, uint(courseID))
+		if err != nil {
+			utils.Fail(c, utils.CodeCourseNotFound, "课程不存在")
+			return
+		}
+		if !ok {
+			utils.Forbidden(c, "无权管理该课程")
+			return
+		}
+	}
+
+	file, err := fileHeader.Open()
+	if err != nil {
+		utils.BadRequest(c, "读取上传文件失败")
+		return
+	}
+	defer file.Close()
+
+	pkg, err := ctrl.scormService.ImportPackage(c.Request.Context(), file, fileHeader.Size, uint(courseID), scope.UserID)
+	if err != nil {
+		if errors.Is(err, services.ErrScormInvalidPackage) {
+			utils.BadRequest(c, err.Error())
+			return
+		}
+		utils.InternalError(c, "导入失败: "+err.Error())
+		return
+	}
+
+	utils.Success(c, ScormPackageResponse{
+		ID:        pkg.ID,
+		CourseID:  pkg.CourseID,
+		Version:   pkg.Version,
+		Title:     pkg.Title,
+		EntryHref: pkg.EntryHref,
+		FileSize:  pkg.FileSize,
+		LaunchURL: ctrl.scormService.LaunchURL(pkg),
+	})
+}
+
+// ServeAsset 提供课件包内的静态文件
+func (ctrl *ScormController) ServeAsset(c *gin.Context) {
+	id, _ := strconv.ParseUint(c.Param("packageId"), 10, 32)
+	if id == 0 {
+		c.Status(http.StatusNotFound)
+		return
+	}
+
+	pkg, err := ctrl.scormService.GetPackage(c.Request.Context(), uint(id))
+	if err != nil {
+		c.Status(http.StatusNotFound)
+		return
+	}
+
+	filePath, ok := ctrl.scormService.AssetPath(pkg, c.Param("filepath"))
+	if !ok {
+		c.Status(http.StatusNotFound)
+		return
+	}
+	if info, err := os.Stat(filePath); err != nil || info.IsDir() {
+		c.Status(http.StatusNotFound)
+		return
+	}
+
+	c.File(filePath)
+}
+
+// Initialize 获取课件启动信息和运行时数据（员工端，对应 LMSInitialize/Initialize）
+func (ctrl *ScormController) Initialize(c *gin.Context) {
+	userID, exists := c.Get("userId")
+	if !exists {
+		utils.Unauthorized(c, "未登录")
+		return
+	}
+
+	pkg, ok := ctrl.publishedPackage(c)
+	if !ok {
+		return
+	}
+
+	user, err := ctrl.userService.GetByID(c.Request.Context(), userID.(uint))
+	if err != nil {
+		utils.Fail(c, utils.CodeUserNotFound, "")
+		return
+	}
+
+	values, err := ctrl.scormService.Initialize(c.Request.Context(), user, pkg)
+	if err != nil {
+		utils.InternalError(c, "获取运行时数据失败: "+err.Error())
+		return
+	}
+
+	utils.Success(c, ScormSessionResponse{
+		PackageID: pkg.ID,
+		Version:   pkg.Version,
+		LaunchURL: ctrl.scormService.LaunchURL(pkg),
+		Values:    values,
+	})
+}
+
+// ScormCommitRequest 保存课件运行时数据请求参数
+type ScormCommitRequest struct {
+	Values map[string]string `json:"values" binding:"required"`
+	Finish bool              `json:"finish"` // 是否结束会话
+}
+
+// Commit 保存课件运行时数据（员工端，对应 LMSCommit/Commit 和 LMSFinish/Terminate）
+func (ctrl *ScormController) Commit(c *gin.Context) {
+	userID, exists := c.Get("userId")
+	if !exists {
+		utils.Unauthorized(c, "未登录")
+		return
+	}
+
+	var req ScormCommitRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.BadRequest(c, "参数错误: "+err.Error())
+		return
+	}
+
+	pkg, ok := ctrl.publishedPackage(c)
+	if !ok {
+		return
+	}
+
+	record, err := ctrl.scormService.Commit(c.Request.Context(), userID.(uint), pkg, req.Values, req.Finish)
+	if err != nil {
+		if errors.Is(err, services.ErrScormReadOnlyElement) {
+			utils.BadRequest(c, err.Error())
+			return
+		}
+		utils.InternalError(c, "保存运行时数据失败: "+err.Error())
+		return
+	}
+
+	utils.Success(c, ScormCommitResponse{
+		Progress:    record.Progress,
+		Duration:    record.Duration,
+		IsCompleted: record.IsCompleted,
+		Score:       record.Score,
+	})
+}
+
+// publishedPackage 获取已发布 SCORM 课程的课件包
+func (ctrl *ScormController) publishedPackage(c *gin.Context) (*models.ScormPackage, bool) {
+	id, _ := strconv.ParseUint(c.Param("id"), 10, 32)
+	if id == 0 {
+		utils.BadRequest(c, "课程ID不能为空")
+		return nil, false
+	}
+
+	var course models.Course
+	if err := database.WithContext(c.Request.Context()).Scopes(services.AvailableCourses(time.Now())).First(&course, id).Error; err != nil {
+		utils.Fail(c, utils.CodeCourseNotFound, "")
+		return nil, false
+	}
+	if course.ContentType != models.ContentTypeSCORM {
+		utils.BadRequest(c, "该课程不是 SCORM 课程")
+		return nil, false
+	}
+
+	pkg, err := ctrl.scormService.GetCoursePackage(c.Request.Context(), course.ID)
+	if err != nil {
+		utils.Fail(c, utils.CodeScormPackageNotFound, "")
+		return nil, false
+	}
+	return pkg, true
+}
+
+// ScormPackageResponse 导入的课件包
+type ScormPackageResponse struct {
+	ID        uint   `json:"id"`
+	CourseID  uint   `json:"courseId"`
+	Version   string `json:"version"` // 1.2 或 2004
+	Title     string `json:"title"`
+	EntryHref string `json:"entryHref"`
+	FileSize  int64  `json:"fileSize"` // 解压后总大小（字节）
+	LaunchURL string `json:"launchUrl"`
+}
+
+// ScormSessionResponse 课件启动信息和运行时数据
+type ScormSessionResponse struct {
+	PackageID uint              `json:"packageId"`
+	Version   string            `json:"version"`
+	LaunchURL string            `json:"launchUrl"`
+	Values    map[string]string `json:"values"` // 运行时数据模型（cmi.*）
+}
+
+// ScormCommitResponse 保存运行时数据后的学习记录
+type ScormCommitResponse struct {
+	Progress    int      `json:"progress"`
+	Duration    int      `json:"duration"`
+	IsCompleted bool     `json:"isCompleted"`
+	Score       *float64 `json:"score"` // 课件未上报成绩时为 null
+}
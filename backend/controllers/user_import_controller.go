@@ -0,0 +1,100 @@
+package controllers
+
+import (
+	"errors"
+	"fmt"
+	"learn-hub-backend/services"
+	"learn-hub-backend/utils"
+	"log/slog"
+	"os"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+type UserImportController struct {
+	importService *services.UserImportService
+}
+
+func NewUserImportController() *UserImportController {
+	return &UserImportController{
+		importService: &services.UserImportService{},
+	}
+}
+
+// ImportUsers 批量导入用户（CSV/XLSX），dryRun=true 时只校验不写入；存在任一错误行时不写入任何数据
+func (ctrl *UserImportController) ImportUsers(c *gin.Context) {
+	fileHeader, err := c.FormFile("file")
+	if err != nil {
+		utils.BadRequest(c, "请上传用户文件(csv/xlsx)")
+		return
+	}
+
+	file, err := fileHeader.Open()
+	if err != nil {
+		utils.BadRequest(c, "读取上传文件失败")
+		return
+	}
+	defer file.Close()
+
+	dryRun := c.PostForm("dryRun") == "true" || c.Query("dryRun") == "true"
+	userID, _ := c.Get("userId")
+	result, err := ctrl.importService.Import(c.Request.Context(), userID.(uint), fileHeader.Filename, file, dryRun)
+	if err != nil {
+		if errors.Is(err, services.ErrInvalidTableFile) || errors.Is(err, services.ErrInvalidUserImport) {
+			utils.BadRequest(c, err.Error())
+			return
+		}
+		if errors.Is(err, services.ErrLastAdmin) {
+			utils.Fail(c, utils.CodeProtected, err.Error())
+			return
+		}
+		utils.InternalError(c, "导入失败: "+err.Error())
+		return
+	}
+
+	utils.Success(c, result)
+}
+
+// GetImportTemplate 下载导入模板
+func (ctrl *UserImportController) GetImportTemplate(c *gin.Context) {
+	format := c.DefaultQuery("format", services.ExportFormatCSV)
+	if format != services.ExportFormatCSV && format != services.ExportFormatXLSX {
+		utils.BadRequest(c, "不支持的模板格式")
+		return
+	}
+
+	contentType := "text/csv; charset=utf-8"
+	if format == services.ExportFormatXLSX {
+		contentType = "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
+	}
+	c.Header("Content-Type", contentType)
+	c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="user-import-template.%s"`, format))
+
+	if err := ctrl.importService.WriteTemplate(format, c.Writer); err != nil {
+		slog.ErrorContext(c.Request.Context(), "生成导入模板失败", "error", err)
+	}
+}
+
+// DownloadReport 下载导入结果报告
+func (ctrl *UserImportController) DownloadReport(c *gin.Context) {
+	id, _ := strconv.ParseUint(c.Param("id"), 10, 32)
+	if id == 0 {
+		utils.BadRequest(c, "导入批次ID不能为空")
+		return
+	}
+
+	batch, err := ctrl.importService.GetImport(c.Request.Context(), uint(id))
+	if err != nil {
+		utils.Fail(c, utils.CodeImportNotFound, "")
+		return
+	}
+
+	filePath := ctrl.importService.ReportFile(batch)
+	if _, err := os.Stat(filePath); err != nil {
+		utils.Fail(c, utils.CodeImportReportNotFound, "")
+		return
+	}
+
+	c.FileAttachment(filePath, fmt.Sprintf("user-import-%d-report.csv", batch.ID))
+}
@@ -0,0 +1,375 @@
+package controllers
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io"
+	"learn-hub-backend/database"
+	"learn-hub-backend/models"
+	"learn-hub-backend/services"
+	"learn-hub-backend/utils"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// xAPI 查询默认和最大返回条数
+const (
+	xapiDefaultLimit = 100
+	xapiMaxLimit     = 500
+)
+
+type XAPIController struct {
+	xapiService *services.XAPIService
+}
+
+func NewXAPIController() *XAPIController {
+	return &XAPIController{
+		xapiService: &services.XAPIService{},
+	}
+}
+
+// PostStatements 保存一条或多条语句（xAPI POST /statements）
+func (ctrl *XAPIController) PostStatements(c *gin.Context) {
+	body, err := io.ReadAll(c.Request.Body)
+	if err != nil {
+		utils.BadRequest(c, "读取请求体失败")
+		return
+	}
+
+	// 请求体可以是单条语句或语句数组
+	var raws []json.RawMessage
+	trimmed := bytes.TrimSpace(body)
+	if len(trimmed) > 0 && trimmed[0] == '[' {
+		if err := json.Unmarshal(trimmed, &raws); err != nil {
+			utils.BadRequest(c, "参数错误: "+err.Error())
+			return
+		}
+	} else {
+		raws = []json.RawMessage{trimmed}
+	}
+	if len(raws) == 0 {
+		utils.BadRequest(c, "语句不能为空")
+		return
+	}
+
+	ids, err := ctrl.xapiService.StoreStatements(c.Request.Context(), xapiClient(c), raws)
+	if err != nil {
+		ctrl.handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, ids)
+}
+
+// PutStatement 以指定ID保存语句（xAPI PUT /statements?statementId=）
+func (ctrl *XAPIController) PutStatement(c *gin.Context) {
+	statementID := c.Query("statementId")
+	if statementID == "" {
+		utils.BadRequest(c, "缺少 statementId 参数")
+		return
+	}
+
+	body, err := io.ReadAll(c.Request.Body)
+	if err != nil {
+		utils.BadRequest(c, "读取请求体失败")
+		return
+	}
+
+	if err := ctrl.xapiService.PutStatement(c.Request.Context(), xapiClient(c), statementID, body); err != nil {
+		ctrl.handleError(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// GetStatements 查询语句（xAPI GET /statements）
+func (ctrl *XAPIController) GetStatements(c *gin.Context) {
+	c.Header("X-Experience-API-Consistent-Through", time.Now().UTC().Format(time.RFC3339Nano))
+
+	// 单条查询
+	statementID := c.Query("statementId")
+	voidedStatementID := c.Query("voidedStatementId")
+	if statementID != "" && voidedStatementID != "" {
+		utils.BadRequest(c, "statementId 和 voidedStatementId 不能同时提供")
+		return
+	}
+	if statementID != "" || voidedStatementID != "" {
+		id, voided := statementID, false
+		if voidedStatementID != "" {
+			id, voided = voidedStatementID, true
+		}
+
+		statement, err := ctrl.xapiService.GetStatement(c.Request.Context(), id, voided)
+		if err != nil {
+			utils.Fail(c, utils.CodeXAPIStatementNotFound, "语句不存在")
+			return
+		}
+		c.Data(http.StatusOK, "application/json; charset=utf-8", []byte(statement.Raw))
+		return
+	}
+
+	// 条件查询
+	filter := services.XAPIStatementFilter{
+		Agent:        c.Query("agent"),
+		VerbID:       c.Query("verb"),
+		ActivityID:   c.Query("activity"),
+		Registration: c.Query("registration"),
+		Ascending:    c.Query("ascending") == "true",
+	}
+
+	filter.Limit, _ = strconv.Atoi(c.DefaultQuery("limit", "0"))
+	if filter.Limit <= 0 {
+		filter.Limit = xapiDefaultLimit
+	}
+	if filter.Limit > xapiMaxLimit {
+		filter.Limit = xapiMaxLimit
+	}
+	filter.Offset, _ = strconv.Atoi(c.DefaultQuery("offset", "0"))
+	if filter.Offset < 0 {
+		filter.Offset = 0
+	}
+
+	if since := c.Query("since"); since != "" {
+		t, err := time.Parse(time.RFC3339Nano, since)
+		if err != nil {
+			utils.BadRequest(c, "since 时间格式错误")
+			return
+		}
+		filter.Since = &t
+	}
+	if until := c.Query("until"); until != "" {
+		t, err := time.Parse(time.RFC3339Nano, until)
+		if err != nil {
+			utils.BadRequest(c, "until 时间格式错误")
+			return
+		}
+		filter.Until = &t
+	}
+
+	statements, more, err := ctrl.xapiService.QueryStatements(c.Request.Context(), filter)
+	if err != nil {
+		ctrl.handleError(c, err)
+		return
+	}
+
+	list := make([]json.RawMessage, 0, len(statements))
+	for _, statement := range statements {
+		list = append(list, json.RawMessage(statement.Raw))
+	}
+
+	// more 为下一页的相对地址，没有更多结果时为空字符串
+	moreURL := ""
+	if more {
+		query := c.Request.URL.Query()
+		query.Set("offset", strconv.Itoa(filter.Offset+filter.Limit))
+		moreURL = c.Request.URL.Path + "?" + query.Encode()
+	}
+
+	c.JSON(http.StatusOK, XAPIStatementResult{Statements: list, More: moreURL})
+}
+
+// GetClientList 获取 xAPI 客户端列表（管理员）
+func (ctrl *XAPIController) GetClientList(c *gin.Context) {
+	var clients []models.XAPIClient
+	database.WithContext(c.Request.Context()).Order("created_at DESC").Find(&clients)
+
+	list := make([]XAPIClientResponse, 0, len(clients))
+	for i := range clients {
+		list = append(list, newXAPIClientResponse(&clients[i]))
+	}
+
+	utils.Success(c, list)
+}
+
+// CreateXAPIClientRequest 创建 xAPI 客户端请求参数
+type CreateXAPIClientRequest struct {
+	Name     string `json:"name" binding:"required"`
+	CanRead  *bool  `json:"canRead"`
+	CanWrite *bool  `json:"canWrite"`
+}
+
+// CreateClient 创建 xAPI 客户端（管理员），密钥只在创建时返回一次
+func (ctrl *XAPIController) CreateClient(c *gin.Context) {
+	var req CreateXAPIClientRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.BadRequest(c, "参数错误: "+err.Error())
+		return
+	}
+
+	canRead, canWrite := true, true
+	if req.CanRead != nil {
+		canRead = *req.CanRead
+	}
+	if req.CanWrite != nil {
+		canWrite = *req.CanWrite
+	}
+
+	client, secret, err := ctrl.xapiService.CreateClient(c.Request.Context(), req.Name, canRead, canWrite)
+	if err != nil {
+		utils.InternalError(c, "创建失败: "+err.Error())
+		return
+	}
+
+	utils.Success(c, CreateXAPIClientResponse{
+		XAPIClientResponse: newXAPIClientResponse(client),
+		Secret:             secret,
+	})
+}
+
+// DeleteClient 删除 xAPI 客户端（管理员）
+func (ctrl *XAPIController) DeleteClient(c *gin.Context) {
+	id, _ := strconv.ParseUint(c.Param("id"), 10, 32)
+	if id == 0 {
+		utils.BadRequest(c, "客户端ID不能为空")
+		return
+	}
+
+	if err := database.WithContext(c.Request.Context()).Delete(&models.XAPIClient{}, id).Error; err != nil {
+		utils.InternalError(c, "删除失败: "+err.Error())
+		return
+	}
+
+	utils.Success(c, gin.H{})
+}
+
+// GetActivityList 获取 xAPI 活动与课程的映射（管理员）
+func (ctrl *XAPIController) GetActivityList(c *gin.Context) {
+	query := database.WithContext(c.Request.Context()).Model(&models.XAPIActivity{})
+	if courseID := c.Query("courseId"); courseID != "" {
+		query = query.Where("course_id = ?", courseID)
+	}
+
+	var activities []models.XAPIActivity
+	query.Order("created_at DESC").Find(&activities)
+
+	list := make([]XAPIActivityResponse, 0, len(activities))
+	for i := range activities {
+		list = append(list, newXAPIActivityResponse(&activities[i]))
+	}
+
+	utils.Success(c, list)
+}
+
+// SaveXAPIActivityRequest 创建或更新 xAPI 活动映射请求参数
+type SaveXAPIActivityRequest struct {
+	ActivityID string `json:"activityId" binding:"required"`
+	CourseID   uint   `json:"courseId" binding:"required"`
+}
+
+// SaveActivity 创建或更新 xAPI 活动映射（管理员）
+func (ctrl *XAPIController) SaveActivity(c *gin.Context) {
+	var req SaveXAPIActivityRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.BadRequest(c, "参数错误: "+err.Error())
+		return
+	}
+
+	var course models.Course
+	if err := database.WithContext(c.Request.Context()).First(&course, req.CourseID).Error; err != nil {
+		utils.Fail(c, utils.CodeCourseNotFound, "课程不存在")
+		return
+	}
+
+	var activity models.XAPIActivity
+	database.WithContext(c.Request.Context()).Where("activity_id = ?", req.ActivityID).First(&activity)
+	activity.ActivityID = req.ActivityID
+	activity.CourseID = req.CourseID
+
+	if err := database.WithContext(c.Request.Context()).Save(&activity).Error; err != nil {
+		utils.InternalError(c, "保存失败: "+err.Error())
+		return
+	}
+
+	utils.Success(c, newXAPIActivityResponse(&activity))
+}
+
+// DeleteActivity 删除 xAPI 活动映射（管理员）
+func (ctrl *XAPIController) DeleteActivity(c *gin.Context) {
+	id, _ := strconv.ParseUint(c.Param("id"), 10, 32)
+	if id == 0 {
+		utils.BadRequest(c, "映射ID不能为空")
+		return
+	}
+
+	if err := database.WithContext(c.Request.Context()).Delete(&models.XAPIActivity{}, id).Error; err != nil {
+		utils.InternalError(c, "删除失败: "+err.Error())
+		return
+	}
+
+	utils.Success(c, gin.H{})
+}
+
+// handleError 将 LRS 服务错误转换为 xAPI 规定的 HTTP 状态码
+func (ctrl *XAPIController) handleError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, services.ErrXAPIInvalidStatement):
+		utils.BadRequest(c, err.Error())
+	case errors.Is(err, services.ErrXAPIConflict):
+		utils.Fail(c, utils.CodeConflict, err.Error())
+	default:
+		utils.InternalError(c, "保存语句失败: "+err.Error())
+	}
+}
+
+// xapiClient 获取中间件认证通过的客户端
+func xapiClient(c *gin.Context) *models.XAPIClient {
+	client, _ := c.Get("xapiClient")
+	return client.(*models.XAPIClient)
+}
+
+// XAPIStatementResult 语句查询结果（xAPI StatementResult）
+type XAPIStatementResult struct {
+	Statements []json.RawMessage `json:"statements"`
+	More       string            `json:"more"` // 下一页的相对地址，没有更多结果时为空字符串
+}
+
+// XAPIClientResponse xAPI 客户端
+type XAPIClientResponse struct {
+	ID        uint   `json:"id"`
+	Name      string `json:"name"`
+	Key       string `json:"key"` // Basic 认证用户名
+	Status    int    `json:"status"`
+	CanRead   bool   `json:"canRead"`
+	CanWrite  bool   `json:"canWrite"`
+	CreatedAt string `json:"createdAt"`
+}
+
+func newXAPIClientResponse(client *models.XAPIClient) XAPIClientResponse {
+	return XAPIClientResponse{
+		ID:        client.ID,
+		Name:      client.Name,
+		Key:       client.Key,
+		Status:    client.Status,
+		CanRead:   client.CanRead,
+		CanWrite:  client.CanWrite,
+		CreatedAt: client.CreatedAt.Format(utils.DateTimeLayout),
+	}
+}
+
+// CreateXAPIClientResponse 新建的 xAPI 客户端
+type CreateXAPIClientResponse struct {
+	XAPIClientResponse
+	Secret string `json:"secret"` // Basic 认证密码，只在创建时返回
+}
+
+// XAPIActivityResponse xAPI 活动与课程的映射
+type XAPIActivityResponse struct {
+	ID         uint   `json:"id"`
+	ActivityID string `json:"activityId"`
+	CourseID   uint   `json:"courseId"`
+	CreatedAt  string `json:"createdAt"`
+}
+
+func newXAPIActivityResponse(activity *models.XAPIActivity) XAPIActivityResponse {
+	return XAPIActivityResponse{
+		ID:         activity.ID,
+		ActivityID: activity.ActivityID,
+		CourseID:   activity.CourseID,
+		CreatedAt:  activity.CreatedAt.Format(utils.DateTimeLayout),
+	}
+}
@@ -0,0 +1,129 @@
+package database_test
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// 基线版本的模型定义（原样复制），用于模拟由基线版本 AutoMigrate 创建的已有数据库
+
+type baselineUser struct {
+	ID        uint `gorm:"primarykey"`
+	CreatedAt time.Time
+	UpdatedAt time.Time
+	DeletedAt gorm.DeletedAt `gorm:"index"`
+
+	Username  string `gorm:"uniqueIndex;size:50;not null"`
+	Password  string `gorm:"size:255;not null"`
+	Name      string `gorm:"size:100"`
+	Email     string `gorm:"size:100"`
+	Phone     string `gorm:"size:20"`
+	Avatar    string `gorm:"size:500"`
+	UserID    string `gorm:"uniqueIndex;size:50"`
+	Status    int    `gorm:"default:1;comment:0-禁用,1-启用"`
+	Access    string `gorm:"size:50;comment:访问级别"`
+	Signature string `gorm:"size:500"`
+	Title     string `gorm:"size:100"`
+	GroupID   uint   `gorm:"comment:所属组织ID"`
+	Country   string `gorm:"size:50"`
+	Province  string `gorm:"size:50"`
+	City      string `gorm:"size:50"`
+	Address   string `gorm:"size:500"`
+
+	Roles []baselineRole `gorm:"many2many:sys_user_role;joinForeignKey:UserID;joinReferences:RoleID"`
+}
+
+func (baselineUser) TableName() string { return "sys_user" }
+
+type baselineRole struct {
+	ID        uint `gorm:"primarykey"`
+	CreatedAt time.Time
+	UpdatedAt time.Time
+	DeletedAt gorm.DeletedAt `gorm:"index"`
+
+	Code        string `gorm:"uniqueIndex;size:50;not null;comment:角色代码"`
+	Name        string `gorm:"size:100;not null"`
+	Description string `gorm:"size:500"`
+	Status      int    `gorm:"default:1;comment:0-禁用,1-启用"`
+
+	Menus []baselineMenu `gorm:"many2many:sys_role_menu;joinForeignKey:RoleID;joinReferences:MenuID"`
+}
+
+func (baselineRole) TableName() string { return "sys_role" }
+
+type baselineMenu struct {
+	ID        uint `gorm:"primarykey"`
+	CreatedAt time.Time
+	UpdatedAt time.Time
+	DeletedAt gorm.DeletedAt `gorm:"index"`
+
+	ParentID  uint   `gorm:"default:0;comment:父菜单ID"`
+	Name      string `gorm:"size:100;not null;comment:菜单名称(国际化key)"`
+	Path      string `gorm:"size:200;comment:路由路径"`
+	Component string `gorm:"size:200;comment:组件路径"`
+	Icon      string `gorm:"size:50;comment:图标名称"`
+	SortOrder int    `gorm:"default:0;comment:排序顺序"`
+	Access    string `gorm:"size:100;comment:权限标识"`
+	Redirect  string `gorm:"size:200;comment:重定向路径"`
+	Layout    int    `gorm:"default:1;comment:是否显示布局"`
+	Hidden    int    `gorm:"default:0;comment:是否隐藏"`
+	Status    int    `gorm:"default:1;comment:0-禁用,1-启用"`
+}
+
+func (baselineMenu) TableName() string { return "sys_menu" }
+
+type baselineRule struct {
+	ID        uint `gorm:"primarykey"`
+	CreatedAt time.Time
+	UpdatedAt time.Time
+	DeletedAt gorm.DeletedAt `gorm:"index"`
+
+	Name     string `gorm:"size:200;not null"`
+	Desc     string `gorm:"size:500"`
+	Status   int    `gorm:"default:1;comment:0-关闭,1-运行中,2-已上线,3-异常"`
+	CallNo   int    `gorm:"default:0;comment:服务调用次数"`
+	Owner    string `gorm:"size:100"`
+	Avatar   string `gorm:"size:500"`
+	Href     string `gorm:"size:500"`
+	Disabled bool   `gorm:"default:0"`
+	Progress int    `gorm:"default:0"`
+}
+
+func (baselineRule) TableName() string { return "sys_rule" }
+
+type baselineCourse struct {
+	ID        uint `gorm:"primarykey"`
+	CreatedAt time.Time
+	UpdatedAt time.Time
+	DeletedAt gorm.DeletedAt `gorm:"index"`
+
+	Title       string `gorm:"size:200;not null;comment:课程标题"`
+	Description string `gorm:"type:text;comment:课程描述"`
+	CoverImage  string `gorm:"size:500;comment:封面图片URL"`
+	ContentType int    `gorm:"default:1;comment:内容类型:1-视频,2-文本,3-混合"`
+	VideoURL    string `gorm:"size:500;comment:视频URL"`
+	TextContent string `gorm:"type:text;comment:文本内容"`
+	Duration    int    `gorm:"default:0;comment:视频时长(秒),文本为0"`
+	Status      int    `gorm:"default:0;comment:状态:0-草稿,1-已发布,2-已下架"`
+	SortOrder   int    `gorm:"default:0;comment:排序"`
+}
+
+func (baselineCourse) TableName() string { return "sys_course" }
+
+type baselineCourseRecord struct {
+	ID        uint `gorm:"primarykey"`
+	CreatedAt time.Time
+	UpdatedAt time.Time
+	DeletedAt gorm.DeletedAt `gorm:"index"`
+
+	UserID      uint       `gorm:"not null;index:idx_user_course,unique;comment:用户ID"`
+	CourseID    uint       `gorm:"not null;index:idx_user_course,unique;comment:课程ID"`
+	Progress    int        `gorm:"default:0;comment:学习进度(0-100)"`
+	Duration    int        `gorm:"default:0;comment:已学习时长(秒)"`
+	IsCompleted bool       `gorm:"default:0;comment:是否完成"`
+	CompletedAt *time.Time `gorm:"comment:完成时间"`
+	LastStudyAt time.Time  `gorm:"comment:最后学习时间"`
+}
+
+func (baselineCourseRecord) TableName() string { return "sys_course_record" }
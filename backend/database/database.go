@@ -1,177 +1,183 @@
 package database
 
 import (
-	"database/sql"
+	"context"
+	"errors"
 	"fmt"
-	"log"
+	"log/slog"
 	"time"
 
 	"learn-hub-backend/config"
-	"learn-hub-backend/models"
-	"learn-hub-backend/utils"
+	"learn-hub-backend/metrics"
+	"learn-hub-backend/tracing"
 
+	"github.com/glebarez/sqlite"
 	"gorm.io/driver/mysql"
+	"gorm.io/driver/postgres"
 	"gorm.io/gorm"
 	"gorm.io/gorm/logger"
 )
 
-var DB *gorm.DB
+// 支持的数据库类型
+const (
+	DriverMySQL    = "mysql"
+	DriverPostgres = "postgres"
+	DriverSQLite   = "sqlite"
+)
 
-// InitDB 初始化数据库连接
-func InitDB() {
-	// 构建 DSN，添加必要的连接参数
-	dsn := fmt.Sprintf("%s:%s@tcp(%s:%s)/%s?charset=utf8mb4&parseTime=True&loc=Local&timeout=10s&readTimeout=30s&writeTimeout=30s",
-		config.AppConfig.DBUser,
-		config.AppConfig.DBPassword,
-		config.AppConfig.DBHost,
-		config.AppConfig.DBPort,
-		config.AppConfig.DBName,
-	)
-
-	var err error
-	var sqlDB *sql.DB
-
-	// 尝试连接，最多重试 10 次
-	maxRetries := 10
-	for i := 0; i < maxRetries; i++ {
-		DB, err = gorm.Open(mysql.Open(dsn), &gorm.Config{
-			Logger: logger.Default.LogMode(logger.Info),
-		})
-
-		if err == nil {
-			// 获取底层的 sql.DB 以配置连接池
-			sqlDB, err = DB.DB()
-			if err == nil {
-				// 设置连接池参数
-				sqlDB.SetMaxIdleConns(10)                  // 设置空闲连接池中连接的最大数量
-				sqlDB.SetMaxOpenConns(100)                 // 设置打开数据库连接的最大数量
-				sqlDB.SetConnMaxLifetime(time.Hour)        // 设置了连接可复用的最大时间
-				sqlDB.SetConnMaxIdleTime(10 * time.Minute) // 设置空闲连接的最大存活时间
-
-				// 测试连接
-				if err = sqlDB.Ping(); err == nil {
-					log.Println("数据库连接成功")
-					break
-				}
-			}
-		}
+var DB *gorm.DB
 
-		if i < maxRetries-1 {
-			log.Printf("数据库连接失败，正在重试 (%d/%d)...", i+1, maxRetries)
-			time.Sleep(2 * time.Second)
-		}
+// Dialector 根据配置的数据库类型构建 GORM 驱动
+func Dialector(cfg *config.Config) (gorm.Dialector, error) {
+	switch cfg.DBDriver {
+	case DriverMySQL:
+		// 构建 DSN，添加必要的连接参数
+		dsn := fmt.Sprintf("%s:%s@tcp(%s:%s)/%s?charset=utf8mb4&parseTime=True&loc=Local&timeout=10s&readTimeout=30s&writeTimeout=30s",
+			cfg.DBUser, cfg.DBPassword, cfg.DBHost, cfg.DBPort, cfg.DBName)
+		return mysql.Open(dsn), nil
+	case DriverPostgres:
+		dsn := fmt.Sprintf("host=%s port=%s user=%s password=%s dbname=%s sslmode=%s connect_timeout=10",
+			cfg.DBHost, cfg.DBPort, cfg.DBUser, cfg.DBPassword, cfg.DBName, cfg.DBSSLMode)
+		return postgres.Open(dsn), nil
+	case DriverSQLite:
+		// DBPath 为 :memory: 时使用内存数据库
+		return sqlite.Open(cfg.DBPath + "?_pragma=busy_timeout(5000)&_pragma=journal_mode(WAL)"), nil
+	default:
+		return nil, fmt.Errorf("不支持的数据库类型: %s", cfg.DBDriver)
 	}
+}
 
+// Connect 连接数据库并检查连通性，失败时返回包含连接目标的错误
+// 按配置重试 DBConnectRetries 次（默认不重试），每次失败都会输出原因
+func Connect() error {
+	cfg := config.AppConfig
+	dialector, err := Dialector(cfg)
 	if err != nil {
-		log.Fatalf("数据库连接失败（已重试 %d 次）: %v", maxRetries, err)
+		return err
 	}
 
-	// 自动迁移
-	AutoMigrate()
+	attempts := cfg.DBConnectRetries + 1
+	for i := 1; i <= attempts; i++ {
+		if err = open(dialector, cfg); err == nil {
+			slog.Info("数据库连接成功", "target", describeTarget(cfg))
+			if err := metrics.InstrumentDB(DB, metricsDBName(cfg)); err != nil {
+				return fmt.Errorf("注册数据库指标失败: %w", err)
+			}
+			if err := tracing.InstrumentDB(DB); err != nil {
+				return fmt.Errorf("注册数据库链路追踪失败: %w", err)
+			}
+			return nil
+		}
+		if i < attempts {
+			slog.Warn("数据库连接失败，稍后重试", "attempt", i, "attempts", attempts, "retry_in", connectRetryDelay.String(), "error", err)
+			time.Sleep(connectRetryDelay)
+		}
+	}
+	return fmt.Errorf("无法连接数据库 %s: %w", describeTarget(cfg), err)
 }
 
-// AutoMigrate 自动迁移数据库表
-func AutoMigrate() {
-	// 先迁移基础表（不包含外键依赖的）
-	err := DB.AutoMigrate(
-		&models.User{},
-		&models.Role{},
-		&models.Menu{},
-		&models.Rule{},
-		&models.Course{},
-	)
+// connectRetryDelay 连接失败后的重试间隔
+const connectRetryDelay = 2 * time.Second
+
+// open 打开连接池、设置连接池参数并测试连接
+func open(dialector gorm.Dialector, cfg *config.Config) error {
+	db, err := gorm.Open(dialector, &gorm.Config{
+		Logger: newLogger(cfg.LogLevel, time.Duration(cfg.DBSlowQueryMs)*time.Millisecond),
+	})
 	if err != nil {
-		log.Fatal("数据库迁移失败:", err)
+		return err
 	}
 
-	// 再迁移有外键依赖的表（外键约束已禁用）
-	err = DB.AutoMigrate(
-		&models.CourseRecord{},
-	)
-
+	// 获取底层的 sql.DB 以配置连接池
+	sqlDB, err := db.DB()
 	if err != nil {
-		log.Fatal("数据库迁移失败:", err)
+		return err
+	}
+	sqlDB.SetMaxIdleConns(cfg.DBMaxIdleConns)                                       // 设置空闲连接池中连接的最大数量
+	sqlDB.SetMaxOpenConns(cfg.DBMaxOpenConns)                                       // 设置打开数据库连接的最大数量
+	sqlDB.SetConnMaxLifetime(time.Duration(cfg.DBConnMaxLifetimeMin) * time.Minute) // 设置了连接可复用的最大时间
+	sqlDB.SetConnMaxIdleTime(time.Duration(cfg.DBConnMaxIdleTimeMin) * time.Minute) // 设置空闲连接的最大存活时间
+	if cfg.DBDriver == DriverSQLite {
+		// SQLite 同一时间只允许一个写入者；内存数据库的每个连接是独立的数据库
+		sqlDB.SetMaxOpenConns(1)
+		sqlDB.SetConnMaxLifetime(0)
+		sqlDB.SetConnMaxIdleTime(0)
 	}
 
-	log.Println("数据库迁移完成")
+	// 测试连接
+	if err := sqlDB.Ping(); err != nil {
+		sqlDB.Close()
+		return err
+	}
+	DB = db
+	return nil
+}
 
-	// 初始化基础数据
-	InitData()
+// describeTarget 连接目标描述（不含密码），用于日志和错误信息
+func describeTarget(cfg *config.Config) string {
+	if cfg.DBDriver == DriverSQLite {
+		return fmt.Sprintf("sqlite:%s", cfg.DBPath)
+	}
+	return fmt.Sprintf("%s://%s@%s:%s/%s", cfg.DBDriver, cfg.DBUser, cfg.DBHost, cfg.DBPort, cfg.DBName)
 }
 
-// InitData 初始化基础数据
-func InitData() {
-	// 检查是否已有数据
-	var count int64
-	DB.Model(&models.User{}).Count(&count)
-	if count > 0 {
-		return
-	}
-
-	// 创建默认角色
-	adminRole := models.Role{
-		Code:        "admin",
-		Name:        "管理员",
-		Description: "系统管理员",
-		Status:      1,
-	}
-	userRole := models.Role{
-		Code:        "user",
-		Name:        "普通用户",
-		Description: "普通用户",
-		Status:      1,
-	}
-	DB.Create(&adminRole)
-	DB.Create(&userRole)
-
-	// 创建默认管理员用户（密码: admin123）
-	// 使用动态生成的密码哈希，确保正确性
-	hashedPassword, err := utils.HashPassword("admin123")
-	if err != nil {
-		log.Println("生成密码哈希失败，使用默认值:", err)
-		hashedPassword = "$2a$10$lZiuaxzQSb.5cuKSbV/F1.5dawptkLSm3p42zwEfY4wWuJDlm.qj2" // admin123
+// metricsDBName 连接池指标中的数据库名
+func metricsDBName(cfg *config.Config) string {
+	if cfg.DBDriver == DriverSQLite {
+		return "sqlite"
 	}
+	return cfg.DBName
+}
 
-	adminUser := models.User{
-		Username: "admin",
-		Password: hashedPassword,
-		Name:     "管理员",
-		Email:    "admin@example.com",
-		UserID:   "00000001",
-		Access:   "admin",
-		Status:   1,
-		Roles:    []models.Role{adminRole},
+// Ping 检查数据库连接是否可用
+func Ping(ctx context.Context) error {
+	if DB == nil {
+		return errors.New("数据库未连接")
 	}
-	DB.Create(&adminUser)
+	sqlDB, err := DB.DB()
+	if err != nil {
+		return err
+	}
+	return sqlDB.PingContext(ctx)
+}
 
-	// 创建默认菜单
-	welcomeMenu := models.Menu{
-		ParentID: 0, Name: "welcome", Path: "/welcome", Component: "./Welcome", Icon: "smile", SortOrder: 1, Status: 1,
+// Close 关闭数据库连接池
+func Close() error {
+	if DB == nil {
+		return nil
 	}
-	adminMenu := models.Menu{
-		ParentID: 0, Name: "admin", Path: "/admin", Component: "", Icon: "crown", Access: "canAdmin", SortOrder: 2, Status: 1,
+	sqlDB, err := DB.DB()
+	if err != nil {
+		return err
 	}
-	listMenu := models.Menu{
-		ParentID: 0, Name: "list.table-list", Path: "/list", Component: "./table-list", Icon: "table", SortOrder: 3, Status: 1,
+	return sqlDB.Close()
+}
+
+// gormLogLevel 按应用日志级别确定 SQL 日志级别：debug 时输出全部 SQL，error 时只输出失败的语句，其他级别输出慢查询和错误
+func gormLogLevel(level string) logger.LogLevel {
+	switch level {
+	case "debug":
+		return logger.Info
+	case "error":
+		return logger.Error
+	default:
+		return logger.Warn
 	}
+}
 
-	DB.Create(&welcomeMenu)
-	DB.Create(&adminMenu)
-	DB.Create(&listMenu)
+// InitDB 初始化数据库连接，并按配置执行未执行的迁移
+func InitDB() error {
+	if err := Connect(); err != nil {
+		return err
+	}
 
-	// 创建 admin 菜单的子菜单
-	adminSubMenus := []models.Menu{
-		{ParentID: adminMenu.ID, Path: "/admin", Redirect: "/admin/sub-page", SortOrder: 1, Status: 1},
-		{ParentID: adminMenu.ID, Name: "sub-page", Path: "/admin/sub-page", Component: "./Admin", SortOrder: 2, Status: 1},
+	if !config.AppConfig.MigrateOnStart {
+		return nil
 	}
-	for _, subMenu := range adminSubMenus {
-		DB.Create(&subMenu)
+	applied, err := Migrate()
+	if err != nil {
+		return fmt.Errorf("数据库迁移失败: %w", err)
 	}
-
-	// 为管理员角色分配所有菜单
-	allMenus := []models.Menu{welcomeMenu, adminMenu, listMenu}
-	allMenus = append(allMenus, adminSubMenus...)
-	DB.Model(&adminRole).Association("Menus").Append(&allMenus)
-
-	log.Println("基础数据初始化完成")
+	slog.Info("数据库迁移完成", "applied", applied)
+	return nil
 }
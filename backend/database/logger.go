@@ -0,0 +1,80 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"learn-hub-backend/logging"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// slogLogger 通过 slog 输出 SQL 日志：失败的语句为 error，超过慢查询阈值的为 warn，其余为 debug
+// 使用 WithContext 传入请求 context 的查询会带上 request_id
+type slogLogger struct {
+	level         logger.LogLevel
+	slowThreshold time.Duration // 为 0 时不记录慢查询
+}
+
+// newLogger 按应用日志级别创建 SQL 日志记录器，slowThreshold 为慢查询阈值
+func newLogger(level string, slowThreshold time.Duration) logger.Interface {
+	return &slogLogger{level: gormLogLevel(level), slowThreshold: slowThreshold}
+}
+
+func (l *slogLogger) LogMode(level logger.LogLevel) logger.Interface {
+	clone := *l
+	clone.level = level
+	return &clone
+}
+
+func (l *slogLogger) Info(ctx context.Context, msg string, args ...interface{}) {
+	if l.level >= logger.Info {
+		slog.InfoContext(ctx, fmt.Sprintf(msg, args...))
+	}
+}
+
+func (l *slogLogger) Warn(ctx context.Context, msg string, args ...interface{}) {
+	if l.level >= logger.Warn {
+		slog.WarnContext(ctx, fmt.Sprintf(msg, args...))
+	}
+}
+
+func (l *slogLogger) Error(ctx context.Context, msg string, args ...interface{}) {
+	if l.level >= logger.Error {
+		slog.ErrorContext(ctx, fmt.Sprintf(msg, args...))
+	}
+}
+
+func (l *slogLogger) Trace(ctx context.Context, begin time.Time, fc func() (sql string, rowsAffected int64), err error) {
+	if l.level <= logger.Silent {
+		return
+	}
+
+	elapsed := time.Since(begin)
+	attrs := func() []slog.Attr {
+		sql, rows := fc()
+		return []slog.Attr{
+			slog.String("sql", logging.Redact(sql)),
+			slog.Int64("rows", rows),
+			slog.Float64("elapsed_ms", float64(elapsed.Microseconds())/1000),
+		}
+	}
+
+	switch {
+	case err != nil && !errors.Is(err, gorm.ErrRecordNotFound) && l.level >= logger.Error:
+		slog.LogAttrs(ctx, slog.LevelError, "SQL 执行失败", append(attrs(), slog.String("error", err.Error()))...)
+	case l.slowThreshold > 0 && elapsed > l.slowThreshold && l.level >= logger.Warn:
+		slog.LogAttrs(ctx, slog.LevelWarn, "慢查询", append(attrs(), slog.Int64("threshold_ms", l.slowThreshold.Milliseconds()))...)
+	case l.level >= logger.Info:
+		slog.LogAttrs(ctx, slog.LevelDebug, "SQL", attrs()...)
+	}
+}
+
+// WithContext 使用请求 context 执行查询，SQL 日志中会带上该请求的 request_id
+func WithContext(ctx context.Context) *gorm.DB {
+	return DB.WithContext(ctx)
+}
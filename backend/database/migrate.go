@@ -0,0 +1,343 @@
+package database
+
+import (
+	"context"
+	"embed"
+	"errors"
+	"fmt"
+	"io/fs"
+	"log/slog"
+	"path"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// migrationFiles 按数据库类型分目录存放的迁移文件，命名为 <版本号>_<名称>.up.sql / .down.sql
+//
+//go:embed migrations
+var migrationFiles embed.FS
+
+var migrationFilePattern = regexp.MustCompile(`^(\d+)_(\w+)\.(up|down)\.sql$`)
+
+// migrationLockName 迁移锁名称，保证多个实例同时启动时只有一个执行迁移
+const migrationLockName = "learn_hub_schema_migrations"
+
+// Migration 一个版本的迁移
+type Migration struct {
+	Version int64
+	Name    string
+	Up      string
+	Down    string
+}
+
+// MigrationState 迁移执行状态
+type MigrationState struct {
+	Version   int64
+	Name      string
+	Applied   bool
+	AppliedAt *time.Time
+}
+
+// schemaMigration 已执行的迁移记录
+type schemaMigration struct {
+	Version   int64     `gorm:"primaryKey;autoIncrement:false"`
+	Name      string    `gorm:"size:200;not null"`
+	AppliedAt time.Time `gorm:"not null"`
+}
+
+func (schemaMigration) TableName() string {
+	return "schema_migrations"
+}
+
+// Migrate 执行全部未执行的迁移，返回本次执行的数量
+func Migrate() (int, error) {
+	migrations, err := loadMigrations(DB.Dialector.Name())
+	if err != nil {
+		return 0, err
+	}
+
+	applied := 0
+	err = withMigrationLock(func() error {
+		done, err := appliedVersions()
+		if err != nil {
+			return err
+		}
+
+		for _, m := range migrations {
+			if _, ok := done[m.Version]; ok {
+				continue
+			}
+			slog.Info("执行迁移", "version", m.Version, "name", m.Name)
+			err := DB.Transaction(func(tx *gorm.DB) error {
+				if err := execStatements(tx, m.Up); err != nil {
+					return err
+				}
+				return tx.Create(&schemaMigration{Version: m.Version, Name: m.Name, AppliedAt: time.Now()}).Error
+			})
+			if err != nil {
+				return fmt.Errorf("迁移 %04d_%s 失败: %w", m.Version, m.Name, err)
+			}
+			applied++
+		}
+		return nil
+	})
+	return applied, err
+}
+
+// MigrateDown 按版本倒序回滚最近执行的 steps 个迁移，返回回滚的数量
+func MigrateDown(steps int) (int, error) {
+	migrations, err := loadMigrations(DB.Dialector.Name())
+	if err != nil {
+		return 0, err
+	}
+
+	rolledBack := 0
+	err = withMigrationLock(func() error {
+		done, err := appliedVersions()
+		if err != nil {
+			return err
+		}
+
+		for i := len(migrations) - 1; i >= 0 && rolledBack < steps; i-- {
+			m := migrations[i]
+			if _, ok := done[m.Version]; !ok {
+				continue
+			}
+			slog.Info("回滚迁移", "version", m.Version, "name", m.Name)
+			err := DB.Transaction(func(tx *gorm.DB) error {
+				if err := execStatements(tx, m.Down); err != nil {
+					return err
+				}
+				return tx.Delete(&schemaMigration{}, m.Version).Error
+			})
+			if err != nil {
+				return fmt.Errorf("回滚 %04d_%s 失败: %w", m.Version, m.Name, err)
+			}
+			rolledBack++
+		}
+		return nil
+	})
+	return rolledBack, err
+}
+
+// Seed 重新执行全部基础数据迁移（名称包含 seed），补回缺失的默认角色、菜单等，返回执行的数量
+// 基础数据迁移均可重复执行；表结构迁移必须已全部执行
+func Seed() (int, error) {
+	migrations, err := loadMigrations(DB.Dialector.Name())
+	if err != nil {
+		return 0, err
+	}
+
+	seeded := 0
+	err = withMigrationLock(func() error {
+		done, err := appliedVersions()
+		if err != nil {
+			return err
+		}
+		if len(done) < len(migrations) {
+			return errors.New("存在未执行的迁移，请先执行 migrate up")
+		}
+
+		for _, m := range migrations {
+			if !strings.Contains(m.Name, "seed") {
+				continue
+			}
+			slog.Info("执行基础数据", "version", m.Version, "name", m.Name)
+			if err := DB.Transaction(func(tx *gorm.DB) error { return execStatements(tx, m.Up) }); err != nil {
+				return fmt.Errorf("基础数据 %04d_%s 执行失败: %w", m.Version, m.Name, err)
+			}
+			seeded++
+		}
+		return nil
+	})
+	return seeded, err
+}
+
+// MigrationStatus 查询全部迁移的执行状态
+func MigrationStatus() ([]MigrationState, error) {
+	migrations, err := loadMigrations(DB.Dialector.Name())
+	if err != nil {
+		return nil, err
+	}
+	done, err := appliedVersions()
+	if err != nil {
+		return nil, err
+	}
+
+	states := make([]MigrationState, 0, len(migrations))
+	for _, m := range migrations {
+		state := MigrationState{Version: m.Version, Name: m.Name}
+		if record, ok := done[m.Version]; ok {
+			state.Applied = true
+			state.AppliedAt = &record.AppliedAt
+		}
+		states = append(states, state)
+	}
+	return states, nil
+}
+
+// PendingMigrations 未执行的迁移数量
+func PendingMigrations() (int, error) {
+	states, err := MigrationStatus()
+	if err != nil {
+		return 0, err
+	}
+
+	pending := 0
+	for _, state := range states {
+		if !state.Applied {
+			pending++
+		}
+	}
+	return pending, nil
+}
+
+// loadMigrations 读取指定数据库类型的迁移文件，按版本号排序
+func loadMigrations(dialect string) ([]Migration, error) {
+	dir := path.Join("migrations", dialect)
+	entries, err := fs.ReadDir(migrationFiles, dir)
+	if err != nil {
+		return nil, fmt.Errorf("不支持的数据库类型 %s: %w", dialect, err)
+	}
+
+	byVersion := map[int64]*Migration{}
+	for _, entry := range entries {
+		match := migrationFilePattern.FindStringSubmatch(entry.Name())
+		if match == nil {
+			continue
+		}
+		version, _ := strconv.ParseInt(match[1], 10, 64)
+		content, err := fs.ReadFile(migrationFiles, path.Join(dir, entry.Name()))
+		if err != nil {
+			return nil, err
+		}
+
+		m, ok := byVersion[version]
+		if !ok {
+			m = &Migration{Version: version, Name: match[2]}
+			byVersion[version] = m
+		} else if m.Name != match[2] {
+			return nil, fmt.Errorf("迁移版本 %d 重复: %s 与 %s", version, m.Name, match[2])
+		}
+		if match[3] == "up" {
+			m.Up = string(content)
+		} else {
+			m.Down = string(content)
+		}
+	}
+
+	migrations := make([]Migration, 0, len(byVersion))
+	for _, m := range byVersion {
+		if m.Up == "" {
+			return nil, fmt.Errorf("迁移 %04d_%s 缺少 up 文件", m.Version, m.Name)
+		}
+		migrations = append(migrations, *m)
+	}
+	sort.Slice(migrations, func(i, j int) bool { return migrations[i].Version < migrations[j].Version })
+	return migrations, nil
+}
+
+// appliedVersions 已执行的迁移，迁移记录表不存在时自动创建
+func appliedVersions() (map[int64]schemaMigration, error) {
+	if !DB.Migrator().HasTable(&schemaMigration{}) {
+		if err := DB.Migrator().CreateTable(&schemaMigration{}); err != nil {
+			return nil, err
+		}
+	}
+
+	var records []schemaMigration
+	if err := DB.Find(&records).Error; err != nil {
+		return nil, err
+	}
+	done := make(map[int64]schemaMigration, len(records))
+	for _, record := range records {
+		done[record.Version] = record
+	}
+	return done, nil
+}
+
+// execStatements 逐条执行 SQL 脚本中的语句（以行尾分号分隔，忽略注释行）
+// 注意：MySQL 的 DDL 会隐式提交事务，迁移中的 DDL 应写成可重复执行的形式；
+// 加列和建索引先查 information_schema 再通过 PREPARE 执行（同一事务使用同一连接，@ddl 变量在语句间保留）
+func execStatements(tx *gorm.DB, script string) error {
+	for _, stmt := range splitStatements(script) {
+		if err := tx.Exec(stmt).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func splitStatements(script string) []string {
+	var statements []string
+	var current strings.Builder
+	flush := func() {
+		if stmt := strings.TrimSpace(current.String()); stmt != "" {
+			statements = append(statements, stmt)
+		}
+		current.Reset()
+	}
+
+	for _, line := range strings.Split(script, "\n") {
+		trimmed := strings.TrimSpace(line)
+		if trimmed == "" || strings.HasPrefix(trimmed, "--") {
+			continue
+		}
+		if strings.HasSuffix(trimmed, ";") {
+			current.WriteString(strings.TrimSuffix(trimmed, ";"))
+			flush()
+			continue
+		}
+		current.WriteString(line)
+		current.WriteString("\n")
+	}
+	flush()
+	return statements
+}
+
+// withMigrationLock 在数据库级别的锁内执行迁移，其他实例等待锁释放后会发现迁移已执行
+func withMigrationLock(fn func() error) error {
+	dialect := DB.Dialector.Name()
+	if dialect == "sqlite" {
+		// SQLite 为单机文件数据库且只使用一个连接，写事务本身互斥，无需额外加锁
+		return fn()
+	}
+
+	sqlDB, err := DB.DB()
+	if err != nil {
+		return err
+	}
+
+	ctx := context.Background()
+	conn, err := sqlDB.Conn(ctx)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	switch dialect {
+	case "mysql":
+		var acquired int
+		if err := conn.QueryRowContext(ctx, "SELECT GET_LOCK(?, 300)", migrationLockName).Scan(&acquired); err != nil {
+			return err
+		}
+		if acquired != 1 {
+			return errors.New("等待迁移锁超时")
+		}
+		defer conn.ExecContext(ctx, "SELECT RELEASE_LOCK(?)", migrationLockName)
+	case "postgres":
+		if _, err := conn.ExecContext(ctx, "SELECT pg_advisory_lock(hashtext($1))", migrationLockName); err != nil {
+			return err
+		}
+		defer conn.ExecContext(ctx, "SELECT pg_advisory_unlock(hashtext($1))", migrationLockName)
+	default:
+		return fmt.Errorf("不支持的数据库类型: %s", dialect)
+	}
+
+	return fn()
+}
@@ -0,0 +1,24 @@
+package database
+
+import (
+	"regexp"
+	"testing"
+)
+
+// MySQL 的 DDL 会隐式提交事务，迁移中途失败后重新执行时已加的列和索引不能报错，
+// 因此加列和建索引都要先查 information_schema，通过 PREPARE 动态执行
+func TestMySQLMigrationsAreRerunnable(t *testing.T) {
+	migrations, err := loadMigrations("mysql")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	unguarded := regexp.MustCompile("(?is)^(ALTER TABLE \\S+ ADD |CREATE (UNIQUE )?INDEX )")
+	for _, m := range migrations {
+		for _, stmt := range splitStatements(m.Up) {
+			if unguarded.MatchString(stmt) {
+				t.Errorf("迁移 %04d_%s 中的语句不能重复执行: %s", m.Version, m.Name, stmt)
+			}
+		}
+	}
+}
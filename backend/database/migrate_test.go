@@ -0,0 +1,108 @@
+package database_test
+
+import (
+	"testing"
+	"time"
+
+	"learn-hub-backend/database"
+	"learn-hub-backend/models"
+	"learn-hub-backend/testutil"
+)
+
+func TestMigrateUpDown(t *testing.T) {
+	testutil.SetupDB(t)
+
+	pending, err := database.PendingMigrations()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if pending != 0 {
+		t.Fatalf("迁移后仍有 %d 个未执行的迁移", pending)
+	}
+
+	states, err := database.MigrationStatus()
+	if err != nil {
+		t.Fatal(err)
+	}
+	rolledBack, err := database.MigrateDown(len(states))
+	if err != nil {
+		t.Fatalf("回滚失败: %v", err)
+	}
+	if rolledBack != len(states) {
+		t.Fatalf("回滚了 %d 个迁移，期望 %d 个", rolledBack, len(states))
+	}
+	if database.DB.Migrator().HasTable(&models.User{}) {
+		t.Fatal("全部回滚后 sys_user 仍然存在")
+	}
+
+	applied, err := database.Migrate()
+	if err != nil {
+		t.Fatalf("回滚后重新迁移失败: %v", err)
+	}
+	if applied != len(states) {
+		t.Fatalf("重新执行了 %d 个迁移，期望 %d 个", applied, len(states))
+	}
+}
+
+// 已有数据库（基线版本 AutoMigrate 创建、没有迁移记录）升级后应补齐后续版本新增的字段和表，且保留原有数据
+func TestMigrateUpgradesBaselineSchema(t *testing.T) {
+	testutil.SetupDB(t)
+	db := testutil.Open(t)
+	database.DB = db
+
+	if err := db.AutoMigrate(&baselineUser{}, &baselineRole{}, &baselineMenu{}, &baselineRule{}, &baselineCourse{}, &baselineCourseRecord{}); err != nil {
+		t.Fatal(err)
+	}
+	user := baselineUser{Username: "alice", Password: "x", UserID: "00000042", Status: 1}
+	course := baselineCourse{Title: "Go 入门", ContentType: models.ContentTypeVideo, VideoURL: "https://example.com/go.mp4", Status: 1}
+	if err := db.Create(&user).Error; err != nil {
+		t.Fatal(err)
+	}
+	if err := db.Create(&course).Error; err != nil {
+		t.Fatal(err)
+	}
+	if err := db.Create(&baselineCourseRecord{UserID: user.ID, CourseID: course.ID, Progress: 50, LastStudyAt: time.Now()}).Error; err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := database.Migrate(); err != nil {
+		t.Fatalf("升级已有数据库失败: %v", err)
+	}
+
+	columns := map[interface{}][]string{
+		&models.Course{}:       {"owner_id", "current_revision", "publish_at", "unpublish_at"},
+		&models.CourseRecord{}: {"score", "completed_revision"},
+	}
+	for model, names := range columns {
+		for _, name := range names {
+			if !db.Migrator().HasColumn(model, name) {
+				t.Errorf("升级后缺少字段 %T.%s", model, name)
+			}
+		}
+	}
+	tables := []interface{}{
+		&models.Sequence{}, &models.CourseRevision{}, &models.CourseDraft{}, &models.LearningEvent{},
+		&models.XAPIClient{}, &models.XAPIStatement{}, &models.CourseInstructor{}, &models.RecycleEntry{},
+	}
+	for _, table := range tables {
+		if !db.Migrator().HasTable(table) {
+			t.Errorf("升级后缺少表 %T", table)
+		}
+	}
+
+	// 原有数据保留，且可以按当前模型读写
+	var upgraded models.Course
+	if err := db.First(&upgraded, course.ID).Error; err != nil {
+		t.Fatal(err)
+	}
+	if upgraded.Title != course.Title || upgraded.OwnerID != 0 || upgraded.CurrentRevision != 0 {
+		t.Fatalf("升级后课程数据不正确: %+v", upgraded)
+	}
+	if err := db.Model(&upgraded).Update("owner_id", user.ID).Error; err != nil {
+		t.Fatal(err)
+	}
+	var admin models.User
+	if err := db.Where("username = ?", "admin").First(&admin).Error; err == nil {
+		t.Fatal("已有用户时不应再创建默认管理员")
+	}
+}
@@ -0,0 +1,128 @@
+-- 初始表结构，与基线版本中 AutoMigrate 生成的结构一致；由 AutoMigrate 创建的已有数据库会跳过已存在的表
+-- 此后的表结构变更见 0003 及之后的迁移
+
+CREATE TABLE IF NOT EXISTS `sys_user` (
+  `id` bigint unsigned AUTO_INCREMENT,
+  `created_at` datetime(3) NULL,
+  `updated_at` datetime(3) NULL,
+  `deleted_at` datetime(3) NULL,
+  `username` varchar(50) NOT NULL,
+  `password` varchar(255) NOT NULL,
+  `name` varchar(100),
+  `email` varchar(100),
+  `phone` varchar(20),
+  `avatar` varchar(500),
+  `user_id` varchar(50),
+  `status` bigint DEFAULT 1 COMMENT '0-禁用,1-启用',
+  `access` varchar(50) COMMENT '访问级别',
+  `signature` varchar(500),
+  `title` varchar(100),
+  `group_id` bigint unsigned COMMENT '所属组织ID',
+  `country` varchar(50),
+  `province` varchar(50),
+  `city` varchar(50),
+  `address` varchar(500),
+  PRIMARY KEY (`id`),
+  UNIQUE INDEX `idx_sys_user_user_id` (`user_id`),
+  INDEX `idx_sys_user_deleted_at` (`deleted_at`),
+  UNIQUE INDEX `idx_sys_user_username` (`username`)
+);
+
+CREATE TABLE IF NOT EXISTS `sys_role` (
+  `id` bigint unsigned AUTO_INCREMENT,
+  `created_at` datetime(3) NULL,
+  `updated_at` datetime(3) NULL,
+  `deleted_at` datetime(3) NULL,
+  `code` varchar(50) NOT NULL COMMENT '角色代码',
+  `name` varchar(100) NOT NULL,
+  `description` varchar(500),
+  `status` bigint DEFAULT 1 COMMENT '0-禁用,1-启用',
+  PRIMARY KEY (`id`),
+  UNIQUE INDEX `idx_sys_role_code` (`code`),
+  INDEX `idx_sys_role_deleted_at` (`deleted_at`)
+);
+
+CREATE TABLE IF NOT EXISTS `sys_menu` (
+  `id` bigint unsigned AUTO_INCREMENT,
+  `created_at` datetime(3) NULL,
+  `updated_at` datetime(3) NULL,
+  `deleted_at` datetime(3) NULL,
+  `parent_id` bigint unsigned DEFAULT 0 COMMENT '父菜单ID',
+  `name` varchar(100) NOT NULL COMMENT '菜单名称(国际化key)',
+  `path` varchar(200) COMMENT '路由路径',
+  `component` varchar(200) COMMENT '组件路径',
+  `icon` varchar(50) COMMENT '图标名称',
+  `sort_order` bigint DEFAULT 0 COMMENT '排序顺序',
+  `access` varchar(100) COMMENT '权限标识',
+  `redirect` varchar(200) COMMENT '重定向路径',
+  `layout` bigint DEFAULT 1 COMMENT '是否显示布局',
+  `hidden` bigint DEFAULT 0 COMMENT '是否隐藏',
+  `status` bigint DEFAULT 1 COMMENT '0-禁用,1-启用',
+  PRIMARY KEY (`id`),
+  INDEX `idx_sys_menu_deleted_at` (`deleted_at`)
+);
+
+CREATE TABLE IF NOT EXISTS `sys_rule` (
+  `id` bigint unsigned AUTO_INCREMENT,
+  `created_at` datetime(3) NULL,
+  `updated_at` datetime(3) NULL,
+  `deleted_at` datetime(3) NULL,
+  `name` varchar(200) NOT NULL,
+  `desc` varchar(500),
+  `status` bigint DEFAULT 1 COMMENT '0-关闭,1-运行中,2-已上线,3-异常',
+  `call_no` bigint DEFAULT 0 COMMENT '服务调用次数',
+  `owner` varchar(100),
+  `avatar` varchar(500),
+  `href` varchar(500),
+  `disabled` boolean DEFAULT false,
+  `progress` bigint DEFAULT 0,
+  PRIMARY KEY (`id`),
+  INDEX `idx_sys_rule_deleted_at` (`deleted_at`)
+);
+
+CREATE TABLE IF NOT EXISTS `sys_user_role` (
+  `user_id` bigint unsigned,
+  `role_id` bigint unsigned,
+  PRIMARY KEY (`user_id`,`role_id`)
+);
+
+CREATE TABLE IF NOT EXISTS `sys_role_menu` (
+  `role_id` bigint unsigned,
+  `menu_id` bigint unsigned,
+  PRIMARY KEY (`role_id`,`menu_id`)
+);
+
+CREATE TABLE IF NOT EXISTS `sys_course` (
+  `id` bigint unsigned AUTO_INCREMENT,
+  `created_at` datetime(3) NULL,
+  `updated_at` datetime(3) NULL,
+  `deleted_at` datetime(3) NULL,
+  `title` varchar(200) NOT NULL COMMENT '课程标题',
+  `description` text COMMENT '课程描述',
+  `cover_image` varchar(500) COMMENT '封面图片URL',
+  `content_type` bigint DEFAULT 1 COMMENT '内容类型:1-视频,2-文本,3-混合',
+  `video_url` varchar(500) COMMENT '视频URL',
+  `text_content` text COMMENT '文本内容',
+  `duration` bigint DEFAULT 0 COMMENT '视频时长(秒),文本为0',
+  `status` bigint DEFAULT 0 COMMENT '状态:0-草稿,1-已发布,2-已下架',
+  `sort_order` bigint DEFAULT 0 COMMENT '排序',
+  PRIMARY KEY (`id`),
+  INDEX `idx_sys_course_deleted_at` (`deleted_at`)
+);
+
+CREATE TABLE IF NOT EXISTS `sys_course_record` (
+  `id` bigint unsigned AUTO_INCREMENT,
+  `created_at` datetime(3) NULL,
+  `updated_at` datetime(3) NULL,
+  `deleted_at` datetime(3) NULL,
+  `user_id` bigint unsigned NOT NULL COMMENT '用户ID',
+  `course_id` bigint unsigned NOT NULL COMMENT '课程ID',
+  `progress` bigint DEFAULT 0 COMMENT '学习进度(0-100)',
+  `duration` bigint DEFAULT 0 COMMENT '已学习时长(秒)',
+  `is_completed` boolean DEFAULT false COMMENT '是否完成',
+  `completed_at` datetime(3) NULL COMMENT '完成时间',
+  `last_study_at` datetime(3) NULL COMMENT '最后学习时间',
+  PRIMARY KEY (`id`),
+  INDEX `idx_sys_course_record_deleted_at` (`deleted_at`),
+  UNIQUE INDEX `idx_user_course` (`user_id`,`course_id`)
+);
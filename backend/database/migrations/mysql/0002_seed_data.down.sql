@@ -0,0 +1 @@
+-- 基础数据可能已被修改或被业务数据引用，回滚时不删除
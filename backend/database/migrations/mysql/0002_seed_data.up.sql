@@ -0,0 +1,54 @@
+-- 基础数据：默认角色、管理员账号（admin/admin123）和默认菜单
+-- 每条语句都先检查数据是否已存在，可在已有数据的数据库上重复执行
+
+INSERT INTO `sys_role` (`created_at`, `updated_at`, `code`, `name`, `description`, `status`)
+SELECT NOW(3), NOW(3), 'admin', '管理员', '系统管理员', 1 FROM DUAL
+WHERE NOT EXISTS (SELECT 1 FROM `sys_role` WHERE `code` = 'admin');
+
+INSERT INTO `sys_role` (`created_at`, `updated_at`, `code`, `name`, `description`, `status`)
+SELECT NOW(3), NOW(3), 'user', '普通用户', '普通用户', 1 FROM DUAL
+WHERE NOT EXISTS (SELECT 1 FROM `sys_role` WHERE `code` = 'user');
+
+INSERT INTO `sys_role` (`created_at`, `updated_at`, `code`, `name`, `description`, `status`)
+SELECT NOW(3), NOW(3), 'course_reviewer', '课程审核员', '审核课程发布', 1 FROM DUAL
+WHERE NOT EXISTS (SELECT 1 FROM `sys_role` WHERE `code` = 'course_reviewer');
+
+INSERT INTO `sys_role` (`created_at`, `updated_at`, `code`, `name`, `description`, `status`)
+SELECT NOW(3), NOW(3), 'instructor', '讲师', '创建课程并管理自己负责的课程', 1 FROM DUAL
+WHERE NOT EXISTS (SELECT 1 FROM `sys_role` WHERE `code` = 'instructor');
+
+-- 仅在没有任何用户时创建默认管理员
+INSERT INTO `sys_user` (`created_at`, `updated_at`, `username`, `password`, `name`, `email`, `user_id`, `access`, `status`)
+SELECT NOW(3), NOW(3), 'admin', '$2a$10$lZiuaxzQSb.5cuKSbV/F1.5dawptkLSm3p42zwEfY4wWuJDlm.qj2', '管理员', 'admin@example.com', '00000001', 'admin', 1 FROM DUAL
+WHERE NOT EXISTS (SELECT 1 FROM (SELECT `id` FROM `sys_user` LIMIT 1) t);
+
+INSERT IGNORE INTO `sys_user_role` (`user_id`, `role_id`)
+SELECT u.`id`, r.`id` FROM `sys_user` u, `sys_role` r
+WHERE u.`username` = 'admin' AND u.`deleted_at` IS NULL AND r.`code` = 'admin';
+
+-- 仅在没有任何菜单时创建默认菜单
+INSERT INTO `sys_menu` (`created_at`, `updated_at`, `parent_id`, `name`, `path`, `component`, `icon`, `access`, `sort_order`, `status`)
+SELECT * FROM (
+  SELECT NOW(3) AS c, NOW(3) AS u, 0 AS p, 'welcome' AS n, '/welcome' AS pa, './Welcome' AS co, 'smile' AS i, '' AS a, 1 AS s, 1 AS st
+  UNION ALL SELECT NOW(3), NOW(3), 0, 'admin', '/admin', '', 'crown', 'canAdmin', 2, 1
+  UNION ALL SELECT NOW(3), NOW(3), 0, 'list.table-list', '/list', './table-list', 'table', '', 3, 1
+) m
+WHERE NOT EXISTS (SELECT 1 FROM (SELECT `id` FROM `sys_menu` LIMIT 1) t);
+
+INSERT INTO `sys_menu` (`created_at`, `updated_at`, `parent_id`, `name`, `path`, `component`, `redirect`, `sort_order`, `status`)
+SELECT NOW(3), NOW(3), p.`id`, '', '/admin', '', '/admin/sub-page', 1, 1
+FROM `sys_menu` p
+WHERE p.`parent_id` = 0 AND p.`path` = '/admin' AND p.`deleted_at` IS NULL
+AND NOT EXISTS (SELECT 1 FROM (SELECT `parent_id` FROM `sys_menu`) c WHERE c.`parent_id` = p.`id`);
+
+INSERT INTO `sys_menu` (`created_at`, `updated_at`, `parent_id`, `name`, `path`, `component`, `sort_order`, `status`)
+SELECT NOW(3), NOW(3), p.`id`, 'sub-page', '/admin/sub-page', './Admin', 2, 1
+FROM `sys_menu` p
+WHERE p.`parent_id` = 0 AND p.`path` = '/admin' AND p.`deleted_at` IS NULL
+AND NOT EXISTS (SELECT 1 FROM (SELECT `path` FROM `sys_menu`) c WHERE c.`path` = '/admin/sub-page');
+
+-- 管理员角色拥有全部默认菜单
+INSERT IGNORE INTO `sys_role_menu` (`role_id`, `menu_id`)
+SELECT r.`id`, m.`id` FROM `sys_role` r, `sys_menu` m
+WHERE r.`code` = 'admin' AND m.`deleted_at` IS NULL
+AND m.`path` IN ('/welcome', '/admin', '/list', '/admin/sub-page');
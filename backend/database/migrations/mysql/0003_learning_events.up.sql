@@ -0,0 +1,18 @@
+-- 学习事件日志
+
+CREATE TABLE IF NOT EXISTS `sys_learning_event` (
+  `id` bigint unsigned AUTO_INCREMENT,
+  `created_at` datetime(3) NULL,
+  `user_id` bigint unsigned NOT NULL COMMENT '用户ID',
+  `course_id` bigint unsigned NOT NULL COMMENT '课程ID',
+  `verb` varchar(50) NOT NULL COMMENT '事件动词:started,paused,seeked,progressed,completed',
+  `position` bigint DEFAULT 0 COMMENT '事件发生时的播放位置(秒)',
+  `from_position` bigint COMMENT '拖动前的播放位置(秒),仅seeked',
+  `progress` bigint DEFAULT 0 COMMENT '事件发生时的学习进度(0-100)',
+  `duration` bigint DEFAULT 0 COMMENT '事件发生时的已学习时长(秒)',
+  `occurred_at` datetime(3) NOT NULL COMMENT '事件发生时间',
+  PRIMARY KEY (`id`),
+  INDEX `idx_event_user_time` (`user_id`,`occurred_at`),
+  INDEX `idx_event_course_time` (`course_id`,`occurred_at`),
+  INDEX `idx_sys_learning_event_verb` (`verb`)
+);
@@ -0,0 +1,54 @@
+-- xAPI 客户端、活动映射和语句
+
+CREATE TABLE IF NOT EXISTS `sys_xapi_client` (
+  `id` bigint unsigned AUTO_INCREMENT,
+  `created_at` datetime(3) NULL,
+  `updated_at` datetime(3) NULL,
+  `deleted_at` datetime(3) NULL,
+  `name` varchar(100) NOT NULL COMMENT '客户端名称',
+  `client_key` varchar(64) NOT NULL COMMENT 'Basic认证用户名',
+  `secret` varchar(255) NOT NULL COMMENT 'Basic认证密码哈希',
+  `status` bigint DEFAULT 1 COMMENT '0-禁用,1-启用',
+  `can_read` boolean COMMENT '是否允许查询语句',
+  `can_write` boolean COMMENT '是否允许写入语句',
+  PRIMARY KEY (`id`),
+  INDEX `idx_sys_xapi_client_deleted_at` (`deleted_at`),
+  UNIQUE INDEX `idx_sys_xapi_client_key` (`client_key`)
+);
+
+CREATE TABLE IF NOT EXISTS `sys_xapi_activity` (
+  `id` bigint unsigned AUTO_INCREMENT,
+  `created_at` datetime(3) NULL,
+  `updated_at` datetime(3) NULL,
+  `activity_id` varchar(255) NOT NULL COMMENT '活动IRI',
+  `course_id` bigint unsigned NOT NULL COMMENT '课程ID',
+  PRIMARY KEY (`id`),
+  UNIQUE INDEX `idx_sys_xapi_activity_activity_id` (`activity_id`),
+  INDEX `idx_sys_xapi_activity_course_id` (`course_id`)
+);
+
+CREATE TABLE IF NOT EXISTS `sys_xapi_statement` (
+  `id` bigint unsigned AUTO_INCREMENT,
+  `created_at` datetime(3) NULL,
+  `statement_id` varchar(36) NOT NULL COMMENT '语句UUID',
+  `client_id` bigint unsigned COMMENT '写入的客户端ID',
+  `actor_key` varchar(255) COMMENT '参与者标识(mbox/account)',
+  `user_id` bigint unsigned COMMENT '匹配到的用户ID,0表示未匹配',
+  `verb_id` varchar(255) COMMENT '动词IRI',
+  `activity_id` varchar(255) COMMENT '对象IRI',
+  `registration` varchar(36) COMMENT '注册UUID',
+  `timestamp` datetime(3) NULL COMMENT '语句发生时间',
+  `stored` datetime(3) NULL COMMENT 'LRS存储时间',
+  `voided` boolean DEFAULT false COMMENT '是否已作废',
+  `raw` text NOT NULL COMMENT '语句JSON原文',
+  PRIMARY KEY (`id`),
+  INDEX `idx_sys_xapi_statement_client_id` (`client_id`),
+  INDEX `idx_sys_xapi_statement_actor_key` (`actor_key`),
+  INDEX `idx_sys_xapi_statement_user_id` (`user_id`),
+  INDEX `idx_sys_xapi_statement_verb_id` (`verb_id`),
+  INDEX `idx_sys_xapi_statement_activity_id` (`activity_id`),
+  UNIQUE INDEX `idx_sys_xapi_statement_statement_id` (`statement_id`),
+  INDEX `idx_sys_xapi_statement_registration` (`registration`),
+  INDEX `idx_sys_xapi_statement_stored` (`stored`),
+  INDEX `idx_sys_xapi_statement_voided` (`voided`)
+);
@@ -0,0 +1,4 @@
+ALTER TABLE `sys_course` MODIFY COLUMN `content_type` bigint DEFAULT 1 COMMENT '内容类型:1-视频,2-文本,3-混合';
+ALTER TABLE `sys_course_record` DROP COLUMN `score`;
+DROP TABLE IF EXISTS `sys_scorm_runtime_value`;
+DROP TABLE IF EXISTS `sys_scorm_package`;
@@ -0,0 +1,40 @@
+-- SCORM 课件包和运行时数据，学习记录增加成绩
+
+CREATE TABLE IF NOT EXISTS `sys_scorm_package` (
+  `id` bigint unsigned AUTO_INCREMENT,
+  `created_at` datetime(3) NULL,
+  `updated_at` datetime(3) NULL,
+  `deleted_at` datetime(3) NULL,
+  `course_id` bigint unsigned NOT NULL COMMENT '课程ID',
+  `version` varchar(20) NOT NULL COMMENT 'SCORM版本:1.2,2004',
+  `identifier` varchar(255) COMMENT '清单标识',
+  `title` varchar(200) COMMENT '组织标题',
+  `entry_href` varchar(500) NOT NULL COMMENT '启动SCO的相对路径',
+  `storage_dir` varchar(255) NOT NULL COMMENT '解压目录(相对上传目录)',
+  `file_size` bigint DEFAULT 0 COMMENT '解压后总大小(字节)',
+  PRIMARY KEY (`id`),
+  INDEX `idx_sys_scorm_package_deleted_at` (`deleted_at`),
+  INDEX `idx_sys_scorm_package_course_id` (`course_id`)
+);
+
+CREATE TABLE IF NOT EXISTS `sys_scorm_runtime_value` (
+  `id` bigint unsigned AUTO_INCREMENT,
+  `created_at` datetime(3) NULL,
+  `updated_at` datetime(3) NULL,
+  `user_id` bigint unsigned NOT NULL COMMENT '用户ID',
+  `package_id` bigint unsigned NOT NULL COMMENT '课件包ID',
+  `element` varchar(255) NOT NULL COMMENT '数据模型元素,如cmi.core.lesson_status',
+  `value` text COMMENT '元素值',
+  PRIMARY KEY (`id`),
+  UNIQUE INDEX `idx_scorm_user_element` (`user_id`,`package_id`,`element`)
+);
+
+SET @ddl = IF(
+  (SELECT COUNT(*) FROM information_schema.COLUMNS WHERE TABLE_SCHEMA = DATABASE() AND TABLE_NAME = 'sys_course_record' AND COLUMN_NAME = 'score') = 0,
+  'ALTER TABLE `sys_course_record` ADD COLUMN `score` double COMMENT ''成绩(0-100),SCORM等课件上报''',
+  'SELECT 1');
+PREPARE stmt FROM @ddl;
+EXECUTE stmt;
+DEALLOCATE PREPARE stmt;
+
+ALTER TABLE `sys_course` MODIFY COLUMN `content_type` bigint DEFAULT 1 COMMENT '内容类型:1-视频,2-文本,3-混合,4-SCORM';
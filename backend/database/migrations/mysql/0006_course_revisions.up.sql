@@ -0,0 +1,53 @@
+-- 课程修订版本和草稿，课程增加当前修订版本号，学习记录增加完成时的修订版本号
+
+CREATE TABLE IF NOT EXISTS `sys_course_revision` (
+  `id` bigint unsigned AUTO_INCREMENT,
+  `created_at` datetime(3) NULL,
+  `course_id` bigint unsigned NOT NULL COMMENT '课程ID',
+  `revision` bigint NOT NULL COMMENT '修订版本号,从1开始',
+  `published_by` bigint unsigned COMMENT '发布人用户ID',
+  `note` varchar(500) COMMENT '发布说明',
+  `title` varchar(200) NOT NULL COMMENT '课程标题',
+  `description` text COMMENT '课程描述',
+  `cover_image` varchar(500) COMMENT '封面图片URL',
+  `content_type` bigint DEFAULT 1 COMMENT '内容类型:1-视频,2-文本,3-混合,4-SCORM',
+  `video_url` varchar(500) COMMENT '视频URL',
+  `text_content` text COMMENT '文本内容',
+  `duration` bigint DEFAULT 0 COMMENT '视频时长(秒),文本为0',
+  PRIMARY KEY (`id`),
+  UNIQUE INDEX `idx_course_revision` (`course_id`,`revision`)
+);
+
+CREATE TABLE IF NOT EXISTS `sys_course_draft` (
+  `id` bigint unsigned AUTO_INCREMENT,
+  `created_at` datetime(3) NULL,
+  `updated_at` datetime(3) NULL,
+  `course_id` bigint unsigned NOT NULL COMMENT '课程ID',
+  `base_revision` bigint DEFAULT 0 COMMENT '草稿基于的修订版本号',
+  `updated_by` bigint unsigned COMMENT '最后编辑人用户ID',
+  `title` varchar(200) NOT NULL COMMENT '课程标题',
+  `description` text COMMENT '课程描述',
+  `cover_image` varchar(500) COMMENT '封面图片URL',
+  `content_type` bigint DEFAULT 1 COMMENT '内容类型:1-视频,2-文本,3-混合,4-SCORM',
+  `video_url` varchar(500) COMMENT '视频URL',
+  `text_content` text COMMENT '文本内容',
+  `duration` bigint DEFAULT 0 COMMENT '视频时长(秒),文本为0',
+  PRIMARY KEY (`id`),
+  UNIQUE INDEX `idx_sys_course_draft_course_id` (`course_id`)
+);
+
+SET @ddl = IF(
+  (SELECT COUNT(*) FROM information_schema.COLUMNS WHERE TABLE_SCHEMA = DATABASE() AND TABLE_NAME = 'sys_course' AND COLUMN_NAME = 'current_revision') = 0,
+  'ALTER TABLE `sys_course` ADD COLUMN `current_revision` bigint DEFAULT 0 COMMENT ''当前线上修订版本号,0表示从未发布''',
+  'SELECT 1');
+PREPARE stmt FROM @ddl;
+EXECUTE stmt;
+DEALLOCATE PREPARE stmt;
+
+SET @ddl = IF(
+  (SELECT COUNT(*) FROM information_schema.COLUMNS WHERE TABLE_SCHEMA = DATABASE() AND TABLE_NAME = 'sys_course_record' AND COLUMN_NAME = 'completed_revision') = 0,
+  'ALTER TABLE `sys_course_record` ADD COLUMN `completed_revision` bigint DEFAULT 0 COMMENT ''完成时课程的修订版本号''',
+  'SELECT 1');
+PREPARE stmt FROM @ddl;
+EXECUTE stmt;
+DEALLOCATE PREPARE stmt;
@@ -0,0 +1,31 @@
+-- 课程定时发布和下架时间
+
+SET @ddl = IF(
+  (SELECT COUNT(*) FROM information_schema.COLUMNS WHERE TABLE_SCHEMA = DATABASE() AND TABLE_NAME = 'sys_course' AND COLUMN_NAME = 'publish_at') = 0,
+  'ALTER TABLE `sys_course` ADD COLUMN `publish_at` datetime(3) NULL COMMENT ''定时发布时间''',
+  'SELECT 1');
+PREPARE stmt FROM @ddl;
+EXECUTE stmt;
+DEALLOCATE PREPARE stmt;
+SET @ddl = IF(
+  (SELECT COUNT(*) FROM information_schema.STATISTICS WHERE TABLE_SCHEMA = DATABASE() AND TABLE_NAME = 'sys_course' AND INDEX_NAME = 'idx_sys_course_publish_at') = 0,
+  'CREATE INDEX `idx_sys_course_publish_at` ON `sys_course` (`publish_at`)',
+  'SELECT 1');
+PREPARE stmt FROM @ddl;
+EXECUTE stmt;
+DEALLOCATE PREPARE stmt;
+
+SET @ddl = IF(
+  (SELECT COUNT(*) FROM information_schema.COLUMNS WHERE TABLE_SCHEMA = DATABASE() AND TABLE_NAME = 'sys_course' AND COLUMN_NAME = 'unpublish_at') = 0,
+  'ALTER TABLE `sys_course` ADD COLUMN `unpublish_at` datetime(3) NULL COMMENT ''定时下架时间''',
+  'SELECT 1');
+PREPARE stmt FROM @ddl;
+EXECUTE stmt;
+DEALLOCATE PREPARE stmt;
+SET @ddl = IF(
+  (SELECT COUNT(*) FROM information_schema.STATISTICS WHERE TABLE_SCHEMA = DATABASE() AND TABLE_NAME = 'sys_course' AND INDEX_NAME = 'idx_sys_course_unpublish_at') = 0,
+  'CREATE INDEX `idx_sys_course_unpublish_at` ON `sys_course` (`unpublish_at`)',
+  'SELECT 1');
+PREPARE stmt FROM @ddl;
+EXECUTE stmt;
+DEALLOCATE PREPARE stmt;
@@ -0,0 +1,3 @@
+ALTER TABLE `sys_course` MODIFY COLUMN `status` bigint DEFAULT 0 COMMENT '状态:0-草稿,1-已发布,2-已下架';
+DROP TABLE IF EXISTS `sys_course_transition`;
+DROP TABLE IF EXISTS `sys_course_reviewer`;
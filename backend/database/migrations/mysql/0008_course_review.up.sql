@@ -0,0 +1,27 @@
+-- 课程发布审核：审核人和状态流转记录
+
+CREATE TABLE IF NOT EXISTS `sys_course_reviewer` (
+  `id` bigint unsigned AUTO_INCREMENT,
+  `created_at` datetime(3) NULL,
+  `course_id` bigint unsigned NOT NULL COMMENT '课程ID',
+  `user_id` bigint unsigned NOT NULL COMMENT '审核人用户ID',
+  PRIMARY KEY (`id`),
+  UNIQUE INDEX `idx_course_reviewer` (`course_id`,`user_id`),
+  INDEX `idx_sys_course_reviewer_user_id` (`user_id`)
+);
+
+CREATE TABLE IF NOT EXISTS `sys_course_transition` (
+  `id` bigint unsigned AUTO_INCREMENT,
+  `created_at` datetime(3) NULL,
+  `course_id` bigint unsigned NOT NULL COMMENT '课程ID',
+  `action` varchar(20) NOT NULL COMMENT '操作:submit,withdraw,approve,reject,publish,unpublish',
+  `from_status` bigint NOT NULL COMMENT '操作前状态',
+  `to_status` bigint NOT NULL COMMENT '操作后状态',
+  `operator_id` bigint unsigned COMMENT '操作人用户ID,0表示系统调度',
+  `comment` text COMMENT '审核意见或备注',
+  PRIMARY KEY (`id`),
+  INDEX `idx_sys_course_transition_course_id` (`course_id`),
+  INDEX `idx_sys_course_transition_created_at` (`created_at`)
+);
+
+ALTER TABLE `sys_course` MODIFY COLUMN `status` bigint DEFAULT 0 COMMENT '状态:0-草稿,1-已发布,2-已下架,3-待审核,4-已驳回,5-待发布';
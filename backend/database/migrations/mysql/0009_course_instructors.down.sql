@@ -0,0 +1,2 @@
+ALTER TABLE `sys_course` DROP COLUMN `owner_id`;
+DROP TABLE IF EXISTS `sys_course_instructor`;
@@ -0,0 +1,26 @@
+-- 课程负责人和协作讲师
+
+CREATE TABLE IF NOT EXISTS `sys_course_instructor` (
+  `id` bigint unsigned AUTO_INCREMENT,
+  `created_at` datetime(3) NULL,
+  `course_id` bigint unsigned NOT NULL COMMENT '课程ID',
+  `user_id` bigint unsigned NOT NULL COMMENT '讲师用户ID',
+  PRIMARY KEY (`id`),
+  UNIQUE INDEX `idx_course_instructor` (`course_id`,`user_id`),
+  INDEX `idx_sys_course_instructor_user_id` (`user_id`)
+);
+
+SET @ddl = IF(
+  (SELECT COUNT(*) FROM information_schema.COLUMNS WHERE TABLE_SCHEMA = DATABASE() AND TABLE_NAME = 'sys_course' AND COLUMN_NAME = 'owner_id') = 0,
+  'ALTER TABLE `sys_course` ADD COLUMN `owner_id` bigint unsigned COMMENT ''课程负责人用户ID,0表示仅管理员可管理''',
+  'SELECT 1');
+PREPARE stmt FROM @ddl;
+EXECUTE stmt;
+DEALLOCATE PREPARE stmt;
+SET @ddl = IF(
+  (SELECT COUNT(*) FROM information_schema.STATISTICS WHERE TABLE_SCHEMA = DATABASE() AND TABLE_NAME = 'sys_course' AND INDEX_NAME = 'idx_sys_course_owner_id') = 0,
+  'CREATE INDEX `idx_sys_course_owner_id` ON `sys_course` (`owner_id`)',
+  'SELECT 1');
+PREPARE stmt FROM @ddl;
+EXECUTE stmt;
+DEALLOCATE PREPARE stmt;
@@ -0,0 +1,14 @@
+-- 学习数据日汇总
+
+CREATE TABLE IF NOT EXISTS `sys_learning_daily_stat` (
+  `id` bigint unsigned AUTO_INCREMENT,
+  `created_at` datetime(3) NULL,
+  `updated_at` datetime(3) NULL,
+  `stat_date` varchar(10) NOT NULL COMMENT '统计日期(YYYY-MM-DD)',
+  `course_id` bigint unsigned NOT NULL COMMENT '课程ID,0表示全部课程',
+  `active_learners` bigint DEFAULT 0 COMMENT '活跃学员数',
+  `events` bigint DEFAULT 0 COMMENT '学习事件数',
+  `completions` bigint DEFAULT 0 COMMENT '完成次数',
+  PRIMARY KEY (`id`),
+  UNIQUE INDEX `idx_stat_date_course` (`stat_date`,`course_id`)
+);
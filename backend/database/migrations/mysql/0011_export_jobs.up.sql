@@ -0,0 +1,20 @@
+-- 导出任务
+
+CREATE TABLE IF NOT EXISTS `sys_export_job` (
+  `id` bigint unsigned AUTO_INCREMENT,
+  `created_at` datetime(3) NULL,
+  `updated_at` datetime(3) NULL,
+  `user_id` bigint unsigned NOT NULL COMMENT '发起导出的用户ID',
+  `kind` varchar(50) NOT NULL COMMENT '导出内容:course-progress,user-progress,users,courses',
+  `format` varchar(10) NOT NULL COMMENT '文件格式:csv,xlsx',
+  `params` text COMMENT '筛选条件(JSON)',
+  `status` varchar(20) NOT NULL COMMENT '状态:pending,running,done,failed',
+  `row_count` bigint DEFAULT 0 COMMENT '导出行数',
+  `file_name` varchar(200) COMMENT '下载文件名',
+  `file_path` varchar(500) COMMENT '文件存储路径(相对上传目录)',
+  `error` text COMMENT '失败原因',
+  `finished_at` datetime(3) NULL COMMENT '完成时间',
+  PRIMARY KEY (`id`),
+  INDEX `idx_sys_export_job_user_id` (`user_id`),
+  INDEX `idx_sys_export_job_status` (`status`)
+);
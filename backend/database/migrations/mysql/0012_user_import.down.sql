@@ -0,0 +1 @@
+DROP TABLE IF EXISTS `sys_user_import`;
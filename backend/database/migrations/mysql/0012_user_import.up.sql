@@ -0,0 +1,17 @@
+-- 用户批量导入记录
+
+CREATE TABLE IF NOT EXISTS `sys_user_import` (
+  `id` bigint unsigned AUTO_INCREMENT,
+  `created_at` datetime(3) NULL,
+  `operator_id` bigint unsigned NOT NULL COMMENT '操作人用户ID',
+  `file_name` varchar(200) COMMENT '上传文件名',
+  `dry_run` boolean COMMENT '是否仅预检',
+  `imported` boolean COMMENT '是否已写入数据库',
+  `total` bigint DEFAULT 0 COMMENT '数据行数',
+  `created` bigint DEFAULT 0 COMMENT '新建用户数',
+  `updated` bigint DEFAULT 0 COMMENT '更新用户数',
+  `failed` bigint DEFAULT 0 COMMENT '校验失败行数',
+  `report_path` varchar(500) COMMENT '结果报告路径(相对上传目录)',
+  PRIMARY KEY (`id`),
+  INDEX `idx_sys_user_import_operator_id` (`operator_id`)
+);
@@ -0,0 +1,7 @@
+-- 编号计数器（用户工号）
+
+CREATE TABLE IF NOT EXISTS `sys_sequence` (
+  `name` varchar(50) COMMENT '计数器名称',
+  `value` bigint unsigned NOT NULL DEFAULT 0 COMMENT '最后分配的值',
+  PRIMARY KEY (`name`)
+);
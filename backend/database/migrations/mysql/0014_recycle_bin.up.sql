@@ -0,0 +1,14 @@
+-- 回收站
+
+CREATE TABLE IF NOT EXISTS `sys_recycle_bin` (
+  `id` bigint unsigned AUTO_INCREMENT,
+  `created_at` datetime(3) NULL COMMENT '删除时间',
+  `kind` varchar(20) NOT NULL COMMENT '数据类型:user,role,course,rule',
+  `record_id` bigint unsigned NOT NULL COMMENT '数据ID',
+  `name` varchar(200) COMMENT '显示名称',
+  `unique_keys` text COMMENT '原唯一键值(JSON)',
+  `operator_id` bigint unsigned COMMENT '删除人用户ID,0表示系统',
+  PRIMARY KEY (`id`),
+  INDEX `idx_sys_recycle_bin_created_at` (`created_at`),
+  UNIQUE INDEX `idx_recycle_kind_record` (`kind`,`record_id`)
+);
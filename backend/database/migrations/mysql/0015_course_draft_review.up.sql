@@ -0,0 +1,32 @@
+-- 线上课程的草稿提交审核，审核通过后才生成新的修订版本
+
+SET @ddl = IF(
+  (SELECT COUNT(*) FROM information_schema.COLUMNS WHERE TABLE_SCHEMA = DATABASE() AND TABLE_NAME = 'sys_course_draft' AND COLUMN_NAME = 'submitted_by') = 0,
+  'ALTER TABLE `sys_course_draft` ADD COLUMN `submitted_by` bigint unsigned DEFAULT 0 COMMENT ''提交审核人用户ID''',
+  'SELECT 1');
+PREPARE stmt FROM @ddl;
+EXECUTE stmt;
+DEALLOCATE PREPARE stmt;
+SET @ddl = IF(
+  (SELECT COUNT(*) FROM information_schema.COLUMNS WHERE TABLE_SCHEMA = DATABASE() AND TABLE_NAME = 'sys_course_draft' AND COLUMN_NAME = 'submitted_at') = 0,
+  'ALTER TABLE `sys_course_draft` ADD COLUMN `submitted_at` datetime(3) NULL COMMENT ''提交审核时间,为空表示未提交审核''',
+  'SELECT 1');
+PREPARE stmt FROM @ddl;
+EXECUTE stmt;
+DEALLOCATE PREPARE stmt;
+SET @ddl = IF(
+  (SELECT COUNT(*) FROM information_schema.STATISTICS WHERE TABLE_SCHEMA = DATABASE() AND TABLE_NAME = 'sys_course_draft' AND INDEX_NAME = 'idx_sys_course_draft_submitted_at') = 0,
+  'CREATE INDEX `idx_sys_course_draft_submitted_at` ON `sys_course_draft` (`submitted_at`)',
+  'SELECT 1');
+PREPARE stmt FROM @ddl;
+EXECUTE stmt;
+DEALLOCATE PREPARE stmt;
+SET @ddl = IF(
+  (SELECT COUNT(*) FROM information_schema.COLUMNS WHERE TABLE_SCHEMA = DATABASE() AND TABLE_NAME = 'sys_course_draft' AND COLUMN_NAME = 'note') = 0,
+  'ALTER TABLE `sys_course_draft` ADD COLUMN `note` varchar(500) COMMENT ''发布说明''',
+  'SELECT 1');
+PREPARE stmt FROM @ddl;
+EXECUTE stmt;
+DEALLOCATE PREPARE stmt;
+
+ALTER TABLE `sys_course_transition` MODIFY COLUMN `action` varchar(20) NOT NULL COMMENT '操作:submit,withdraw,approve,reject,publish,unpublish及对应的*_revision';
@@ -0,0 +1,8 @@
+DROP TABLE IF EXISTS "sys_course_record";
+DROP TABLE IF EXISTS "sys_course";
+DROP TABLE IF EXISTS "sys_role_menu";
+DROP TABLE IF EXISTS "sys_user_role";
+DROP TABLE IF EXISTS "sys_rule";
+DROP TABLE IF EXISTS "sys_menu";
+DROP TABLE IF EXISTS "sys_role";
+DROP TABLE IF EXISTS "sys_user";
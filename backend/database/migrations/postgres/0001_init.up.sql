@@ -0,0 +1,162 @@
+-- 初始表结构，与 MySQL 迁移 0001_init 保持一致
+-- 此后的表结构变更见 0003 及之后的迁移
+
+CREATE TABLE IF NOT EXISTS "sys_user" (
+  "id" bigserial,
+  "created_at" timestamptz,
+  "updated_at" timestamptz,
+  "deleted_at" timestamptz,
+  "username" varchar(50) NOT NULL,
+  "password" varchar(255) NOT NULL,
+  "name" varchar(100),
+  "email" varchar(100),
+  "phone" varchar(20),
+  "avatar" varchar(500),
+  "user_id" varchar(50),
+  "status" bigint DEFAULT 1,
+  "access" varchar(50),
+  "signature" varchar(500),
+  "title" varchar(100),
+  "group_id" bigint,
+  "country" varchar(50),
+  "province" varchar(50),
+  "city" varchar(50),
+  "address" varchar(500),
+  PRIMARY KEY ("id")
+);
+CREATE UNIQUE INDEX IF NOT EXISTS "idx_sys_user_user_id" ON "sys_user" ("user_id");
+CREATE UNIQUE INDEX IF NOT EXISTS "idx_sys_user_username" ON "sys_user" ("username");
+CREATE INDEX IF NOT EXISTS "idx_sys_user_deleted_at" ON "sys_user" ("deleted_at");
+COMMENT ON COLUMN "sys_user"."status" IS '0-禁用,1-启用';
+COMMENT ON COLUMN "sys_user"."group_id" IS '所属组织ID';
+COMMENT ON COLUMN "sys_user"."access" IS '访问级别';
+
+CREATE TABLE IF NOT EXISTS "sys_role" (
+  "id" bigserial,
+  "created_at" timestamptz,
+  "updated_at" timestamptz,
+  "deleted_at" timestamptz,
+  "code" varchar(50) NOT NULL,
+  "name" varchar(100) NOT NULL,
+  "description" varchar(500),
+  "status" bigint DEFAULT 1,
+  PRIMARY KEY ("id")
+);
+CREATE UNIQUE INDEX IF NOT EXISTS "idx_sys_role_code" ON "sys_role" ("code");
+CREATE INDEX IF NOT EXISTS "idx_sys_role_deleted_at" ON "sys_role" ("deleted_at");
+COMMENT ON COLUMN "sys_role"."code" IS '角色代码';
+COMMENT ON COLUMN "sys_role"."status" IS '0-禁用,1-启用';
+
+CREATE TABLE IF NOT EXISTS "sys_menu" (
+  "id" bigserial,
+  "created_at" timestamptz,
+  "updated_at" timestamptz,
+  "deleted_at" timestamptz,
+  "parent_id" bigint DEFAULT 0,
+  "name" varchar(100) NOT NULL,
+  "path" varchar(200),
+  "component" varchar(200),
+  "icon" varchar(50),
+  "sort_order" bigint DEFAULT 0,
+  "access" varchar(100),
+  "redirect" varchar(200),
+  "layout" bigint DEFAULT 1,
+  "hidden" bigint DEFAULT 0,
+  "status" bigint DEFAULT 1,
+  PRIMARY KEY ("id")
+);
+CREATE INDEX IF NOT EXISTS "idx_sys_menu_deleted_at" ON "sys_menu" ("deleted_at");
+COMMENT ON COLUMN "sys_menu"."name" IS '菜单名称(国际化key)';
+COMMENT ON COLUMN "sys_menu"."hidden" IS '是否隐藏';
+COMMENT ON COLUMN "sys_menu"."status" IS '0-禁用,1-启用';
+COMMENT ON COLUMN "sys_menu"."parent_id" IS '父菜单ID';
+COMMENT ON COLUMN "sys_menu"."icon" IS '图标名称';
+COMMENT ON COLUMN "sys_menu"."layout" IS '是否显示布局';
+COMMENT ON COLUMN "sys_menu"."component" IS '组件路径';
+COMMENT ON COLUMN "sys_menu"."sort_order" IS '排序顺序';
+COMMENT ON COLUMN "sys_menu"."access" IS '权限标识';
+COMMENT ON COLUMN "sys_menu"."path" IS '路由路径';
+COMMENT ON COLUMN "sys_menu"."redirect" IS '重定向路径';
+
+CREATE TABLE IF NOT EXISTS "sys_rule" (
+  "id" bigserial,
+  "created_at" timestamptz,
+  "updated_at" timestamptz,
+  "deleted_at" timestamptz,
+  "name" varchar(200) NOT NULL,
+  "desc" varchar(500),
+  "status" bigint DEFAULT 1,
+  "call_no" bigint DEFAULT 0,
+  "owner" varchar(100),
+  "avatar" varchar(500),
+  "href" varchar(500),
+  "disabled" boolean DEFAULT false,
+  "progress" bigint DEFAULT 0,
+  PRIMARY KEY ("id")
+);
+CREATE INDEX IF NOT EXISTS "idx_sys_rule_deleted_at" ON "sys_rule" ("deleted_at");
+COMMENT ON COLUMN "sys_rule"."status" IS '0-关闭,1-运行中,2-已上线,3-异常';
+COMMENT ON COLUMN "sys_rule"."call_no" IS '服务调用次数';
+
+CREATE TABLE IF NOT EXISTS "sys_user_role" (
+  "user_id" bigint,
+  "role_id" bigint,
+  PRIMARY KEY ("user_id","role_id")
+);
+
+CREATE TABLE IF NOT EXISTS "sys_role_menu" (
+  "role_id" bigint,
+  "menu_id" bigint,
+  PRIMARY KEY ("role_id","menu_id")
+);
+
+CREATE TABLE IF NOT EXISTS "sys_course" (
+  "id" bigserial,
+  "created_at" timestamptz,
+  "updated_at" timestamptz,
+  "deleted_at" timestamptz,
+  "title" varchar(200) NOT NULL,
+  "description" text,
+  "cover_image" varchar(500),
+  "content_type" bigint DEFAULT 1,
+  "video_url" varchar(500),
+  "text_content" text,
+  "duration" bigint DEFAULT 0,
+  "status" bigint DEFAULT 0,
+  "sort_order" bigint DEFAULT 0,
+  PRIMARY KEY ("id")
+);
+CREATE INDEX IF NOT EXISTS "idx_sys_course_deleted_at" ON "sys_course" ("deleted_at");
+COMMENT ON COLUMN "sys_course"."status" IS '状态:0-草稿,1-已发布,2-已下架';
+COMMENT ON COLUMN "sys_course"."text_content" IS '文本内容';
+COMMENT ON COLUMN "sys_course"."title" IS '课程标题';
+COMMENT ON COLUMN "sys_course"."cover_image" IS '封面图片URL';
+COMMENT ON COLUMN "sys_course"."sort_order" IS '排序';
+COMMENT ON COLUMN "sys_course"."video_url" IS '视频URL';
+COMMENT ON COLUMN "sys_course"."description" IS '课程描述';
+COMMENT ON COLUMN "sys_course"."content_type" IS '内容类型:1-视频,2-文本,3-混合';
+COMMENT ON COLUMN "sys_course"."duration" IS '视频时长(秒),文本为0';
+
+CREATE TABLE IF NOT EXISTS "sys_course_record" (
+  "id" bigserial,
+  "created_at" timestamptz,
+  "updated_at" timestamptz,
+  "deleted_at" timestamptz,
+  "user_id" bigint NOT NULL,
+  "course_id" bigint NOT NULL,
+  "progress" bigint DEFAULT 0,
+  "duration" bigint DEFAULT 0,
+  "is_completed" boolean DEFAULT false,
+  "completed_at" timestamptz,
+  "last_study_at" timestamptz,
+  PRIMARY KEY ("id")
+);
+CREATE UNIQUE INDEX IF NOT EXISTS "idx_user_course" ON "sys_course_record" ("user_id","course_id");
+CREATE INDEX IF NOT EXISTS "idx_sys_course_record_deleted_at" ON "sys_course_record" ("deleted_at");
+COMMENT ON COLUMN "sys_course_record"."duration" IS '已学习时长(秒)';
+COMMENT ON COLUMN "sys_course_record"."completed_at" IS '完成时间';
+COMMENT ON COLUMN "sys_course_record"."user_id" IS '用户ID';
+COMMENT ON COLUMN "sys_course_record"."course_id" IS '课程ID';
+COMMENT ON COLUMN "sys_course_record"."progress" IS '学习进度(0-100)';
+COMMENT ON COLUMN "sys_course_record"."is_completed" IS '是否完成';
+COMMENT ON COLUMN "sys_course_record"."last_study_at" IS '最后学习时间';
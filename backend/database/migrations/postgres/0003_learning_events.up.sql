@@ -0,0 +1,26 @@
+-- 学习事件日志
+
+CREATE TABLE IF NOT EXISTS "sys_learning_event" (
+  "id" bigserial,
+  "created_at" timestamptz,
+  "user_id" bigint NOT NULL,
+  "course_id" bigint NOT NULL,
+  "verb" varchar(50) NOT NULL,
+  "position" bigint DEFAULT 0,
+  "from_position" bigint,
+  "progress" bigint DEFAULT 0,
+  "duration" bigint DEFAULT 0,
+  "occurred_at" timestamptz NOT NULL,
+  PRIMARY KEY ("id")
+);
+CREATE INDEX IF NOT EXISTS "idx_sys_learning_event_verb" ON "sys_learning_event" ("verb");
+CREATE INDEX IF NOT EXISTS "idx_event_course_time" ON "sys_learning_event" ("course_id","occurred_at");
+CREATE INDEX IF NOT EXISTS "idx_event_user_time" ON "sys_learning_event" ("user_id","occurred_at");
+COMMENT ON COLUMN "sys_learning_event"."user_id" IS '用户ID';
+COMMENT ON COLUMN "sys_learning_event"."course_id" IS '课程ID';
+COMMENT ON COLUMN "sys_learning_event"."verb" IS '事件动词:started,paused,seeked,progressed,completed';
+COMMENT ON COLUMN "sys_learning_event"."position" IS '事件发生时的播放位置(秒)';
+COMMENT ON COLUMN "sys_learning_event"."from_position" IS '拖动前的播放位置(秒),仅seeked';
+COMMENT ON COLUMN "sys_learning_event"."progress" IS '事件发生时的学习进度(0-100)';
+COMMENT ON COLUMN "sys_learning_event"."duration" IS '事件发生时的已学习时长(秒)';
+COMMENT ON COLUMN "sys_learning_event"."occurred_at" IS '事件发生时间';
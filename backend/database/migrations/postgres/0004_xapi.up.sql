@@ -0,0 +1,73 @@
+-- xAPI 客户端、活动映射和语句
+
+CREATE TABLE IF NOT EXISTS "sys_xapi_client" (
+  "id" bigserial,
+  "created_at" timestamptz,
+  "updated_at" timestamptz,
+  "deleted_at" timestamptz,
+  "name" varchar(100) NOT NULL,
+  "client_key" varchar(64) NOT NULL,
+  "secret" varchar(255) NOT NULL,
+  "status" bigint DEFAULT 1,
+  "can_read" boolean,
+  "can_write" boolean,
+  PRIMARY KEY ("id")
+);
+CREATE UNIQUE INDEX IF NOT EXISTS "idx_sys_xapi_client_key" ON "sys_xapi_client" ("client_key");
+CREATE INDEX IF NOT EXISTS "idx_sys_xapi_client_deleted_at" ON "sys_xapi_client" ("deleted_at");
+COMMENT ON COLUMN "sys_xapi_client"."name" IS '客户端名称';
+COMMENT ON COLUMN "sys_xapi_client"."client_key" IS 'Basic认证用户名';
+COMMENT ON COLUMN "sys_xapi_client"."secret" IS 'Basic认证密码哈希';
+COMMENT ON COLUMN "sys_xapi_client"."status" IS '0-禁用,1-启用';
+COMMENT ON COLUMN "sys_xapi_client"."can_read" IS '是否允许查询语句';
+COMMENT ON COLUMN "sys_xapi_client"."can_write" IS '是否允许写入语句';
+
+CREATE TABLE IF NOT EXISTS "sys_xapi_activity" (
+  "id" bigserial,
+  "created_at" timestamptz,
+  "updated_at" timestamptz,
+  "activity_id" varchar(255) NOT NULL,
+  "course_id" bigint NOT NULL,
+  PRIMARY KEY ("id")
+);
+CREATE INDEX IF NOT EXISTS "idx_sys_xapi_activity_course_id" ON "sys_xapi_activity" ("course_id");
+CREATE UNIQUE INDEX IF NOT EXISTS "idx_sys_xapi_activity_activity_id" ON "sys_xapi_activity" ("activity_id");
+COMMENT ON COLUMN "sys_xapi_activity"."activity_id" IS '活动IRI';
+COMMENT ON COLUMN "sys_xapi_activity"."course_id" IS '课程ID';
+
+CREATE TABLE IF NOT EXISTS "sys_xapi_statement" (
+  "id" bigserial,
+  "created_at" timestamptz,
+  "statement_id" varchar(36) NOT NULL,
+  "client_id" bigint,
+  "actor_key" varchar(255),
+  "user_id" bigint,
+  "verb_id" varchar(255),
+  "activity_id" varchar(255),
+  "registration" varchar(36),
+  "timestamp" timestamptz,
+  "stored" timestamptz,
+  "voided" boolean DEFAULT false,
+  "raw" text NOT NULL,
+  PRIMARY KEY ("id")
+);
+CREATE INDEX IF NOT EXISTS "idx_sys_xapi_statement_registration" ON "sys_xapi_statement" ("registration");
+CREATE INDEX IF NOT EXISTS "idx_sys_xapi_statement_activity_id" ON "sys_xapi_statement" ("activity_id");
+CREATE INDEX IF NOT EXISTS "idx_sys_xapi_statement_verb_id" ON "sys_xapi_statement" ("verb_id");
+CREATE UNIQUE INDEX IF NOT EXISTS "idx_sys_xapi_statement_statement_id" ON "sys_xapi_statement" ("statement_id");
+CREATE INDEX IF NOT EXISTS "idx_sys_xapi_statement_voided" ON "sys_xapi_statement" ("voided");
+CREATE INDEX IF NOT EXISTS "idx_sys_xapi_statement_stored" ON "sys_xapi_statement" ("stored");
+CREATE INDEX IF NOT EXISTS "idx_sys_xapi_statement_user_id" ON "sys_xapi_statement" ("user_id");
+CREATE INDEX IF NOT EXISTS "idx_sys_xapi_statement_actor_key" ON "sys_xapi_statement" ("actor_key");
+CREATE INDEX IF NOT EXISTS "idx_sys_xapi_statement_client_id" ON "sys_xapi_statement" ("client_id");
+COMMENT ON COLUMN "sys_xapi_statement"."raw" IS '语句JSON原文';
+COMMENT ON COLUMN "sys_xapi_statement"."statement_id" IS '语句UUID';
+COMMENT ON COLUMN "sys_xapi_statement"."client_id" IS '写入的客户端ID';
+COMMENT ON COLUMN "sys_xapi_statement"."verb_id" IS '动词IRI';
+COMMENT ON COLUMN "sys_xapi_statement"."activity_id" IS '对象IRI';
+COMMENT ON COLUMN "sys_xapi_statement"."registration" IS '注册UUID';
+COMMENT ON COLUMN "sys_xapi_statement"."timestamp" IS '语句发生时间';
+COMMENT ON COLUMN "sys_xapi_statement"."actor_key" IS '参与者标识(mbox/account)';
+COMMENT ON COLUMN "sys_xapi_statement"."user_id" IS '匹配到的用户ID,0表示未匹配';
+COMMENT ON COLUMN "sys_xapi_statement"."stored" IS 'LRS存储时间';
+COMMENT ON COLUMN "sys_xapi_statement"."voided" IS '是否已作废';
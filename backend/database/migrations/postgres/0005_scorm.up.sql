@@ -0,0 +1,46 @@
+-- SCORM 课件包和运行时数据，学习记录增加成绩
+
+CREATE TABLE IF NOT EXISTS "sys_scorm_package" (
+  "id" bigserial,
+  "created_at" timestamptz,
+  "updated_at" timestamptz,
+  "deleted_at" timestamptz,
+  "course_id" bigint NOT NULL,
+  "version" varchar(20) NOT NULL,
+  "identifier" varchar(255),
+  "title" varchar(200),
+  "entry_href" varchar(500) NOT NULL,
+  "storage_dir" varchar(255) NOT NULL,
+  "file_size" bigint DEFAULT 0,
+  PRIMARY KEY ("id")
+);
+CREATE INDEX IF NOT EXISTS "idx_sys_scorm_package_course_id" ON "sys_scorm_package" ("course_id");
+CREATE INDEX IF NOT EXISTS "idx_sys_scorm_package_deleted_at" ON "sys_scorm_package" ("deleted_at");
+COMMENT ON COLUMN "sys_scorm_package"."storage_dir" IS '解压目录(相对上传目录)';
+COMMENT ON COLUMN "sys_scorm_package"."course_id" IS '课程ID';
+COMMENT ON COLUMN "sys_scorm_package"."identifier" IS '清单标识';
+COMMENT ON COLUMN "sys_scorm_package"."title" IS '组织标题';
+COMMENT ON COLUMN "sys_scorm_package"."entry_href" IS '启动SCO的相对路径';
+COMMENT ON COLUMN "sys_scorm_package"."file_size" IS '解压后总大小(字节)';
+COMMENT ON COLUMN "sys_scorm_package"."version" IS 'SCORM版本:1.2,2004';
+
+CREATE TABLE IF NOT EXISTS "sys_scorm_runtime_value" (
+  "id" bigserial,
+  "created_at" timestamptz,
+  "updated_at" timestamptz,
+  "user_id" bigint NOT NULL,
+  "package_id" bigint NOT NULL,
+  "element" varchar(255) NOT NULL,
+  "value" text,
+  PRIMARY KEY ("id")
+);
+CREATE UNIQUE INDEX IF NOT EXISTS "idx_scorm_user_element" ON "sys_scorm_runtime_value" ("user_id","package_id","element");
+COMMENT ON COLUMN "sys_scorm_runtime_value"."element" IS '数据模型元素,如cmi.core.lesson_status';
+COMMENT ON COLUMN "sys_scorm_runtime_value"."value" IS '元素值';
+COMMENT ON COLUMN "sys_scorm_runtime_value"."user_id" IS '用户ID';
+COMMENT ON COLUMN "sys_scorm_runtime_value"."package_id" IS '课件包ID';
+
+ALTER TABLE "sys_course_record" ADD COLUMN "score" decimal;
+COMMENT ON COLUMN "sys_course_record"."score" IS '成绩(0-100),SCORM等课件上报';
+
+COMMENT ON COLUMN "sys_course"."content_type" IS '内容类型:1-视频,2-文本,3-混合,4-SCORM';
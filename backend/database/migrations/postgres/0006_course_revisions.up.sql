@@ -0,0 +1,64 @@
+-- 课程修订版本和草稿，课程增加当前修订版本号，学习记录增加完成时的修订版本号
+
+CREATE TABLE IF NOT EXISTS "sys_course_revision" (
+  "id" bigserial,
+  "created_at" timestamptz,
+  "course_id" bigint NOT NULL,
+  "revision" bigint NOT NULL,
+  "published_by" bigint,
+  "note" varchar(500),
+  "title" varchar(200) NOT NULL,
+  "description" text,
+  "cover_image" varchar(500),
+  "content_type" bigint DEFAULT 1,
+  "video_url" varchar(500),
+  "text_content" text,
+  "duration" bigint DEFAULT 0,
+  PRIMARY KEY ("id")
+);
+CREATE UNIQUE INDEX IF NOT EXISTS "idx_course_revision" ON "sys_course_revision" ("course_id","revision");
+COMMENT ON COLUMN "sys_course_revision"."revision" IS '修订版本号,从1开始';
+COMMENT ON COLUMN "sys_course_revision"."description" IS '课程描述';
+COMMENT ON COLUMN "sys_course_revision"."cover_image" IS '封面图片URL';
+COMMENT ON COLUMN "sys_course_revision"."duration" IS '视频时长(秒),文本为0';
+COMMENT ON COLUMN "sys_course_revision"."course_id" IS '课程ID';
+COMMENT ON COLUMN "sys_course_revision"."published_by" IS '发布人用户ID';
+COMMENT ON COLUMN "sys_course_revision"."note" IS '发布说明';
+COMMENT ON COLUMN "sys_course_revision"."title" IS '课程标题';
+COMMENT ON COLUMN "sys_course_revision"."content_type" IS '内容类型:1-视频,2-文本,3-混合,4-SCORM';
+COMMENT ON COLUMN "sys_course_revision"."video_url" IS '视频URL';
+COMMENT ON COLUMN "sys_course_revision"."text_content" IS '文本内容';
+
+CREATE TABLE IF NOT EXISTS "sys_course_draft" (
+  "id" bigserial,
+  "created_at" timestamptz,
+  "updated_at" timestamptz,
+  "course_id" bigint NOT NULL,
+  "base_revision" bigint DEFAULT 0,
+  "updated_by" bigint,
+  "title" varchar(200) NOT NULL,
+  "description" text,
+  "cover_image" varchar(500),
+  "content_type" bigint DEFAULT 1,
+  "video_url" varchar(500),
+  "text_content" text,
+  "duration" bigint DEFAULT 0,
+  PRIMARY KEY ("id")
+);
+CREATE UNIQUE INDEX IF NOT EXISTS "idx_sys_course_draft_course_id" ON "sys_course_draft" ("course_id");
+COMMENT ON COLUMN "sys_course_draft"."base_revision" IS '草稿基于的修订版本号';
+COMMENT ON COLUMN "sys_course_draft"."cover_image" IS '封面图片URL';
+COMMENT ON COLUMN "sys_course_draft"."video_url" IS '视频URL';
+COMMENT ON COLUMN "sys_course_draft"."text_content" IS '文本内容';
+COMMENT ON COLUMN "sys_course_draft"."duration" IS '视频时长(秒),文本为0';
+COMMENT ON COLUMN "sys_course_draft"."course_id" IS '课程ID';
+COMMENT ON COLUMN "sys_course_draft"."updated_by" IS '最后编辑人用户ID';
+COMMENT ON COLUMN "sys_course_draft"."title" IS '课程标题';
+COMMENT ON COLUMN "sys_course_draft"."description" IS '课程描述';
+COMMENT ON COLUMN "sys_course_draft"."content_type" IS '内容类型:1-视频,2-文本,3-混合,4-SCORM';
+
+ALTER TABLE "sys_course" ADD COLUMN "current_revision" bigint DEFAULT 0;
+COMMENT ON COLUMN "sys_course"."current_revision" IS '当前线上修订版本号,0表示从未发布';
+
+ALTER TABLE "sys_course_record" ADD COLUMN "completed_revision" bigint DEFAULT 0;
+COMMENT ON COLUMN "sys_course_record"."completed_revision" IS '完成时课程的修订版本号';
@@ -0,0 +1,2 @@
+ALTER TABLE "sys_course" DROP COLUMN "unpublish_at";
+ALTER TABLE "sys_course" DROP COLUMN "publish_at";
@@ -0,0 +1,9 @@
+-- 课程定时发布和下架时间
+
+ALTER TABLE "sys_course" ADD COLUMN "publish_at" timestamptz;
+CREATE INDEX IF NOT EXISTS "idx_sys_course_publish_at" ON "sys_course" ("publish_at");
+COMMENT ON COLUMN "sys_course"."publish_at" IS '定时发布时间';
+
+ALTER TABLE "sys_course" ADD COLUMN "unpublish_at" timestamptz;
+CREATE INDEX IF NOT EXISTS "idx_sys_course_unpublish_at" ON "sys_course" ("unpublish_at");
+COMMENT ON COLUMN "sys_course"."unpublish_at" IS '定时下架时间';
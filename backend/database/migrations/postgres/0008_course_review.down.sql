@@ -0,0 +1,3 @@
+COMMENT ON COLUMN "sys_course"."status" IS '状态:0-草稿,1-已发布,2-已下架';
+DROP TABLE IF EXISTS "sys_course_transition";
+DROP TABLE IF EXISTS "sys_course_reviewer";
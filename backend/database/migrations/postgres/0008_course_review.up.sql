@@ -0,0 +1,35 @@
+-- 课程发布审核：审核人和状态流转记录
+
+CREATE TABLE IF NOT EXISTS "sys_course_reviewer" (
+  "id" bigserial,
+  "created_at" timestamptz,
+  "course_id" bigint NOT NULL,
+  "user_id" bigint NOT NULL,
+  PRIMARY KEY ("id")
+);
+CREATE INDEX IF NOT EXISTS "idx_sys_course_reviewer_user_id" ON "sys_course_reviewer" ("user_id");
+CREATE UNIQUE INDEX IF NOT EXISTS "idx_course_reviewer" ON "sys_course_reviewer" ("course_id","user_id");
+COMMENT ON COLUMN "sys_course_reviewer"."course_id" IS '课程ID';
+COMMENT ON COLUMN "sys_course_reviewer"."user_id" IS '审核人用户ID';
+
+CREATE TABLE IF NOT EXISTS "sys_course_transition" (
+  "id" bigserial,
+  "created_at" timestamptz,
+  "course_id" bigint NOT NULL,
+  "action" varchar(20) NOT NULL,
+  "from_status" bigint NOT NULL,
+  "to_status" bigint NOT NULL,
+  "operator_id" bigint,
+  "comment" text,
+  PRIMARY KEY ("id")
+);
+CREATE INDEX IF NOT EXISTS "idx_sys_course_transition_course_id" ON "sys_course_transition" ("course_id");
+CREATE INDEX IF NOT EXISTS "idx_sys_course_transition_created_at" ON "sys_course_transition" ("created_at");
+COMMENT ON COLUMN "sys_course_transition"."course_id" IS '课程ID';
+COMMENT ON COLUMN "sys_course_transition"."action" IS '操作:submit,withdraw,approve,reject,publish,unpublish';
+COMMENT ON COLUMN "sys_course_transition"."from_status" IS '操作前状态';
+COMMENT ON COLUMN "sys_course_transition"."to_status" IS '操作后状态';
+COMMENT ON COLUMN "sys_course_transition"."operator_id" IS '操作人用户ID,0表示系统调度';
+COMMENT ON COLUMN "sys_course_transition"."comment" IS '审核意见或备注';
+
+COMMENT ON COLUMN "sys_course"."status" IS '状态:0-草稿,1-已发布,2-已下架,3-待审核,4-已驳回,5-待发布';
@@ -0,0 +1,17 @@
+-- 课程负责人和协作讲师
+
+CREATE TABLE IF NOT EXISTS "sys_course_instructor" (
+  "id" bigserial,
+  "created_at" timestamptz,
+  "course_id" bigint NOT NULL,
+  "user_id" bigint NOT NULL,
+  PRIMARY KEY ("id")
+);
+CREATE INDEX IF NOT EXISTS "idx_sys_course_instructor_user_id" ON "sys_course_instructor" ("user_id");
+CREATE UNIQUE INDEX IF NOT EXISTS "idx_course_instructor" ON "sys_course_instructor" ("course_id","user_id");
+COMMENT ON COLUMN "sys_course_instructor"."course_id" IS '课程ID';
+COMMENT ON COLUMN "sys_course_instructor"."user_id" IS '讲师用户ID';
+
+ALTER TABLE "sys_course" ADD COLUMN "owner_id" bigint;
+CREATE INDEX IF NOT EXISTS "idx_sys_course_owner_id" ON "sys_course" ("owner_id");
+COMMENT ON COLUMN "sys_course"."owner_id" IS '课程负责人用户ID,0表示仅管理员可管理';
@@ -0,0 +1,19 @@
+-- 学习数据日汇总
+
+CREATE TABLE IF NOT EXISTS "sys_learning_daily_stat" (
+  "id" bigserial,
+  "created_at" timestamptz,
+  "updated_at" timestamptz,
+  "stat_date" varchar(10) NOT NULL,
+  "course_id" bigint NOT NULL,
+  "active_learners" bigint DEFAULT 0,
+  "events" bigint DEFAULT 0,
+  "completions" bigint DEFAULT 0,
+  PRIMARY KEY ("id")
+);
+CREATE UNIQUE INDEX IF NOT EXISTS "idx_stat_date_course" ON "sys_learning_daily_stat" ("stat_date","course_id");
+COMMENT ON COLUMN "sys_learning_daily_stat"."stat_date" IS '统计日期(YYYY-MM-DD)';
+COMMENT ON COLUMN "sys_learning_daily_stat"."course_id" IS '课程ID,0表示全部课程';
+COMMENT ON COLUMN "sys_learning_daily_stat"."active_learners" IS '活跃学员数';
+COMMENT ON COLUMN "sys_learning_daily_stat"."events" IS '学习事件数';
+COMMENT ON COLUMN "sys_learning_daily_stat"."completions" IS '完成次数';
@@ -0,0 +1,30 @@
+-- 导出任务
+
+CREATE TABLE IF NOT EXISTS "sys_export_job" (
+  "id" bigserial,
+  "created_at" timestamptz,
+  "updated_at" timestamptz,
+  "user_id" bigint NOT NULL,
+  "kind" varchar(50) NOT NULL,
+  "format" varchar(10) NOT NULL,
+  "params" text,
+  "status" varchar(20) NOT NULL,
+  "row_count" bigint DEFAULT 0,
+  "file_name" varchar(200),
+  "file_path" varchar(500),
+  "error" text,
+  "finished_at" timestamptz,
+  PRIMARY KEY ("id")
+);
+CREATE INDEX IF NOT EXISTS "idx_sys_export_job_status" ON "sys_export_job" ("status");
+CREATE INDEX IF NOT EXISTS "idx_sys_export_job_user_id" ON "sys_export_job" ("user_id");
+COMMENT ON COLUMN "sys_export_job"."format" IS '文件格式:csv,xlsx';
+COMMENT ON COLUMN "sys_export_job"."params" IS '筛选条件(JSON)';
+COMMENT ON COLUMN "sys_export_job"."status" IS '状态:pending,running,done,failed';
+COMMENT ON COLUMN "sys_export_job"."file_name" IS '下载文件名';
+COMMENT ON COLUMN "sys_export_job"."file_path" IS '文件存储路径(相对上传目录)';
+COMMENT ON COLUMN "sys_export_job"."user_id" IS '发起导出的用户ID';
+COMMENT ON COLUMN "sys_export_job"."row_count" IS '导出行数';
+COMMENT ON COLUMN "sys_export_job"."error" IS '失败原因';
+COMMENT ON COLUMN "sys_export_job"."finished_at" IS '完成时间';
+COMMENT ON COLUMN "sys_export_job"."kind" IS '导出内容:course-progress,user-progress,users,courses';
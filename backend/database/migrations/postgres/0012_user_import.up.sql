@@ -0,0 +1,26 @@
+-- 用户批量导入记录
+
+CREATE TABLE IF NOT EXISTS "sys_user_import" (
+  "id" bigserial,
+  "created_at" timestamptz,
+  "operator_id" bigint NOT NULL,
+  "file_name" varchar(200),
+  "dry_run" boolean,
+  "imported" boolean,
+  "total" bigint DEFAULT 0,
+  "created" bigint DEFAULT 0,
+  "updated" bigint DEFAULT 0,
+  "failed" bigint DEFAULT 0,
+  "report_path" varchar(500),
+  PRIMARY KEY ("id")
+);
+CREATE INDEX IF NOT EXISTS "idx_sys_user_import_operator_id" ON "sys_user_import" ("operator_id");
+COMMENT ON COLUMN "sys_user_import"."operator_id" IS '操作人用户ID';
+COMMENT ON COLUMN "sys_user_import"."dry_run" IS '是否仅预检';
+COMMENT ON COLUMN "sys_user_import"."total" IS '数据行数';
+COMMENT ON COLUMN "sys_user_import"."created" IS '新建用户数';
+COMMENT ON COLUMN "sys_user_import"."updated" IS '更新用户数';
+COMMENT ON COLUMN "sys_user_import"."report_path" IS '结果报告路径(相对上传目录)';
+COMMENT ON COLUMN "sys_user_import"."file_name" IS '上传文件名';
+COMMENT ON COLUMN "sys_user_import"."imported" IS '是否已写入数据库';
+COMMENT ON COLUMN "sys_user_import"."failed" IS '校验失败行数';
@@ -0,0 +1,9 @@
+-- 编号计数器（用户工号）
+
+CREATE TABLE IF NOT EXISTS "sys_sequence" (
+  "name" varchar(50),
+  "value" bigint NOT NULL DEFAULT 0,
+  PRIMARY KEY ("name")
+);
+COMMENT ON COLUMN "sys_sequence"."value" IS '最后分配的值';
+COMMENT ON COLUMN "sys_sequence"."name" IS '计数器名称';
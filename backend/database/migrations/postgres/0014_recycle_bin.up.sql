@@ -0,0 +1,20 @@
+-- 回收站
+
+CREATE TABLE IF NOT EXISTS "sys_recycle_bin" (
+  "id" bigserial,
+  "created_at" timestamptz,
+  "kind" varchar(20) NOT NULL,
+  "record_id" bigint NOT NULL,
+  "name" varchar(200),
+  "unique_keys" text,
+  "operator_id" bigint,
+  PRIMARY KEY ("id")
+);
+CREATE UNIQUE INDEX IF NOT EXISTS "idx_recycle_kind_record" ON "sys_recycle_bin" ("kind","record_id");
+CREATE INDEX IF NOT EXISTS "idx_sys_recycle_bin_created_at" ON "sys_recycle_bin" ("created_at");
+COMMENT ON COLUMN "sys_recycle_bin"."created_at" IS '删除时间';
+COMMENT ON COLUMN "sys_recycle_bin"."kind" IS '数据类型:user,role,course,rule';
+COMMENT ON COLUMN "sys_recycle_bin"."record_id" IS '数据ID';
+COMMENT ON COLUMN "sys_recycle_bin"."name" IS '显示名称';
+COMMENT ON COLUMN "sys_recycle_bin"."unique_keys" IS '原唯一键值(JSON)';
+COMMENT ON COLUMN "sys_recycle_bin"."operator_id" IS '删除人用户ID,0表示系统';
@@ -0,0 +1,4 @@
+COMMENT ON COLUMN "sys_course_transition"."action" IS '操作:submit,withdraw,approve,reject,publish,unpublish';
+ALTER TABLE "sys_course_draft" DROP COLUMN "note";
+ALTER TABLE "sys_course_draft" DROP COLUMN "submitted_at";
+ALTER TABLE "sys_course_draft" DROP COLUMN "submitted_by";
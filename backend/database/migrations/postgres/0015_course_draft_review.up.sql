@@ -0,0 +1,13 @@
+-- 线上课程的草稿提交审核，审核通过后才生成新的修订版本
+
+ALTER TABLE "sys_course_draft" ADD COLUMN "submitted_by" bigint DEFAULT 0;
+COMMENT ON COLUMN "sys_course_draft"."submitted_by" IS '提交审核人用户ID';
+
+ALTER TABLE "sys_course_draft" ADD COLUMN "submitted_at" timestamptz;
+CREATE INDEX IF NOT EXISTS "idx_sys_course_draft_submitted_at" ON "sys_course_draft" ("submitted_at");
+COMMENT ON COLUMN "sys_course_draft"."submitted_at" IS '提交审核时间,为空表示未提交审核';
+
+ALTER TABLE "sys_course_draft" ADD COLUMN "note" varchar(500);
+COMMENT ON COLUMN "sys_course_draft"."note" IS '发布说明';
+
+COMMENT ON COLUMN "sys_course_transition"."action" IS '操作:submit,withdraw,approve,reject,publish,unpublish及对应的*_revision';
@@ -0,0 +1,128 @@
+-- 初始表结构，与 MySQL 迁移 0001_init 保持一致
+-- 此后的表结构变更见 0003 及之后的迁移
+
+CREATE TABLE IF NOT EXISTS `sys_user` (
+  `id` integer,
+  `created_at` datetime,
+  `updated_at` datetime,
+  `deleted_at` datetime,
+  `username` text NOT NULL,
+  `password` text NOT NULL,
+  `name` text,
+  `email` text,
+  `phone` text,
+  `avatar` text,
+  `user_id` text,
+  `status` integer DEFAULT 1,
+  `access` text,
+  `signature` text,
+  `title` text,
+  `group_id` integer,
+  `country` text,
+  `province` text,
+  `city` text,
+  `address` text,
+  PRIMARY KEY (`id`)
+);
+CREATE UNIQUE INDEX IF NOT EXISTS `idx_sys_user_user_id` ON `sys_user`(`user_id`);
+CREATE UNIQUE INDEX IF NOT EXISTS `idx_sys_user_username` ON `sys_user`(`username`);
+CREATE INDEX IF NOT EXISTS `idx_sys_user_deleted_at` ON `sys_user`(`deleted_at`);
+
+CREATE TABLE IF NOT EXISTS `sys_role` (
+  `id` integer,
+  `created_at` datetime,
+  `updated_at` datetime,
+  `deleted_at` datetime,
+  `code` text NOT NULL,
+  `name` text NOT NULL,
+  `description` text,
+  `status` integer DEFAULT 1,
+  PRIMARY KEY (`id`)
+);
+CREATE UNIQUE INDEX IF NOT EXISTS `idx_sys_role_code` ON `sys_role`(`code`);
+CREATE INDEX IF NOT EXISTS `idx_sys_role_deleted_at` ON `sys_role`(`deleted_at`);
+
+CREATE TABLE IF NOT EXISTS `sys_menu` (
+  `id` integer,
+  `created_at` datetime,
+  `updated_at` datetime,
+  `deleted_at` datetime,
+  `parent_id` integer DEFAULT 0,
+  `name` text NOT NULL,
+  `path` text,
+  `component` text,
+  `icon` text,
+  `sort_order` integer DEFAULT 0,
+  `access` text,
+  `redirect` text,
+  `layout` integer DEFAULT 1,
+  `hidden` integer DEFAULT 0,
+  `status` integer DEFAULT 1,
+  PRIMARY KEY (`id`)
+);
+CREATE INDEX IF NOT EXISTS `idx_sys_menu_deleted_at` ON `sys_menu`(`deleted_at`);
+
+CREATE TABLE IF NOT EXISTS `sys_rule` (
+  `id` integer,
+  `created_at` datetime,
+  `updated_at` datetime,
+  `deleted_at` datetime,
+  `name` text NOT NULL,
+  `desc` text,
+  `status` integer DEFAULT 1,
+  `call_no` integer DEFAULT 0,
+  `owner` text,
+  `avatar` text,
+  `href` text,
+  `disabled` numeric DEFAULT false,
+  `progress` integer DEFAULT 0,
+  PRIMARY KEY (`id`)
+);
+CREATE INDEX IF NOT EXISTS `idx_sys_rule_deleted_at` ON `sys_rule`(`deleted_at`);
+
+CREATE TABLE IF NOT EXISTS `sys_user_role` (
+  `user_id` integer,
+  `role_id` integer,
+  PRIMARY KEY (`user_id`,`role_id`)
+);
+
+CREATE TABLE IF NOT EXISTS `sys_role_menu` (
+  `role_id` integer,
+  `menu_id` integer,
+  PRIMARY KEY (`role_id`,`menu_id`)
+);
+
+CREATE TABLE IF NOT EXISTS `sys_course` (
+  `id` integer,
+  `created_at` datetime,
+  `updated_at` datetime,
+  `deleted_at` datetime,
+  `title` text NOT NULL,
+  `description` text,
+  `cover_image` text,
+  `content_type` integer DEFAULT 1,
+  `video_url` text,
+  `text_content` text,
+  `duration` integer DEFAULT 0,
+  `status` integer DEFAULT 0,
+  `sort_order` integer DEFAULT 0,
+  PRIMARY KEY (`id`)
+);
+CREATE INDEX IF NOT EXISTS `idx_sys_course_deleted_at` ON `sys_course`(`deleted_at`);
+
+CREATE TABLE IF NOT EXISTS `sys_course_record` (
+  `id` integer,
+  `created_at` datetime,
+  `updated_at` datetime,
+  `deleted_at` datetime,
+  `user_id` integer NOT NULL,
+  `course_id` integer NOT NULL,
+  `progress` integer DEFAULT 0,
+  `duration` integer DEFAULT 0,
+  `is_completed` numeric DEFAULT false,
+  `completed_at` datetime,
+  `last_study_at` datetime,
+  PRIMARY KEY (`id`)
+);
+CREATE UNIQUE INDEX IF NOT EXISTS `idx_user_course` ON `sys_course_record`(`user_id`,`course_id`);
+CREATE INDEX IF NOT EXISTS `idx_sys_course_record_deleted_at` ON `sys_course_record`(`deleted_at`);
@@ -0,0 +1 @@
+DROP TABLE IF EXISTS `sys_learning_event`;
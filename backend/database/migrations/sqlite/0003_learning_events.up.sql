@@ -0,0 +1,18 @@
+-- 学习事件日志
+
+CREATE TABLE IF NOT EXISTS `sys_learning_event` (
+  `id` integer,
+  `created_at` datetime,
+  `user_id` integer NOT NULL,
+  `course_id` integer NOT NULL,
+  `verb` text NOT NULL,
+  `position` integer DEFAULT 0,
+  `from_position` integer,
+  `progress` integer DEFAULT 0,
+  `duration` integer DEFAULT 0,
+  `occurred_at` datetime NOT NULL,
+  PRIMARY KEY (`id`)
+);
+CREATE INDEX IF NOT EXISTS `idx_event_course_time` ON `sys_learning_event`(`course_id`,`occurred_at`);
+CREATE INDEX IF NOT EXISTS `idx_event_user_time` ON `sys_learning_event`(`user_id`,`occurred_at`);
+CREATE INDEX IF NOT EXISTS `idx_sys_learning_event_verb` ON `sys_learning_event`(`verb`);
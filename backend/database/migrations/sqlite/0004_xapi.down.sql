@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS `sys_xapi_statement`;
+DROP TABLE IF EXISTS `sys_xapi_activity`;
+DROP TABLE IF EXISTS `sys_xapi_client`;
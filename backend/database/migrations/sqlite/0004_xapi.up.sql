@@ -0,0 +1,54 @@
+-- xAPI 客户端、活动映射和语句
+
+CREATE TABLE IF NOT EXISTS `sys_xapi_client` (
+  `id` integer,
+  `created_at` datetime,
+  `updated_at` datetime,
+  `deleted_at` datetime,
+  `name` text NOT NULL,
+  `client_key` text NOT NULL,
+  `secret` text NOT NULL,
+  `status` integer DEFAULT 1,
+  `can_read` numeric,
+  `can_write` numeric,
+  PRIMARY KEY (`id`)
+);
+CREATE UNIQUE INDEX IF NOT EXISTS `idx_sys_xapi_client_key` ON `sys_xapi_client`(`client_key`);
+CREATE INDEX IF NOT EXISTS `idx_sys_xapi_client_deleted_at` ON `sys_xapi_client`(`deleted_at`);
+
+CREATE TABLE IF NOT EXISTS `sys_xapi_activity` (
+  `id` integer,
+  `created_at` datetime,
+  `updated_at` datetime,
+  `activity_id` text NOT NULL,
+  `course_id` integer NOT NULL,
+  PRIMARY KEY (`id`)
+);
+CREATE INDEX IF NOT EXISTS `idx_sys_xapi_activity_course_id` ON `sys_xapi_activity`(`course_id`);
+CREATE UNIQUE INDEX IF NOT EXISTS `idx_sys_xapi_activity_activity_id` ON `sys_xapi_activity`(`activity_id`);
+
+CREATE TABLE IF NOT EXISTS `sys_xapi_statement` (
+  `id` integer,
+  `created_at` datetime,
+  `statement_id` text NOT NULL,
+  `client_id` integer,
+  `actor_key` text,
+  `user_id` integer,
+  `verb_id` text,
+  `activity_id` text,
+  `registration` text,
+  `timestamp` datetime,
+  `stored` datetime,
+  `voided` numeric DEFAULT false,
+  `raw` text NOT NULL,
+  PRIMARY KEY (`id`)
+);
+CREATE INDEX IF NOT EXISTS `idx_sys_xapi_statement_voided` ON `sys_xapi_statement`(`voided`);
+CREATE INDEX IF NOT EXISTS `idx_sys_xapi_statement_stored` ON `sys_xapi_statement`(`stored`);
+CREATE INDEX IF NOT EXISTS `idx_sys_xapi_statement_user_id` ON `sys_xapi_statement`(`user_id`);
+CREATE INDEX IF NOT EXISTS `idx_sys_xapi_statement_actor_key` ON `sys_xapi_statement`(`actor_key`);
+CREATE INDEX IF NOT EXISTS `idx_sys_xapi_statement_client_id` ON `sys_xapi_statement`(`client_id`);
+CREATE UNIQUE INDEX IF NOT EXISTS `idx_sys_xapi_statement_statement_id` ON `sys_xapi_statement`(`statement_id`);
+CREATE INDEX IF NOT EXISTS `idx_sys_xapi_statement_registration` ON `sys_xapi_statement`(`registration`);
+CREATE INDEX IF NOT EXISTS `idx_sys_xapi_statement_activity_id` ON `sys_xapi_statement`(`activity_id`);
+CREATE INDEX IF NOT EXISTS `idx_sys_xapi_statement_verb_id` ON `sys_xapi_statement`(`verb_id`);
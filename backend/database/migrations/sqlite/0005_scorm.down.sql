@@ -0,0 +1,3 @@
+ALTER TABLE `sys_course_record` DROP COLUMN `score`;
+DROP TABLE IF EXISTS `sys_scorm_runtime_value`;
+DROP TABLE IF EXISTS `sys_scorm_package`;
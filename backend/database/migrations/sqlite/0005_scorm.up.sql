@@ -0,0 +1,32 @@
+-- SCORM 课件包和运行时数据，学习记录增加成绩
+
+CREATE TABLE IF NOT EXISTS `sys_scorm_package` (
+  `id` integer,
+  `created_at` datetime,
+  `updated_at` datetime,
+  `deleted_at` datetime,
+  `course_id` integer NOT NULL,
+  `version` text NOT NULL,
+  `identifier` text,
+  `title` text,
+  `entry_href` text NOT NULL,
+  `storage_dir` text NOT NULL,
+  `file_size` integer DEFAULT 0,
+  PRIMARY KEY (`id`)
+);
+CREATE INDEX IF NOT EXISTS `idx_sys_scorm_package_course_id` ON `sys_scorm_package`(`course_id`);
+CREATE INDEX IF NOT EXISTS `idx_sys_scorm_package_deleted_at` ON `sys_scorm_package`(`deleted_at`);
+
+CREATE TABLE IF NOT EXISTS `sys_scorm_runtime_value` (
+  `id` integer,
+  `created_at` datetime,
+  `updated_at` datetime,
+  `user_id` integer NOT NULL,
+  `package_id` integer NOT NULL,
+  `element` text NOT NULL,
+  `value` text,
+  PRIMARY KEY (`id`)
+);
+CREATE UNIQUE INDEX IF NOT EXISTS `idx_scorm_user_element` ON `sys_scorm_runtime_value`(`user_id`,`package_id`,`element`);
+
+ALTER TABLE `sys_course_record` ADD COLUMN `score` real;
@@ -0,0 +1,4 @@
+ALTER TABLE `sys_course_record` DROP COLUMN `completed_revision`;
+ALTER TABLE `sys_course` DROP COLUMN `current_revision`;
+DROP TABLE IF EXISTS `sys_course_draft`;
+DROP TABLE IF EXISTS `sys_course_revision`;
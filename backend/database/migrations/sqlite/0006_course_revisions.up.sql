@@ -0,0 +1,41 @@
+-- 课程修订版本和草稿，课程增加当前修订版本号，学习记录增加完成时的修订版本号
+
+CREATE TABLE IF NOT EXISTS `sys_course_revision` (
+  `id` integer,
+  `created_at` datetime,
+  `course_id` integer NOT NULL,
+  `revision` integer NOT NULL,
+  `published_by` integer,
+  `note` text,
+  `title` text NOT NULL,
+  `description` text,
+  `cover_image` text,
+  `content_type` integer DEFAULT 1,
+  `video_url` text,
+  `text_content` text,
+  `duration` integer DEFAULT 0,
+  PRIMARY KEY (`id`)
+);
+CREATE UNIQUE INDEX IF NOT EXISTS `idx_course_revision` ON `sys_course_revision`(`course_id`,`revision`);
+
+CREATE TABLE IF NOT EXISTS `sys_course_draft` (
+  `id` integer,
+  `created_at` datetime,
+  `updated_at` datetime,
+  `course_id` integer NOT NULL,
+  `base_revision` integer DEFAULT 0,
+  `updated_by` integer,
+  `title` text NOT NULL,
+  `description` text,
+  `cover_image` text,
+  `content_type` integer DEFAULT 1,
+  `video_url` text,
+  `text_content` text,
+  `duration` integer DEFAULT 0,
+  PRIMARY KEY (`id`)
+);
+CREATE UNIQUE INDEX IF NOT EXISTS `idx_sys_course_draft_course_id` ON `sys_course_draft`(`course_id`);
+
+ALTER TABLE `sys_course` ADD COLUMN `current_revision` integer DEFAULT 0;
+
+ALTER TABLE `sys_course_record` ADD COLUMN `completed_revision` integer DEFAULT 0;
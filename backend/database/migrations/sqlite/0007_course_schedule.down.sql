@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS `idx_sys_course_unpublish_at`;
+ALTER TABLE `sys_course` DROP COLUMN `unpublish_at`;
+DROP INDEX IF EXISTS `idx_sys_course_publish_at`;
+ALTER TABLE `sys_course` DROP COLUMN `publish_at`;
@@ -0,0 +1,7 @@
+-- 课程定时发布和下架时间
+
+ALTER TABLE `sys_course` ADD COLUMN `publish_at` datetime;
+CREATE INDEX IF NOT EXISTS `idx_sys_course_publish_at` ON `sys_course`(`publish_at`);
+
+ALTER TABLE `sys_course` ADD COLUMN `unpublish_at` datetime;
+CREATE INDEX IF NOT EXISTS `idx_sys_course_unpublish_at` ON `sys_course`(`unpublish_at`);
@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS `sys_course_transition`;
+DROP TABLE IF EXISTS `sys_course_reviewer`;
@@ -0,0 +1,25 @@
+-- 课程发布审核：审核人和状态流转记录
+
+CREATE TABLE IF NOT EXISTS `sys_course_reviewer` (
+  `id` integer,
+  `created_at` datetime,
+  `course_id` integer NOT NULL,
+  `user_id` integer NOT NULL,
+  PRIMARY KEY (`id`)
+);
+CREATE INDEX IF NOT EXISTS `idx_sys_course_reviewer_user_id` ON `sys_course_reviewer`(`user_id`);
+CREATE UNIQUE INDEX IF NOT EXISTS `idx_course_reviewer` ON `sys_course_reviewer`(`course_id`,`user_id`);
+
+CREATE TABLE IF NOT EXISTS `sys_course_transition` (
+  `id` integer,
+  `created_at` datetime,
+  `course_id` integer NOT NULL,
+  `action` text NOT NULL,
+  `from_status` integer NOT NULL,
+  `to_status` integer NOT NULL,
+  `operator_id` integer,
+  `comment` text,
+  PRIMARY KEY (`id`)
+);
+CREATE INDEX IF NOT EXISTS `idx_sys_course_transition_course_id` ON `sys_course_transition`(`course_id`);
+CREATE INDEX IF NOT EXISTS `idx_sys_course_transition_created_at` ON `sys_course_transition`(`created_at`);
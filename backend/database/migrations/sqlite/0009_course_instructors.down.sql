@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS `idx_sys_course_owner_id`;
+ALTER TABLE `sys_course` DROP COLUMN `owner_id`;
+DROP TABLE IF EXISTS `sys_course_instructor`;
@@ -0,0 +1,14 @@
+-- 课程负责人和协作讲师
+
+CREATE TABLE IF NOT EXISTS `sys_course_instructor` (
+  `id` integer,
+  `created_at` datetime,
+  `course_id` integer NOT NULL,
+  `user_id` integer NOT NULL,
+  PRIMARY KEY (`id`)
+);
+CREATE INDEX IF NOT EXISTS `idx_sys_course_instructor_user_id` ON `sys_course_instructor`(`user_id`);
+CREATE UNIQUE INDEX IF NOT EXISTS `idx_course_instructor` ON `sys_course_instructor`(`course_id`,`user_id`);
+
+ALTER TABLE `sys_course` ADD COLUMN `owner_id` integer;
+CREATE INDEX IF NOT EXISTS `idx_sys_course_owner_id` ON `sys_course`(`owner_id`);
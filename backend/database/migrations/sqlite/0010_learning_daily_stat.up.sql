@@ -0,0 +1,14 @@
+-- 学习数据日汇总
+
+CREATE TABLE IF NOT EXISTS `sys_learning_daily_stat` (
+  `id` integer,
+  `created_at` datetime,
+  `updated_at` datetime,
+  `stat_date` text NOT NULL,
+  `course_id` integer NOT NULL,
+  `active_learners` integer DEFAULT 0,
+  `events` integer DEFAULT 0,
+  `completions` integer DEFAULT 0,
+  PRIMARY KEY (`id`)
+);
+CREATE UNIQUE INDEX IF NOT EXISTS `idx_stat_date_course` ON `sys_learning_daily_stat`(`stat_date`,`course_id`);
@@ -0,0 +1,20 @@
+-- 导出任务
+
+CREATE TABLE IF NOT EXISTS `sys_export_job` (
+  `id` integer,
+  `created_at` datetime,
+  `updated_at` datetime,
+  `user_id` integer NOT NULL,
+  `kind` text NOT NULL,
+  `format` text NOT NULL,
+  `params` text,
+  `status` text NOT NULL,
+  `row_count` integer DEFAULT 0,
+  `file_name` text,
+  `file_path` text,
+  `error` text,
+  `finished_at` datetime,
+  PRIMARY KEY (`id`)
+);
+CREATE INDEX IF NOT EXISTS `idx_sys_export_job_user_id` ON `sys_export_job`(`user_id`);
+CREATE INDEX IF NOT EXISTS `idx_sys_export_job_status` ON `sys_export_job`(`status`);
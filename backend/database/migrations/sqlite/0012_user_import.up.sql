@@ -0,0 +1,17 @@
+-- 用户批量导入记录
+
+CREATE TABLE IF NOT EXISTS `sys_user_import` (
+  `id` integer,
+  `created_at` datetime,
+  `operator_id` integer NOT NULL,
+  `file_name` text,
+  `dry_run` numeric,
+  `imported` numeric,
+  `total` integer DEFAULT 0,
+  `created` integer DEFAULT 0,
+  `updated` integer DEFAULT 0,
+  `failed` integer DEFAULT 0,
+  `report_path` text,
+  PRIMARY KEY (`id`)
+);
+CREATE INDEX IF NOT EXISTS `idx_sys_user_import_operator_id` ON `sys_user_import`(`operator_id`);
@@ -0,0 +1,7 @@
+-- 编号计数器（用户工号）
+
+CREATE TABLE IF NOT EXISTS `sys_sequence` (
+  `name` text,
+  `value` integer NOT NULL DEFAULT 0,
+  PRIMARY KEY (`name`)
+);
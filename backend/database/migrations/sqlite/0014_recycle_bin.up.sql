@@ -0,0 +1,14 @@
+-- 回收站
+
+CREATE TABLE IF NOT EXISTS `sys_recycle_bin` (
+  `id` integer,
+  `created_at` datetime,
+  `kind` text NOT NULL,
+  `record_id` integer NOT NULL,
+  `name` text,
+  `unique_keys` text,
+  `operator_id` integer,
+  PRIMARY KEY (`id`)
+);
+CREATE UNIQUE INDEX IF NOT EXISTS `idx_recycle_kind_record` ON `sys_recycle_bin`(`kind`,`record_id`);
+CREATE INDEX IF NOT EXISTS `idx_sys_recycle_bin_created_at` ON `sys_recycle_bin`(`created_at`);
@@ -0,0 +1,4 @@
+ALTER TABLE `sys_course_draft` DROP COLUMN `note`;
+DROP INDEX IF EXISTS `idx_sys_course_draft_submitted_at`;
+ALTER TABLE `sys_course_draft` DROP COLUMN `submitted_at`;
+ALTER TABLE `sys_course_draft` DROP COLUMN `submitted_by`;
@@ -0,0 +1,6 @@
+-- 线上课程的草稿提交审核，审核通过后才生成新的修订版本
+
+ALTER TABLE `sys_course_draft` ADD COLUMN `submitted_by` integer DEFAULT 0;
+ALTER TABLE `sys_course_draft` ADD COLUMN `submitted_at` datetime;
+CREATE INDEX IF NOT EXISTS `idx_sys_course_draft_submitted_at` ON `sys_course_draft`(`submitted_at`);
+ALTER TABLE `sys_course_draft` ADD COLUMN `note` text;
@@ -0,0 +1,15 @@
+package database
+
+import (
+	"strings"
+
+	"gorm.io/gorm"
+)
+
+// Contains 不区分大小写的模糊搜索
+// MySQL 默认排序规则不区分大小写，PostgreSQL 的 LIKE 区分大小写，统一转小写后比较保证各数据库结果一致
+func Contains(column, keyword string) func(*gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		return db.Where("LOWER("+column+") LIKE ?", "%"+strings.ToLower(keyword)+"%")
+	}
+}
@@ -0,0 +1,122 @@
+package logging
+
+import (
+	"context"
+	"log/slog"
+	"os"
+	"regexp"
+	"strings"
+
+	"go.opentelemetry.io/otel/trace"
+)
+
+// RequestIDHeader 请求 ID 的请求头和响应头
+const RequestIDHeader = "X-Request-ID"
+
+// redacted 脱敏后的占位值
+const redacted = "[REDACTED]"
+
+// sensitiveKeys 属性名包含这些词时整体脱敏（不区分大小写）
+var sensitiveKeys = []string{"password", "secret", "token", "authorization", "cookie"}
+
+// bcryptHashPattern bcrypt 密码哈希，出现在日志消息或 SQL 中时替换
+var bcryptHashPattern = regexp.MustCompile(`\$2[abxy]?\$\d{2}\$[./A-Za-z0-9]{53}`)
+
+type requestIDKey struct{}
+
+// level 全局日志级别，可在配置加载后调整
+var level = new(slog.LevelVar)
+
+// Setup 将全局日志设置为输出到标准输出的 JSON 格式，级别为 info，加载配置后通过 SetLevel 调整
+// 标准库 log 包的输出也会经过该处理器（按 info 级别），同样进行脱敏
+func Setup() {
+	handler := slog.NewJSONHandler(os.Stdout, &slog.HandlerOptions{
+		Level:       level,
+		ReplaceAttr: redact,
+	})
+	slog.SetDefault(slog.New(contextHandler{handler}))
+}
+
+// SetLevel 设置全局日志级别：debug、info、warn 或 error
+func SetLevel(name string) {
+	level.Set(ParseLevel(name))
+}
+
+// ParseLevel 解析日志级别，无法识别时为 info
+func ParseLevel(name string) slog.Level {
+	switch name {
+	case "debug":
+		return slog.LevelDebug
+	case "warn":
+		return slog.LevelWarn
+	case "error":
+		return slog.LevelError
+	default:
+		return slog.LevelInfo
+	}
+}
+
+// WithRequestID 将请求 ID 写入 context，之后使用该 context 输出的日志都会带上 request_id
+func WithRequestID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, requestIDKey{}, id)
+}
+
+// RequestID 读取 context 中的请求 ID，不存在时为空
+func RequestID(ctx context.Context) string {
+	if ctx == nil {
+		return ""
+	}
+	id, _ := ctx.Value(requestIDKey{}).(string)
+	return id
+}
+
+// Redact 替换文本中的密码哈希
+func Redact(s string) string {
+	return bcryptHashPattern.ReplaceAllString(s, redacted)
+}
+
+// redact 敏感属性整体脱敏，其余字符串属性（包括日志消息）中的密码哈希替换为占位值
+func redact(groups []string, a slog.Attr) slog.Attr {
+	if isSensitiveKey(a.Key) {
+		return slog.String(a.Key, redacted)
+	}
+	if a.Value.Kind() == slog.KindString {
+		if s := a.Value.String(); bcryptHashPattern.MatchString(s) {
+			return slog.String(a.Key, Redact(s))
+		}
+	}
+	return a
+}
+
+func isSensitiveKey(key string) bool {
+	key = strings.ToLower(key)
+	for _, word := range sensitiveKeys {
+		if strings.Contains(key, word) {
+			return true
+		}
+	}
+	return false
+}
+
+// contextHandler 从 context 中取出请求 ID 和链路 ID 附加到每条日志
+type contextHandler struct {
+	slog.Handler
+}
+
+func (h contextHandler) Handle(ctx context.Context, r slog.Record) error {
+	if id := RequestID(ctx); id != "" {
+		r.AddAttrs(slog.String("request_id", id))
+	}
+	if sc := trace.SpanContextFromContext(ctx); sc.IsValid() {
+		r.AddAttrs(slog.String("trace_id", sc.TraceID().String()), slog.String("span_id", sc.SpanID().String()))
+	}
+	return h.Handler.Handle(ctx, r)
+}
+
+func (h contextHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return contextHandler{h.Handler.WithAttrs(attrs)}
+}
+
+func (h contextHandler) WithGroup(name string) slog.Handler {
+	return contextHandler{h.Handler.WithGroup(name)}
+}
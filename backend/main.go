@@ -1,27 +1,130 @@
 package main
 
 import (
-	"log"
+	"context"
+	"log/slog"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
 
 	"learn-hub-backend/config"
+	"learn-hub-backend/controllers"
 	"learn-hub-backend/database"
+	"learn-hub-backend/logging"
 	"learn-hub-backend/routes"
+	"learn-hub-backend/services"
+	"learn-hub-backend/tracing"
 )
 
 func main() {
-	// 加载配置
-	config.LoadConfig()
+	// 不带子命令时启动服务
+	args := os.Args[1:]
+	if len(args) == 0 {
+		args = []string{"serve"}
+	}
+	runCommand(args)
+}
+
+// serve 启动 HTTP 服务和后台任务，收到 SIGINT/SIGTERM 后优雅退出：
+// 停止接收新请求并等待进行中的请求、后台任务和导出任务完成，最长等待 ShutdownTimeoutSec 秒
+func serve() {
+	cfg := config.AppConfig
+	logging.SetLevel(cfg.LogLevel)
+
+	// 启动前检查端口、上传目录和数据库，任一失败立即退出
+	ln, err := net.Listen("tcp", ":"+cfg.Port)
+	if err != nil {
+		fatal("启动失败：无法监听端口", "port", cfg.Port, "error", err)
+	}
+	if err := os.MkdirAll(cfg.UploadDir, 0755); err != nil {
+		fatal("启动失败：无法创建上传目录", "dir", cfg.UploadDir, "error", err)
+	}
+	if err := database.InitDB(); err != nil {
+		fatal("启动失败", "error", err)
+	}
 
-	// 初始化数据库
-	database.InitDB()
+	shutdownTracing, err := tracing.Setup(context.Background(), cfg)
+	if err != nil {
+		fatal("启动失败：链路追踪初始化失败", "error", err)
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
 
-	// 设置路由
-	r := routes.SetupRoutes()
+	// 上次未完成的导出任务标记为失败
+	exportService := &services.ExportService{}
+	if err := exportService.RecoverJobs(); err != nil {
+		slog.Error("恢复导出任务失败", "error", err)
+	}
 
-	// 启动服务器
-	port := ":" + config.AppConfig.Port
-	log.Printf("服务器启动在端口 %s", port)
-	if err := r.Run(port); err != nil {
-		log.Fatal("服务器启动失败:", err)
+	workerCtx, stopWorkers := context.WithCancel(context.Background())
+	defer stopWorkers()
+	workers := []<-chan struct{}{
+		// 定时上下架调度器
+		services.NewCourseScheduler().Start(workerCtx),
+		// 回收站清理任务
+		services.NewRecyclePurger(cfg.RecycleRetentionDays).Start(workerCtx),
+	}
+	// 学习数据日汇总任务（可选）
+	if cfg.AnalyticsRollup {
+		workers = append(workers, (&services.AnalyticsService{}).StartRollup(workerCtx, time.Hour))
 	}
+
+	srv := &http.Server{
+		Handler:           routes.SetupRoutes(),
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+	serveErr := make(chan error, 1)
+	go func() {
+		serveErr <- srv.Serve(ln)
+	}()
+	slog.Info("服务器已启动", "port", cfg.Port, "env", cfg.AppEnv)
+
+	select {
+	case err := <-serveErr:
+		fatal("服务器异常退出", "error", err)
+	case <-ctx.Done():
+	}
+	// 恢复默认信号处理，再次收到信号时立即退出
+	stop()
+
+	timeout := time.Duration(cfg.ShutdownTimeoutSec) * time.Second
+	slog.Info("收到退出信号，停止接收新请求", "timeout", timeout.String())
+	controllers.MarkShuttingDown()
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+	if err := srv.Shutdown(shutdownCtx); err != nil {
+		slog.Warn("等待进行中的请求超时", "error", err)
+	}
+
+	stopWorkers()
+	for _, done := range workers {
+		select {
+		case <-done:
+		case <-shutdownCtx.Done():
+		}
+	}
+	if err := exportService.WaitJobs(shutdownCtx); err != nil {
+		slog.Warn("等待导出任务超时，未完成的任务将在下次启动时标记为失败")
+	}
+
+	// 上报缓冲中的链路数据
+	if err := shutdownTracing(shutdownCtx); err != nil {
+		slog.Warn("上报链路数据失败", "error", err)
+	}
+
+	if err := database.Close(); err != nil {
+		slog.Error("关闭数据库连接失败", "error", err)
+	}
+	slog.Info("服务已退出")
+}
+
+// fatal 记录错误日志后退出
+func fatal(msg string, args ...interface{}) {
+	slog.Error(msg, args...)
+	os.Exit(1)
 }
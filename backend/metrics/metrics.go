@@ -0,0 +1,123 @@
+package metrics
+
+import (
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+// namespace 指标名前缀
+const namespace = "learnhub"
+
+// HTTP 请求指标，route 为路由模板（如 /api/course/:id），未匹配的路由记为 unmatched
+var (
+	HTTPRequests = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "http_requests_total",
+		Help:      "HTTP 请求数",
+	}, []string{"method", "route", "status"})
+
+	HTTPRequestDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "http_request_duration_seconds",
+		Help:      "HTTP 请求处理耗时",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"method", "route"})
+
+	HTTPRequestsInFlight = promauto.NewGauge(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "http_requests_in_flight",
+		Help:      "正在处理的 HTTP 请求数",
+	})
+)
+
+// 数据库查询指标，operation 为 create/query/update/delete/row/raw
+var (
+	DBQueryDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "db_query_duration_seconds",
+		Help:      "数据库语句执行耗时",
+		Buckets:   []float64{.001, .0025, .005, .01, .025, .05, .1, .25, .5, 1, 2.5},
+	}, []string{"operation", "table"})
+
+	DBQueryErrors = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "db_query_errors_total",
+		Help:      "数据库语句执行失败次数（不含记录不存在）",
+	}, []string{"operation", "table"})
+)
+
+// 业务指标
+var (
+	Logins = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "logins_total",
+		Help:      "登录次数，result 为 success、failure 或 disabled",
+	}, []string{"result"})
+
+	ProgressUpdates = promauto.NewCounter(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "progress_updates_total",
+		Help:      "学习进度更新次数",
+	})
+
+	CourseCompletions = promauto.NewCounter(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "course_completions_total",
+		Help:      "课程完成次数",
+	})
+)
+
+// 登录结果
+const (
+	LoginSuccess  = "success"
+	LoginFailure  = "failure"
+	LoginDisabled = "disabled"
+)
+
+// sessionWindow 最近一次认证请求在该时间内的用户计为活跃会话
+const sessionWindow = 15 * time.Minute
+
+// sessions 记录已认证用户最近一次请求的时间（仅统计本实例）
+var sessions = &sessionTracker{lastSeen: map[uint]time.Time{}}
+
+func init() {
+	promauto.NewGaugeFunc(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "active_sessions",
+		Help:      "最近 15 分钟内有认证请求的用户数（本实例）",
+	}, func() float64 {
+		return float64(sessions.active(time.Now()))
+	})
+}
+
+// TouchSession 记录用户的一次认证请求
+func TouchSession(userID uint) {
+	sessions.touch(userID, time.Now())
+}
+
+type sessionTracker struct {
+	mu       sync.Mutex
+	lastSeen map[uint]time.Time
+}
+
+func (t *sessionTracker) touch(userID uint, now time.Time) {
+	t.mu.Lock()
+	t.lastSeen[userID] = now
+	t.mu.Unlock()
+}
+
+// active 统计活跃用户数，同时清理过期记录
+func (t *sessionTracker) active(now time.Time) int {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	for userID, seen := range t.lastSeen {
+		if now.Sub(seen) > sessionWindow {
+			delete(t.lastSeen, userID)
+		}
+	}
+	return len(t.lastSeen)
+}
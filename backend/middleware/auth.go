@@ -1,6 +1,9 @@
 package middleware
 
 import (
+	"errors"
+	"learn-hub-backend/metrics"
+	"learn-hub-backend/services"
 	"learn-hub-backend/utils"
 	"strings"
 
@@ -37,8 +40,34 @@ func AuthMiddleware() gin.HandlerFunc {
 		c.Set("userId", claims.UserID)
 		c.Set("username", claims.Username)
 		c.Set("access", claims.Access)
+		metrics.TouchSession(claims.UserID)
 
 		c.Next()
 	}
 }
 
+// AdminMiddleware 管理员权限中间件，需在 AuthMiddleware 之后使用
+// 按数据库中的当前角色判断，令牌签发后被取消管理员角色或禁用的用户立即失去权限
+func AdminMiddleware() gin.HandlerFunc {
+	userService := &services.UserService{}
+
+	return func(c *gin.Context) {
+		userID, _ := c.Get("userId")
+		uid, _ := userID.(uint)
+
+		access, err := userService.CurrentAccess(c.Request.Context(), uid)
+		if err != nil && !errors.Is(err, services.ErrUserNotFound) {
+			utils.InternalError(c, "查询权限失败: "+err.Error())
+			c.Abort()
+			return
+		}
+		if access != services.AccessAdmin {
+			utils.Forbidden(c, "需要管理员权限")
+			c.Abort()
+			return
+		}
+
+		c.Set("access", access)
+		c.Next()
+	}
+}
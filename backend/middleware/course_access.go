@@ -0,0 +1,68 @@
+package middleware
+
+import (
+	"errors"
+	"learn-hub-backend/services"
+	"learn-hub-backend/utils"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// CourseScopeMiddleware 课程管理权限中间件：解析当前用户可管理的课程范围并存入上下文 courseScope
+// 管理员可管理全部课程，讲师只能管理自己负责或协作的课程，其他用户无权访问
+func CourseScopeMiddleware() gin.HandlerFunc {
+	accessService := &services.CourseAccessService{}
+
+	return func(c *gin.Context) {
+		userID, _ := c.Get("userId")
+		uid, _ := userID.(uint)
+
+		scope, err := accessService.Resolve(c.Request.Context(), uid)
+		if err != nil {
+			if errors.Is(err, services.ErrNoCourseAccess) {
+				utils.Forbidden(c, err.Error())
+			} else {
+				utils.InternalError(c, "查询权限失败: "+err.Error())
+			}
+			c.Abort()
+			return
+		}
+
+		c.Set("courseScope", scope)
+		c.Next()
+	}
+}
+
+// CourseOwnerMiddleware 校验路径参数 id 对应的课程在当前用户的管理范围内，需在 CourseScopeMiddleware 之后使用
+func CourseOwnerMiddleware() gin.HandlerFunc {
+	accessService := &services.CourseAccessService{}
+
+	return func(c *gin.Context) {
+		id, _ := strconv.ParseUint(c.Param("id"), 10, 32)
+		if id == 0 {
+			c.Next()
+			return
+		}
+
+		scope := c.MustGet("courseScope").(*services.CourseScope)
+		ok, err := accessService.CanManage(c.Request.Context(), scope, uint(id))
+		if err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				utils.Fail(c, utils.CodeCourseNotFound, "课程不存在")
+			} else {
+				utils.InternalError(c, "查询权限失败: "+err.Error())
+			}
+			c.Abort()
+			return
+		}
+		if !ok {
+			utils.Forbidden(c, "无权管理该课程")
+			c.Abort()
+			return
+		}
+
+		c.Next()
+	}
+}
@@ -0,0 +1,76 @@
+package middleware
+
+import (
+	"fmt"
+	"log/slog"
+	"net/http"
+	"runtime/debug"
+	"time"
+
+	"learn-hub-backend/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+// quietPaths 探针和指标接口的访问日志只在 debug 级别输出
+var quietPaths = map[string]bool{"/healthz": true, "/readyz": true, "/metrics": true}
+
+// AccessLogMiddleware 以结构化日志记录每个请求，5xx 为 error，4xx 为 warn，其余为 info
+// 只记录路径不记录查询参数，避免泄露参数中的 token 等信息
+func AccessLogMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		start := time.Now()
+		c.Next()
+
+		status := c.Writer.Status()
+		level := slog.LevelInfo
+		switch {
+		case status >= http.StatusInternalServerError:
+			level = slog.LevelError
+		case status >= http.StatusBadRequest:
+			level = slog.LevelWarn
+		case quietPaths[c.Request.URL.Path]:
+			level = slog.LevelDebug
+		}
+
+		attrs := []slog.Attr{
+			slog.String("method", c.Request.Method),
+			slog.String("path", c.Request.URL.Path),
+			slog.String("route", c.FullPath()),
+			slog.Int("status", status),
+			slog.Float64("latency_ms", float64(time.Since(start).Microseconds())/1000),
+			slog.String("client_ip", c.ClientIP()),
+			slog.Int("bytes", c.Writer.Size()),
+		}
+		if userID, ok := c.Get("userId"); ok {
+			attrs = append(attrs, slog.Any("user_id", userID))
+		}
+		if len(c.Errors) > 0 {
+			attrs = append(attrs, slog.String("error", c.Errors.String()))
+		}
+		slog.LogAttrs(c.Request.Context(), level, "HTTP 请求", attrs...)
+	}
+}
+
+// RecoveryMiddleware 捕获处理请求时的 panic，记录错误和调用栈后返回 500
+func RecoveryMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		defer func() {
+			if err := recover(); err != nil {
+				if err == http.ErrAbortHandler {
+					panic(err)
+				}
+				slog.ErrorContext(c.Request.Context(), "处理请求时发生 panic",
+					"error", fmt.Sprint(err),
+					"method", c.Request.Method,
+					"path", c.Request.URL.Path,
+					"stack", string(debug.Stack()))
+				if !c.Writer.Written() {
+					utils.InternalError(c, "服务器内部错误")
+				}
+				c.Abort()
+			}
+		}()
+		c.Next()
+	}
+}
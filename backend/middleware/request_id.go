@@ -0,0 +1,28 @@
+package middleware
+
+import (
+	"regexp"
+
+	"learn-hub-backend/logging"
+	"learn-hub-backend/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+// requestIDPattern 接受调用方传入的请求 ID 的格式，避免日志被注入任意内容
+var requestIDPattern = regexp.MustCompile(`^[A-Za-z0-9._-]{1,64}$`)
+
+// RequestIDMiddleware 为每个请求分配请求 ID：沿用请求头 X-Request-ID（格式合法时），否则生成新的 UUID
+// 请求 ID 写入响应头和请求 context，日志、SQL 日志和错误响应中都会带上
+func RequestIDMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id := c.GetHeader(logging.RequestIDHeader)
+		if !requestIDPattern.MatchString(id) {
+			id = utils.NewUUID()
+		}
+
+		c.Header(logging.RequestIDHeader, id)
+		c.Request = c.Request.WithContext(logging.WithRequestID(c.Request.Context(), id))
+		c.Next()
+	}
+}
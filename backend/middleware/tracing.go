@@ -0,0 +1,18 @@
+package middleware
+
+import (
+	"net/http"
+
+	"learn-hub-backend/tracing"
+
+	"github.com/gin-gonic/gin"
+	"go.opentelemetry.io/contrib/instrumentation/github.com/gin-gonic/gin/otelgin"
+)
+
+// TracingMiddleware 为每个请求创建以路由模板命名的 span，并从请求头 traceparent 延续上游链路
+// 探针和指标接口不记录
+func TracingMiddleware() gin.HandlerFunc {
+	return otelgin.Middleware(tracing.ServiceName, otelgin.WithFilter(func(r *http.Request) bool {
+		return !quietPaths[r.URL.Path]
+	}))
+}
@@ -0,0 +1,58 @@
+package middleware
+
+import (
+	"learn-hub-backend/services"
+	"learn-hub-backend/utils"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// XAPIMiddleware xAPI LRS 中间件：校验版本头和客户端 Basic 认证
+func XAPIMiddleware() gin.HandlerFunc {
+	xapiService := &services.XAPIService{}
+
+	return func(c *gin.Context) {
+		c.Header("X-Experience-API-Version", services.XAPIVersion)
+
+		version := c.GetHeader("X-Experience-API-Version")
+		if !strings.HasPrefix(version, "1.0") {
+			utils.BadRequest(c, "缺少或不支持的 X-Experience-API-Version 请求头")
+			c.Abort()
+			return
+		}
+
+		key, secret, ok := c.Request.BasicAuth()
+		if !ok {
+			c.Header("WWW-Authenticate", `Basic realm="xAPI"`)
+			utils.Unauthorized(c, "未提供 xAPI 客户端凭证")
+			c.Abort()
+			return
+		}
+
+		client, err := xapiService.Authenticate(c.Request.Context(), key, secret)
+		if err != nil {
+			c.Header("WWW-Authenticate", `Basic realm="xAPI"`)
+			utils.Unauthorized(c, err.Error())
+			c.Abort()
+			return
+		}
+
+		// 按客户端权限限制读写
+		if c.Request.Method == http.MethodGet && !client.CanRead {
+			utils.Forbidden(c, "该客户端无查询权限")
+			c.Abort()
+			return
+		}
+		if c.Request.Method != http.MethodGet && !client.CanWrite {
+			utils.Forbidden(c, "该客户端无写入权限")
+			c.Abort()
+			return
+		}
+
+		c.Set("xapiClient", client)
+
+		c.Next()
+	}
+}
@@ -6,6 +6,24 @@ import (
 	"gorm.io/gorm"
 )
 
+// 课程内容类型
+const (
+	ContentTypeVideo = 1 // 视频
+	ContentTypeText  = 2 // 文本
+	ContentTypeMixed = 3 // 混合
+	ContentTypeSCORM = 4 // SCORM 课件包
+)
+
+// 课程状态，只能通过审核流程（CourseReviewService）按状态机流转
+const (
+	CourseStatusDraft       = 0 // 草稿
+	CourseStatusPublished   = 1 // 已发布
+	CourseStatusUnpublished = 2 // 已下架
+	CourseStatusPending     = 3 // 待审核
+	CourseStatusRejected    = 4 // 已驳回
+	CourseStatusApproved    = 5 // 审核通过，等待定时发布
+)
+
 // Course 课程模型
 type Course struct {
 	ID        uint           `gorm:"primarykey" json:"id"`
@@ -13,15 +31,21 @@ type Course struct {
 	UpdatedAt time.Time      `json:"updatedAt"`
 	DeletedAt gorm.DeletedAt `gorm:"index" json:"-"`
 
-	Title       string `gorm:"size:200;not null;comment:课程标题" json:"title"`
-	Description string `gorm:"type:text;comment:课程描述" json:"description"`
-	CoverImage  string `gorm:"size:500;comment:封面图片URL" json:"coverImage"`
-	ContentType int    `gorm:"default:1;comment:内容类型:1-视频,2-文本,3-混合" json:"contentType"`
-	VideoURL    string `gorm:"size:500;comment:视频URL" json:"videoUrl"`
-	TextContent string `gorm:"type:text;comment:文本内容" json:"textContent"`
-	Duration    int    `gorm:"default:0;comment:视频时长(秒),文本为0" json:"duration"`
-	Status      int    `gorm:"default:0;comment:状态:0-草稿,1-已发布,2-已下架" json:"status"`
-	SortOrder   int    `gorm:"default:0;comment:排序" json:"sortOrder"`
+	Title           string `gorm:"size:200;not null;comment:课程标题" json:"title"`
+	Description     string `gorm:"type:text;comment:课程描述" json:"description"`
+	CoverImage      string `gorm:"size:500;comment:封面图片URL" json:"coverImage"`
+	ContentType     int    `gorm:"default:1;comment:内容类型:1-视频,2-文本,3-混合,4-SCORM" json:"contentType"`
+	VideoURL        string `gorm:"size:500;comment:视频URL" json:"videoUrl"`
+	TextContent     string `gorm:"type:text;comment:文本内容" json:"textContent"`
+	Duration        int    `gorm:"default:0;comment:视频时长(秒),文本为0" json:"duration"`
+	Status          int    `gorm:"default:0;comment:状态:0-草稿,1-已发布,2-已下架,3-待审核,4-已驳回,5-待发布" json:"status"`
+	SortOrder       int    `gorm:"default:0;comment:排序" json:"sortOrder"`
+	CurrentRevision int    `gorm:"default:0;comment:当前线上修订版本号,0表示从未发布" json:"currentRevision"`
+	OwnerID         uint   `gorm:"index;comment:课程负责人用户ID,0表示仅管理员可管理" json:"ownerId"`
+
+	// 定时上下架（到期后由调度器执行并清空）
+	PublishAt   *time.Time `gorm:"index;comment:定时发布时间" json:"publishAt,omitempty"`
+	UnpublishAt *time.Time `gorm:"index;comment:定时下架时间" json:"unpublishAt,omitempty"`
 
 	// 关联关系（禁用外键约束，避免迁移顺序问题）
 	Records []CourseRecord `gorm:"foreignKey:CourseID;references:ID;constraint:-" json:"records,omitempty"`
@@ -0,0 +1,20 @@
+package models
+
+import "time"
+
+// RoleCodeInstructor 讲师角色代码：可以创建课程，只能管理自己负责或协作的课程
+const RoleCodeInstructor = "instructor"
+
+// CourseInstructor 课程协作讲师
+type CourseInstructor struct {
+	ID        uint      `gorm:"primarykey" json:"id"`
+	CreatedAt time.Time `json:"createdAt"`
+
+	CourseID uint `gorm:"not null;uniqueIndex:idx_course_instructor;comment:课程ID" json:"courseId"`
+	UserID   uint `gorm:"not null;uniqueIndex:idx_course_instructor;index;comment:讲师用户ID" json:"userId"`
+}
+
+// TableName 指定表名
+func (CourseInstructor) TableName() string {
+	return "sys_course_instructor"
+}
@@ -0,0 +1,54 @@
+package models
+
+import "time"
+
+// 课程状态流转操作
+const (
+	CourseActionSubmit    = "submit"    // 提交审核
+	CourseActionWithdraw  = "withdraw"  // 撤回审核
+	CourseActionApprove   = "approve"   // 审核通过
+	CourseActionReject    = "reject"    // 审核驳回
+	CourseActionPublish   = "publish"   // 发布（审核通过后到达定时发布时间）
+	CourseActionUnpublish = "unpublish" // 下架
+
+	// 线上课程（已发布或待定时发布）的草稿审核，不改变课程状态
+	CourseActionSubmitRevision   = "submit_revision"   // 提交草稿审核
+	CourseActionWithdrawRevision = "withdraw_revision" // 撤回草稿审核
+	CourseActionApproveRevision  = "approve_revision"  // 草稿审核通过，生成新的修订版本
+	CourseActionRejectRevision   = "reject_revision"   // 草稿审核驳回
+)
+
+// RoleCodeCourseReviewer 课程审核员角色代码
+const RoleCodeCourseReviewer = "course_reviewer"
+
+// CourseReviewer 课程指定审核人
+type CourseReviewer struct {
+	ID        uint      `gorm:"primarykey" json:"id"`
+	CreatedAt time.Time `json:"createdAt"`
+
+	CourseID uint `gorm:"not null;uniqueIndex:idx_course_reviewer;comment:课程ID" json:"courseId"`
+	UserID   uint `gorm:"not null;uniqueIndex:idx_course_reviewer;index;comment:审核人用户ID" json:"userId"`
+}
+
+// TableName 指定表名
+func (CourseReviewer) TableName() string {
+	return "sys_course_reviewer"
+}
+
+// CourseTransition 课程状态流转记录（只追加）
+type CourseTransition struct {
+	ID        uint      `gorm:"primarykey" json:"id"`
+	CreatedAt time.Time `gorm:"index" json:"createdAt"`
+
+	CourseID   uint   `gorm:"not null;index;comment:课程ID" json:"courseId"`
+	Action     string `gorm:"size:20;not null;comment:操作:submit,withdraw,approve,reject,publish,unpublish及对应的*_revision" json:"action"`
+	FromStatus int    `gorm:"not null;comment:操作前状态" json:"fromStatus"`
+	ToStatus   int    `gorm:"not null;comment:操作后状态" json:"toStatus"`
+	OperatorID uint   `gorm:"comment:操作人用户ID,0表示系统调度" json:"operatorId"`
+	Comment    string `gorm:"type:text;comment:审核意见或备注" json:"comment"`
+}
+
+// TableName 指定表名
+func (CourseTransition) TableName() string {
+	return "sys_course_transition"
+}
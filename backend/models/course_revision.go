@@ -0,0 +1,86 @@
+package models
+
+import (
+	"time"
+)
+
+// CourseContent 课程内容字段（修订版本和草稿共用）
+type CourseContent struct {
+	Title       string `gorm:"size:200;not null;comment:课程标题" json:"title"`
+	Description string `gorm:"type:text;comment:课程描述" json:"description"`
+	CoverImage  string `gorm:"size:500;comment:封面图片URL" json:"coverImage"`
+	ContentType int    `gorm:"default:1;comment:内容类型:1-视频,2-文本,3-混合,4-SCORM" json:"contentType"`
+	VideoURL    string `gorm:"size:500;comment:视频URL" json:"videoUrl"`
+	TextContent string `gorm:"type:text;comment:文本内容" json:"textContent"`
+	Duration    int    `gorm:"default:0;comment:视频时长(秒),文本为0" json:"duration"`
+}
+
+// ContentOf 提取课程当前的内容字段
+func ContentOf(course *Course) CourseContent {
+	return CourseContent{
+		Title:       course.Title,
+		Description: course.Description,
+		CoverImage:  course.CoverImage,
+		ContentType: course.ContentType,
+		VideoURL:    course.VideoURL,
+		TextContent: course.TextContent,
+		Duration:    course.Duration,
+	}
+}
+
+// ApplyTo 将内容字段写入课程
+func (content CourseContent) ApplyTo(course *Course) {
+	course.Title = content.Title
+	course.Description = content.Description
+	course.CoverImage = content.CoverImage
+	course.ContentType = content.ContentType
+	course.VideoURL = content.VideoURL
+	course.TextContent = content.TextContent
+	course.Duration = content.Duration
+}
+
+// CourseRevision 课程已发布的修订版本（不可修改）
+type CourseRevision struct {
+	ID        uint      `gorm:"primarykey" json:"id"`
+	CreatedAt time.Time `json:"createdAt"`
+
+	CourseID    uint   `gorm:"not null;uniqueIndex:idx_course_revision;comment:课程ID" json:"courseId"`
+	Revision    int    `gorm:"not null;uniqueIndex:idx_course_revision;comment:修订版本号,从1开始" json:"revision"`
+	PublishedBy uint   `gorm:"comment:发布人用户ID" json:"publishedBy"`
+	Note        string `gorm:"size:500;comment:发布说明" json:"note"`
+
+	CourseContent `gorm:"embedded"`
+}
+
+// TableName 指定表名
+func (CourseRevision) TableName() string {
+	return "sys_course_revision"
+}
+
+// CourseDraft 已发布课程的工作草稿（每个课程最多一份）
+type CourseDraft struct {
+	ID        uint      `gorm:"primarykey" json:"id"`
+	CreatedAt time.Time `json:"createdAt"`
+	UpdatedAt time.Time `json:"updatedAt"`
+
+	CourseID     uint `gorm:"not null;uniqueIndex;comment:课程ID" json:"courseId"`
+	BaseRevision int  `gorm:"default:0;comment:草稿基于的修订版本号" json:"baseRevision"`
+	UpdatedBy    uint `gorm:"comment:最后编辑人用户ID" json:"updatedBy"`
+
+	// 线上课程的草稿需审核通过后才生成新的修订版本
+	SubmittedBy uint       `gorm:"default:0;comment:提交审核人用户ID" json:"submittedBy"`
+	SubmittedAt *time.Time `gorm:"index;comment:提交审核时间,为空表示未提交审核" json:"submittedAt"`
+	Note        string     `gorm:"size:500;comment:发布说明" json:"note"`
+
+	CourseContent `gorm:"embedded"`
+}
+
+// InReview 草稿是否已提交审核
+func (d *CourseDraft) InReview() bool {
+	return d.SubmittedAt != nil
+}
+
+// TableName 指定表名
+func (CourseDraft) TableName() string {
+	return "sys_course_draft"
+}
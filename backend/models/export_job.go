@@ -0,0 +1,34 @@
+package models
+
+import "time"
+
+// 导出任务状态
+const (
+	ExportJobPending = "pending" // 排队中
+	ExportJobRunning = "running" // 生成中
+	ExportJobDone    = "done"    // 已完成，可下载
+	ExportJobFailed  = "failed"  // 失败
+)
+
+// ExportJob 后台导出任务（大数据量导出时异步生成文件）
+type ExportJob struct {
+	ID        uint      `gorm:"primarykey" json:"id"`
+	CreatedAt time.Time `json:"createdAt"`
+	UpdatedAt time.Time `json:"updatedAt"`
+
+	UserID     uint       `gorm:"not null;index;comment:发起导出的用户ID" json:"userId"`
+	Kind       string     `gorm:"size:50;not null;comment:导出内容:course-progress,user-progress,users,courses" json:"kind"`
+	Format     string     `gorm:"size:10;not null;comment:文件格式:csv,xlsx" json:"format"`
+	Params     string     `gorm:"type:text;comment:筛选条件(JSON)" json:"params"`
+	Status     string     `gorm:"size:20;not null;index;comment:状态:pending,running,done,failed" json:"status"`
+	RowCount   int64      `gorm:"default:0;comment:导出行数" json:"rowCount"`
+	FileName   string     `gorm:"size:200;comment:下载文件名" json:"fileName"`
+	FilePath   string     `gorm:"size:500;comment:文件存储路径(相对上传目录)" json:"-"`
+	Error      string     `gorm:"type:text;comment:失败原因" json:"error,omitempty"`
+	FinishedAt *time.Time `gorm:"comment:完成时间" json:"finishedAt,omitempty"`
+}
+
+// TableName 指定表名
+func (ExportJob) TableName() string {
+	return "sys_export_job"
+}
@@ -0,0 +1,43 @@
+package models
+
+import (
+	"time"
+)
+
+// 学习事件动词
+const (
+	VerbStarted    = "started"    // 开始学习
+	VerbPaused     = "paused"     // 暂停
+	VerbSeeked     = "seeked"     // 拖动进度
+	VerbProgressed = "progressed" // 进度更新
+	VerbCompleted  = "completed"  // 完成课程
+)
+
+// LearningEvent 学习事件模型（仅追加，不更新不删除）
+type LearningEvent struct {
+	ID        uint      `gorm:"primarykey" json:"id"`
+	CreatedAt time.Time `json:"createdAt"`
+
+	UserID       uint      `gorm:"not null;index:idx_event_user_time;comment:用户ID" json:"userId"`
+	CourseID     uint      `gorm:"not null;index:idx_event_course_time;comment:课程ID" json:"courseId"`
+	Verb         string    `gorm:"size:50;not null;index;comment:事件动词:started,paused,seeked,progressed,completed" json:"verb"`
+	Position     int       `gorm:"default:0;comment:事件发生时的播放位置(秒)" json:"position"`
+	FromPosition *int      `gorm:"comment:拖动前的播放位置(秒),仅seeked" json:"fromPosition,omitempty"`
+	Progress     int       `gorm:"default:0;comment:事件发生时的学习进度(0-100)" json:"progress"`
+	Duration     int       `gorm:"default:0;comment:事件发生时的已学习时长(秒)" json:"duration"`
+	OccurredAt   time.Time `gorm:"not null;index:idx_event_user_time;index:idx_event_course_time;comment:事件发生时间" json:"occurredAt"`
+}
+
+// TableName 指定表名
+func (LearningEvent) TableName() string {
+	return "sys_learning_event"
+}
+
+// IsValidLearningVerb 判断是否为支持的学习事件动词
+func IsValidLearningVerb(verb string) bool {
+	switch verb {
+	case VerbStarted, VerbPaused, VerbSeeked, VerbProgressed, VerbCompleted:
+		return true
+	}
+	return false
+}
@@ -0,0 +1,22 @@
+package models
+
+import "time"
+
+// LearningDailyStat 学习数据日汇总（由汇总任务预计算，可重复执行）
+// CourseID 为 0 的行表示当天全部课程的汇总
+type LearningDailyStat struct {
+	ID        uint      `gorm:"primarykey" json:"id"`
+	CreatedAt time.Time `json:"createdAt"`
+	UpdatedAt time.Time `json:"updatedAt"`
+
+	StatDate       string `gorm:"size:10;not null;uniqueIndex:idx_stat_date_course;comment:统计日期(YYYY-MM-DD)" json:"statDate"`
+	CourseID       uint   `gorm:"not null;uniqueIndex:idx_stat_date_course;comment:课程ID,0表示全部课程" json:"courseId"`
+	ActiveLearners int64  `gorm:"default:0;comment:活跃学员数" json:"activeLearners"`
+	Events         int64  `gorm:"default:0;comment:学习事件数" json:"events"`
+	Completions    int64  `gorm:"default:0;comment:完成次数" json:"completions"`
+}
+
+// TableName 指定表名
+func (LearningDailyStat) TableName() string {
+	return "sys_learning_daily_stat"
+}
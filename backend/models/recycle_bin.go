@@ -0,0 +1,20 @@
+package models
+
+import "time"
+
+// RecycleEntry 回收站条目，记录软删除的数据及其被占位替换前的唯一键，用于恢复
+type RecycleEntry struct {
+	ID        uint      `gorm:"primarykey" json:"id"`
+	CreatedAt time.Time `gorm:"index;comment:删除时间" json:"deletedAt"`
+
+	Kind       string `gorm:"size:20;not null;uniqueIndex:idx_recycle_kind_record;comment:数据类型:user,role,course,rule" json:"kind"`
+	RecordID   uint   `gorm:"not null;uniqueIndex:idx_recycle_kind_record;comment:数据ID" json:"recordId"`
+	Name       string `gorm:"size:200;comment:显示名称" json:"name"`
+	UniqueKeys string `gorm:"type:text;comment:原唯一键值(JSON)" json:"-"`
+	OperatorID uint   `gorm:"comment:删除人用户ID,0表示系统" json:"operatorId"`
+}
+
+// TableName 指定表名
+func (RecycleEntry) TableName() string {
+	return "sys_recycle_bin"
+}
@@ -6,6 +6,9 @@ import (
 	"gorm.io/gorm"
 )
 
+// RoleCodeAdmin 内置管理员角色代码
+const RoleCodeAdmin = "admin"
+
 // Role 角色模型
 type Role struct {
 	ID          uint           `gorm:"primarykey" json:"id"`
@@ -20,7 +20,7 @@ type Rule struct {
 	Owner    string `gorm:"size:100" json:"owner"`
 	Avatar   string `gorm:"size:500" json:"avatar"`
 	Href     string `gorm:"size:500" json:"href"`
-	Disabled bool   `gorm:"default:0" json:"disabled"`
+	Disabled bool   `gorm:"default:false" json:"disabled"`
 	Progress int    `gorm:"default:0" json:"progress"`
 }
 
@@ -0,0 +1,51 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// SCORM 版本
+const (
+	ScormVersion12   = "1.2"
+	ScormVersion2004 = "2004"
+)
+
+// ScormPackage SCORM 课件包
+type ScormPackage struct {
+	ID        uint           `gorm:"primarykey" json:"id"`
+	CreatedAt time.Time      `json:"createdAt"`
+	UpdatedAt time.Time      `json:"updatedAt"`
+	DeletedAt gorm.DeletedAt `gorm:"index" json:"-"`
+
+	CourseID   uint   `gorm:"not null;index;comment:课程ID" json:"courseId"`
+	Version    string `gorm:"size:20;not null;comment:SCORM版本:1.2,2004" json:"version"`
+	Identifier string `gorm:"size:255;comment:清单标识" json:"identifier"`
+	Title      string `gorm:"size:200;comment:组织标题" json:"title"`
+	EntryHref  string `gorm:"size:500;not null;comment:启动SCO的相对路径" json:"entryHref"`
+	StorageDir string `gorm:"size:255;not null;comment:解压目录(相对上传目录)" json:"-"`
+	FileSize   int64  `gorm:"default:0;comment:解压后总大小(字节)" json:"fileSize"`
+}
+
+// TableName 指定表名
+func (ScormPackage) TableName() string {
+	return "sys_scorm_package"
+}
+
+// ScormRuntimeValue SCORM 运行时数据模型值（每个用户每个元素一行）
+type ScormRuntimeValue struct {
+	ID        uint      `gorm:"primarykey" json:"id"`
+	CreatedAt time.Time `json:"createdAt"`
+	UpdatedAt time.Time `json:"updatedAt"`
+
+	UserID    uint   `gorm:"not null;uniqueIndex:idx_scorm_user_element;comment:用户ID" json:"userId"`
+	PackageID uint   `gorm:"not null;uniqueIndex:idx_scorm_user_element;comment:课件包ID" json:"packageId"`
+	Element   string `gorm:"size:255;not null;uniqueIndex:idx_scorm_user_element;comment:数据模型元素,如cmi.core.lesson_status" json:"element"`
+	Value     string `gorm:"type:text;comment:元素值" json:"value"`
+}
+
+// TableName 指定表名
+func (ScormRuntimeValue) TableName() string {
+	return "sys_scorm_runtime_value"
+}
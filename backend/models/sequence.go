@@ -0,0 +1,12 @@
+package models
+
+// Sequence 编号计数器，每个名称一行，通过原子更新分配递增编号
+type Sequence struct {
+	Name  string `gorm:"primarykey;size:50;comment:计数器名称" json:"name"`
+	Value uint64 `gorm:"not null;default:0;comment:最后分配的值" json:"value"`
+}
+
+// TableName 指定表名
+func (Sequence) TableName() string {
+	return "sys_sequence"
+}
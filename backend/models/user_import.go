@@ -0,0 +1,24 @@
+package models
+
+import "time"
+
+// UserImport 批量导入用户的批次记录，结果报告保存在上传目录
+type UserImport struct {
+	ID        uint      `gorm:"primarykey" json:"id"`
+	CreatedAt time.Time `json:"createdAt"`
+
+	OperatorID uint   `gorm:"not null;index;comment:操作人用户ID" json:"operatorId"`
+	FileName   string `gorm:"size:200;comment:上传文件名" json:"fileName"`
+	DryRun     bool   `gorm:"comment:是否仅预检" json:"dryRun"`
+	Imported   bool   `gorm:"comment:是否已写入数据库" json:"imported"`
+	Total      int    `gorm:"default:0;comment:数据行数" json:"total"`
+	Created    int    `gorm:"default:0;comment:新建用户数" json:"created"`
+	Updated    int    `gorm:"default:0;comment:更新用户数" json:"updated"`
+	Failed     int    `gorm:"default:0;comment:校验失败行数" json:"failed"`
+	ReportPath string `gorm:"size:500;comment:结果报告路径(相对上传目录)" json:"-"`
+}
+
+// TableName 指定表名
+func (UserImport) TableName() string {
+	return "sys_user_import"
+}
@@ -0,0 +1,72 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// xAPI 动词 IRI
+const (
+	XAPIVerbCompleted = "http://adlnet.gov/expapi/verbs/completed"
+	XAPIVerbPassed    = "http://adlnet.gov/expapi/verbs/passed"
+	XAPIVerbVoided    = "http://adlnet.gov/expapi/verbs/voided"
+)
+
+// XAPIClient xAPI 客户端凭证（LRS Basic 认证）
+type XAPIClient struct {
+	ID        uint           `gorm:"primarykey" json:"id"`
+	CreatedAt time.Time      `json:"createdAt"`
+	UpdatedAt time.Time      `json:"updatedAt"`
+	DeletedAt gorm.DeletedAt `gorm:"index" json:"-"`
+
+	Name     string `gorm:"size:100;not null;comment:客户端名称" json:"name"`
+	Key      string `gorm:"column:client_key;uniqueIndex;size:64;not null;comment:Basic认证用户名" json:"key"`
+	Secret   string `gorm:"size:255;not null;comment:Basic认证密码哈希" json:"-"`
+	Status   int    `gorm:"default:1;comment:0-禁用,1-启用" json:"status"`
+	CanRead  bool   `gorm:"comment:是否允许查询语句" json:"canRead"`
+	CanWrite bool   `gorm:"comment:是否允许写入语句" json:"canWrite"`
+}
+
+// TableName 指定表名
+func (XAPIClient) TableName() string {
+	return "sys_xapi_client"
+}
+
+// XAPIActivity 已知 xAPI 活动与课程的映射
+type XAPIActivity struct {
+	ID        uint      `gorm:"primarykey" json:"id"`
+	CreatedAt time.Time `json:"createdAt"`
+	UpdatedAt time.Time `json:"updatedAt"`
+
+	ActivityID string `gorm:"uniqueIndex;size:255;not null;comment:活动IRI" json:"activityId"`
+	CourseID   uint   `gorm:"not null;index;comment:课程ID" json:"courseId"`
+}
+
+// TableName 指定表名
+func (XAPIActivity) TableName() string {
+	return "sys_xapi_activity"
+}
+
+// XAPIStatement xAPI 语句（原文保存在 Raw 中，其余字段用于查询）
+type XAPIStatement struct {
+	ID        uint      `gorm:"primarykey" json:"-"`
+	CreatedAt time.Time `json:"-"`
+
+	StatementID  string    `gorm:"uniqueIndex;size:36;not null;comment:语句UUID" json:"id"`
+	ClientID     uint      `gorm:"index;comment:写入的客户端ID" json:"-"`
+	ActorKey     string    `gorm:"size:255;index;comment:参与者标识(mbox/account)" json:"-"`
+	UserID       uint      `gorm:"index;comment:匹配到的用户ID,0表示未匹配" json:"-"`
+	VerbID       string    `gorm:"size:255;index;comment:动词IRI" json:"-"`
+	ActivityID   string    `gorm:"size:255;index;comment:对象IRI" json:"-"`
+	Registration string    `gorm:"size:36;index;comment:注册UUID" json:"-"`
+	Timestamp    time.Time `gorm:"comment:语句发生时间" json:"-"`
+	Stored       time.Time `gorm:"index;comment:LRS存储时间" json:"-"`
+	Voided       bool      `gorm:"default:false;index;comment:是否已作废" json:"-"`
+	Raw          string    `gorm:"type:text;not null;comment:语句JSON原文" json:"-"`
+}
+
+// TableName 指定表名
+func (XAPIStatement) TableName() string {
+	return "sys_xapi_statement"
+}
@@ -0,0 +1,223 @@
+package openapi
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// 接口认证方式
+const (
+	AuthBearer = ""      // 默认：请求头 Authorization: Bearer <token>
+	AuthNone   = "none"  // 不需要认证
+	AuthBasic  = "basic" // xAPI 客户端 Basic 认证
+)
+
+// Route 接口说明，以 "方法 路由模板"（如 "GET /api/user/:id"）为键登记
+type Route struct {
+	Summary     string
+	Description string
+	Tag         string
+	Auth        string      // 认证方式，默认 AuthBearer
+	Query       []Parameter // 查询参数，使用 Query 构造
+	Body        interface{} // JSON 请求体类型的零值
+	Form        []FormField // multipart/form-data 请求体（文件上传）
+	Response    interface{} // 成功响应中 data 的类型的零值，nil 时不限定
+	List        bool        // 分页列表响应：data 为 Response 的数组，另有 total、current、pageSize
+	Raw         bool        // 响应不使用统一结构，Response 即为响应体
+	File        string      // 响应为文件下载时的内容类型
+}
+
+// FormField 表单字段
+type FormField struct {
+	Name        string
+	Description string
+	File        bool
+	Required    bool
+}
+
+// Query 构造查询参数，typ 为 string、integer、boolean
+func Query(name, typ, description string) Parameter {
+	return Parameter{Name: name, In: "query", Description: description, Schema: &Schema{Type: typ}}
+}
+
+// Build 根据 gin 路由表和接口说明生成文档；没有接口说明的路由同样列出，只包含路径参数
+// errorResponse 为错误响应体类型的零值
+func Build(info Info, routes gin.RoutesInfo, docs map[string]Route, errorResponse interface{}) *Document {
+	registry := newSchemaRegistry()
+	errorSchema := registry.schemaOf(errorResponse)
+
+	doc := &Document{
+		OpenAPI: "3.0.3",
+		Info:    info,
+		Paths:   map[string]PathItem{},
+		Components: Components{
+			SecuritySchemes: map[string]SecurityScheme{
+				"bearerAuth": {Type: "http", Scheme: "bearer", BearerFormat: "JWT", Description: "登录接口返回的 token"},
+				"basicAuth":  {Type: "http", Scheme: "basic", Description: "xAPI 客户端的 key 和 secret"},
+			},
+		},
+		Security: []SecurityRequirement{{"bearerAuth": {}}},
+	}
+
+	tags := map[string]bool{}
+	for _, route := range routes {
+		spec := docs[routeKey(route.Method, route.Path)]
+		path, params := convertPath(route.Path)
+		op := &Operation{
+			Summary:     spec.Summary,
+			Description: spec.Description,
+			OperationID: operationID(route.Method, route.Path),
+			Parameters:  append(params, spec.Query...),
+			Responses:   map[string]Response{},
+		}
+		if op.Summary == "" {
+			op.Summary = handlerName(route.Handler)
+		}
+		if spec.Tag != "" {
+			op.Tags = []string{spec.Tag}
+			tags[spec.Tag] = true
+		}
+
+		switch spec.Auth {
+		case AuthNone:
+			op.Security = &[]SecurityRequirement{}
+		case AuthBasic:
+			op.Security = &[]SecurityRequirement{{"basicAuth": {}}}
+		}
+
+		if spec.Body != nil {
+			op.RequestBody = &RequestBody{
+				Required: true,
+				Content:  map[string]MediaType{"application/json": {Schema: registry.schemaOf(spec.Body)}},
+			}
+		} else if len(spec.Form) > 0 {
+			op.RequestBody = &RequestBody{
+				Required: true,
+				Content:  map[string]MediaType{"multipart/form-data": {Schema: formSchema(spec.Form)}},
+			}
+		}
+
+		op.Responses["200"] = successResponse(registry, spec)
+		if spec.Auth != AuthNone {
+			op.Responses["401"] = Response{Description: "未登录或认证失败", Content: jsonContent(errorSchema)}
+		}
+		op.Responses["default"] = Response{Description: "错误", Content: jsonContent(errorSchema)}
+
+		item, ok := doc.Paths[path]
+		if !ok {
+			item = PathItem{}
+			doc.Paths[path] = item
+		}
+		item[strings.ToLower(route.Method)] = op
+	}
+
+	doc.Components.Schemas = registry.schemas
+	for tag := range tags {
+		doc.Tags = append(doc.Tags, Tag{Name: tag})
+	}
+	sort.Slice(doc.Tags, func(i, j int) bool { return doc.Tags[i].Name < doc.Tags[j].Name })
+	return doc
+}
+
+// Undocumented 没有接口说明的路由（"方法 路由模板"）
+func Undocumented(routes gin.RoutesInfo, docs map[string]Route) []string {
+	var missing []string
+	for _, route := range routes {
+		key := routeKey(route.Method, route.Path)
+		if _, ok := docs[key]; !ok {
+			missing = append(missing, key)
+		}
+	}
+	return missing
+}
+
+func successResponse(registry *schemaRegistry, spec Route) Response {
+	if spec.File != "" {
+		return Response{Description: "文件", Content: map[string]MediaType{spec.File: {Schema: &Schema{Type: "string", Format: "binary"}}}}
+	}
+
+	data := registry.schemaOf(spec.Response)
+	if spec.Raw {
+		return Response{Description: "成功", Content: jsonContent(data)}
+	}
+
+	envelope := &Schema{
+		Type:       "object",
+		Properties: map[string]*Schema{"success": {Type: "boolean"}},
+		Required:   []string{"success"},
+	}
+	if spec.List {
+		envelope.Properties["data"] = &Schema{Type: "array", Items: data}
+		envelope.Properties["total"] = &Schema{Type: "integer", Format: "int64"}
+		envelope.Properties["current"] = &Schema{Type: "integer", Description: "当前页码"}
+		envelope.Properties["pageSize"] = &Schema{Type: "integer", Description: "每页数量"}
+	} else {
+		envelope.Properties["data"] = data
+	}
+	return Response{Description: "成功", Content: jsonContent(envelope)}
+}
+
+func formSchema(fields []FormField) *Schema {
+	s := &Schema{Type: "object", Properties: map[string]*Schema{}}
+	for _, field := range fields {
+		prop := &Schema{Type: "string", Description: field.Description}
+		if field.File {
+			prop.Format = "binary"
+		}
+		s.Properties[field.Name] = prop
+		if field.Required {
+			s.Required = append(s.Required, field.Name)
+		}
+	}
+	return s
+}
+
+func jsonContent(schema *Schema) map[string]MediaType {
+	return map[string]MediaType{"application/json": {Schema: schema}}
+}
+
+func routeKey(method, path string) string {
+	return method + " " + path
+}
+
+// integerPathParams 取值为数字 ID 的路径参数
+var integerPathParams = map[string]bool{"id": true, "revision": true, "packageId": true}
+
+// convertPath 将 gin 路由模板（:id、*filepath）转换为 OpenAPI 路径（{id}），并生成路径参数
+func convertPath(path string) (string, []Parameter) {
+	var params []Parameter
+	segments := strings.Split(path, "/")
+	for i, segment := range segments {
+		if segment == "" || (segment[0] != ':' && segment[0] != '*') {
+			continue
+		}
+		name := segment[1:]
+		segments[i] = "{" + name + "}"
+		schema := &Schema{Type: "string"}
+		if integerPathParams[name] {
+			schema = &Schema{Type: "integer", Format: "int32"}
+		}
+		params = append(params, Parameter{Name: name, In: "path", Required: true, Schema: schema})
+	}
+	return strings.Join(segments, "/"), params
+}
+
+// operationID 由方法和路径生成唯一的接口 ID，如 GET /api/user/:id -> get_api_user_id
+func operationID(method, path string) string {
+	replacer := strings.NewReplacer("/", "_", ":", "", "*", "", "-", "_")
+	return strings.ToLower(method) + strings.TrimRight(replacer.Replace(path), "_")
+}
+
+// handlerName 处理函数的简短名称，如 learn-hub-backend/controllers.(*UserController).GetUserList-fm -> UserController.GetUserList
+func handlerName(name string) string {
+	if i := strings.LastIndex(name, "/"); i >= 0 {
+		name = name[i+1:]
+	}
+	if i := strings.Index(name, "."); i >= 0 {
+		name = name[i+1:]
+	}
+	name = strings.TrimSuffix(name, "-fm")
+	return strings.NewReplacer("(*", "", ")", "").Replace(name)
+}
@@ -0,0 +1,101 @@
+package routes
+
+import (
+	"bytes"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"learn-hub-backend/database"
+	"learn-hub-backend/models"
+	"learn-hub-backend/testutil"
+	"learn-hub-backend/utils"
+)
+
+// adminUser 种子数据中的默认管理员
+func adminUser(t *testing.T) *models.User {
+	t.Helper()
+	var admin models.User
+	if err := database.DB.Where("username = ?", "admin").First(&admin).Error; err != nil {
+		t.Fatal(err)
+	}
+	return &admin
+}
+
+// 批量导入会覆盖密码和角色，只有管理员可以使用
+func TestUserImportRequiresAdmin(t *testing.T) {
+	testutil.SetupDB(t)
+	learner := login(t, testutil.CreateUser(t, "learner", "user"))
+	admin := login(t, adminUser(t))
+
+	var body bytes.Buffer
+	form := multipart.NewWriter(&body)
+	file, err := form.CreateFormFile("file", "users.csv")
+	if err != nil {
+		t.Fatal(err)
+	}
+	file.Write([]byte("username,password,roles\nadmin,newpassword1,admin"))
+	form.Close()
+
+	req := httptest.NewRequest(http.MethodPost, "/api/user/import", &body)
+	req.Header.Set("Content-Type", form.FormDataContentType())
+	req.Header.Set("Authorization", "Bearer "+learner)
+	w := httptest.NewRecorder()
+	router(t).ServeHTTP(w, req)
+	if code := errorCode(t, w); code != utils.CodeForbidden.Code {
+		t.Fatalf("普通用户导入返回 %q，期望 %s", code, utils.CodeForbidden.Code)
+	}
+
+	for _, path := range []string{"/api/user/import/template", "/api/user/import/1/report"} {
+		if w := serve(t, http.MethodGet, path, learner, ""); w.Code != http.StatusForbidden {
+			t.Errorf("普通用户 GET %s 返回 %d，期望 403", path, w.Code)
+		}
+	}
+	if w := serve(t, http.MethodGet, "/api/user/import/template", admin, ""); w.Code != http.StatusOK {
+		t.Fatalf("管理员下载导入模板返回 %d", w.Code)
+	}
+}
+
+// 用户列表导出包含全部用户的个人信息，只有管理员可以使用
+func TestUserExportRequiresAdmin(t *testing.T) {
+	testutil.SetupDB(t)
+	learner := login(t, testutil.CreateUser(t, "learner", "user"))
+	admin := login(t, adminUser(t))
+
+	if w := serve(t, http.MethodGet, "/api/user/list/export", learner, ""); w.Code != http.StatusForbidden {
+		t.Fatalf("普通用户导出用户列表返回 %d，期望 403", w.Code)
+	}
+	w := serve(t, http.MethodGet, "/api/user/list/export", admin, "")
+	if w.Code != http.StatusOK || !bytes.Contains(w.Body.Bytes(), []byte("learner")) {
+		t.Fatalf("管理员导出用户列表返回 %d: %s", w.Code, w.Body.String())
+	}
+}
+
+// 令牌签发后被取消管理员角色或禁用的用户不能继续使用管理员接口
+func TestAdminMiddlewareUsesCurrentRoles(t *testing.T) {
+	testutil.SetupDB(t)
+	boss := testutil.CreateUser(t, "boss", models.RoleCodeAdmin)
+	token := login(t, boss)
+
+	if w := serve(t, http.MethodGet, "/api/recycle", token, ""); w.Code != http.StatusOK {
+		t.Fatalf("管理员访问回收站返回 %d: %s", w.Code, w.Body.String())
+	}
+
+	if err := database.DB.Model(&models.User{}).Where("id = ?", boss.ID).Update("status", 0).Error; err != nil {
+		t.Fatal(err)
+	}
+	if w := serve(t, http.MethodGet, "/api/recycle", token, ""); w.Code != http.StatusForbidden {
+		t.Fatalf("禁用后的管理员访问回收站返回 %d，期望 403", w.Code)
+	}
+
+	if err := database.DB.Model(&models.User{}).Where("id = ?", boss.ID).Update("status", 1).Error; err != nil {
+		t.Fatal(err)
+	}
+	if err := database.DB.Model(boss).Association("Roles").Clear(); err != nil {
+		t.Fatal(err)
+	}
+	if w := serve(t, http.MethodGet, "/api/recycle", token, ""); w.Code != http.StatusForbidden {
+		t.Fatalf("取消管理员角色后访问回收站返回 %d，期望 403", w.Code)
+	}
+}
@@ -0,0 +1,75 @@
+package routes
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+
+	"learn-hub-backend/database"
+	"learn-hub-backend/models"
+	"learn-hub-backend/testutil"
+	"learn-hub-backend/utils"
+)
+
+// 学习进度和完成只能提交到员工可学习的课程
+func TestLearnProgressRequiresAvailableCourse(t *testing.T) {
+	testutil.SetupDB(t)
+	learner := testutil.CreateUser(t, "learner", "user")
+	token := login(t, learner)
+
+	draft := testutil.CreateCourse(t, "draft", 0)
+	published := testutil.CreateCourse(t, "published", 0)
+	if err := database.DB.Model(published).Update("status", models.CourseStatusPublished).Error; err != nil {
+		t.Fatal(err)
+	}
+
+	for _, id := range []uint{draft.ID, 9999} {
+		for _, action := range []string{"progress", "complete"} {
+			w := serve(t, http.MethodPost, fmt.Sprintf("/api/learn/course/%d/%s", id, action), token, `{"progress":50}`)
+			if code := errorCode(t, w); code != utils.CodeCourseNotFound.Code {
+				t.Errorf("课程 %d %s 返回 %q，期望 %s", id, action, code, utils.CodeCourseNotFound.Code)
+			}
+		}
+	}
+	var records int64
+	if err := database.DB.Model(&models.CourseRecord{}).Count(&records).Error; err != nil {
+		t.Fatal(err)
+	}
+	if records != 0 {
+		t.Fatalf("不可学习的课程产生了 %d 条学习记录", records)
+	}
+
+	w := serve(t, http.MethodPost, fmt.Sprintf("/api/learn/course/%d/progress", published.ID), token, `{"progress":50}`)
+	if code := errorCode(t, w); code != "" {
+		t.Fatalf("已发布课程更新进度返回 %q: %s", code, w.Body.String())
+	}
+}
+
+// 重复标记完成只产生一次完成事件
+func TestCompleteCourseRecordsCompletionOnce(t *testing.T) {
+	testutil.SetupDB(t)
+	learner := testutil.CreateUser(t, "learner", "user")
+	token := login(t, learner)
+	course := testutil.CreateCourse(t, "published", 0)
+	if err := database.DB.Model(course).Update("status", models.CourseStatusPublished).Error; err != nil {
+		t.Fatal(err)
+	}
+
+	path := fmt.Sprintf("/api/learn/course/%d/complete", course.ID)
+	for i := 0; i < 3; i++ {
+		if code := errorCode(t, serve(t, http.MethodPost, path, token, "")); code != "" {
+			t.Fatalf("第 %d 次标记完成返回 %q", i+1, code)
+		}
+	}
+
+	var completions int64
+	err := database.DB.Model(&models.LearningEvent{}).
+		Where("user_id = ? AND course_id = ? AND verb = ?", learner.ID, course.ID, models.VerbCompleted).
+		Count(&completions).Error
+	if err != nil {
+		t.Fatal(err)
+	}
+	if completions != 1 {
+		t.Fatalf("完成事件 %d 条，期望 1 条", completions)
+	}
+}
@@ -0,0 +1,269 @@
+package routes
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"learn-hub-backend/controllers"
+	"learn-hub-backend/models"
+	"learn-hub-backend/openapi"
+	"learn-hub-backend/services"
+	"learn-hub-backend/utils"
+)
+
+// apiInfo 接口文档基本信息
+var apiInfo = openapi.Info{
+	Title:   "Learn Hub API",
+	Version: "1.0",
+	Description: "除登录等公开接口外均需在请求头携带 Authorization: Bearer <token>。\n" +
+		"响应统一为 {success, data}，分页列表为 {success, data, total, current, pageSize}，" +
+		"失败时为 {success: false, errorCode, errorMessage, requestId}，errorCode 的取值及对应的 HTTP 状态码见 Response 结构说明。",
+}
+
+// 文件下载的内容类型
+const (
+	fileDownload = "application/octet-stream"
+	zipFile      = "application/zip"
+)
+
+// 常用查询参数
+var (
+	paginationParams = []openapi.Parameter{
+		openapi.Query("current", "integer", "页码，默认 1"),
+		openapi.Query("pageSize", "integer", "每页数量，默认 20"),
+	}
+	exportParams = []openapi.Parameter{
+		openapi.Query("format", "string", "导出格式：csv（默认）或 xlsx"),
+		openapi.Query("async", "boolean", "为 true 时转为后台任务，返回任务信息；行数超过阈值时自动转为后台任务"),
+	}
+	deleteParams = []openapi.Parameter{
+		openapi.Query("policy", "string", "存在关联数据时的处理方式：block（默认，拒绝删除）、cascade 或 reassign"),
+		openapi.Query("targetId", "integer", "policy 为 reassign 时关联数据的接收方 ID"),
+	}
+	analyticsParams = []openapi.Parameter{
+		openapi.Query("courseId", "integer", "只统计指定课程"),
+		openapi.Query("startTime", "string", "开始时间，格式 2006-01-02"),
+		openapi.Query("endTime", "string", "结束时间，格式 2006-01-02"),
+	}
+)
+
+// params 合并多组查询参数
+func params(groups ...[]openapi.Parameter) []openapi.Parameter {
+	var all []openapi.Parameter
+	for _, group := range groups {
+		all = append(all, group...)
+	}
+	return all
+}
+
+// apiDocs 接口说明，键为 "方法 路由模板"；新增路由时需在此登记，缺少说明的路由在启动时输出警告
+var apiDocs = map[string]openapi.Route{
+	// 系统
+	"GET /healthz":          {Tag: "系统", Summary: "存活检查", Auth: openapi.AuthNone, Raw: true},
+	"GET /readyz":           {Tag: "系统", Summary: "就绪检查", Description: "数据库不可用或存在未执行的迁移时返回 503", Auth: openapi.AuthNone, Raw: true},
+	"GET /metrics":          {Tag: "系统", Summary: "Prometheus 指标", Auth: openapi.AuthNone, File: "text/plain"},
+	"GET /api/openapi.json": {Tag: "系统", Summary: "OpenAPI 文档", Auth: openapi.AuthNone, Raw: true},
+	"GET /api/docs":         {Tag: "系统", Summary: "接口文档页面（Swagger UI）", Auth: openapi.AuthNone, File: "text/html"},
+	"GET /scorm/content/:packageId/*filepath": {Tag: "SCORM", Summary: "课件包内的静态文件", Auth: openapi.AuthNone, File: fileDownload},
+
+	// xAPI 学习记录存储
+	"POST /xapi/statements": {Tag: "xAPI", Summary: "保存一条或多条语句", Auth: openapi.AuthBasic, Body: json.RawMessage{}, Response: []string{}, Raw: true},
+	"PUT /xapi/statements": {Tag: "xAPI", Summary: "以指定 ID 保存语句", Auth: openapi.AuthBasic, Body: json.RawMessage{}, Raw: true,
+		Query: []openapi.Parameter{openapi.Query("statementId", "string", "语句 ID（UUID）")}},
+	"GET /xapi/statements": {Tag: "xAPI", Summary: "查询语句", Description: "指定 statementId 或 voidedStatementId 时直接返回该语句", Auth: openapi.AuthBasic, Response: controllers.XAPIStatementResult{}, Raw: true,
+		Query: []openapi.Parameter{
+			openapi.Query("statementId", "string", "按语句 ID 查询"),
+			openapi.Query("voidedStatementId", "string", "按已作废的语句 ID 查询"),
+			openapi.Query("agent", "string", "学习者（JSON 格式的 Agent）"),
+			openapi.Query("verb", "string", "动词 ID"),
+			openapi.Query("activity", "string", "活动 ID"),
+			openapi.Query("registration", "string", "注册 ID"),
+			openapi.Query("ascending", "boolean", "按时间正序"),
+			openapi.Query("limit", "integer", "返回数量"),
+			openapi.Query("offset", "integer", "偏移量"),
+			openapi.Query("since", "string", "起始时间（RFC 3339）"),
+			openapi.Query("until", "string", "截止时间（RFC 3339）"),
+		}},
+
+	// 认证
+	"POST /api/login/account": {Tag: "认证", Summary: "用户登录", Auth: openapi.AuthNone, Body: controllers.LoginRequest{}, Response: controllers.LoginResponse{}, Raw: true},
+	"GET /api/login/captcha": {Tag: "认证", Summary: "获取验证码", Auth: openapi.AuthNone,
+		Response: controllers.CaptchaResponse{},
+		Query:    []openapi.Parameter{openapi.Query("phone", "string", "手机号")}},
+	"GET /api/currentUser":      {Tag: "认证", Summary: "获取当前用户信息", Response: controllers.CurrentUserResponse{}},
+	"POST /api/login/outLogin":  {Tag: "认证", Summary: "退出登录"},
+	"GET /api/menu/list":        {Tag: "菜单", Summary: "获取当前用户的菜单", Response: []models.MenuVO{}},
+	"GET /api/user/permissions": {Tag: "菜单", Summary: "获取当前用户的权限标识", Response: controllers.UserPermissionsResponse{}},
+
+	// 规则
+	"GET /api/rule":  {Tag: "规则", Summary: "获取规则列表", Query: paginationParams, Response: controllers.RuleResponse{}, List: true},
+	"POST /api/rule": {Tag: "规则", Summary: "创建、更新或删除规则", Description: "method 为 post、update 或 delete，删除时不返回数据", Body: controllers.RuleRequest{}, Response: controllers.RuleResponse{}},
+
+	// 用户
+	"GET /api/user/list": {Tag: "用户", Summary: "获取用户列表", Response: controllers.UserResponse{}, List: true, Query: params(paginationParams, []openapi.Parameter{
+		openapi.Query("username", "string", "用户名（模糊匹配）"),
+		openapi.Query("status", "integer", "状态：1-启用，0-禁用"),
+	})},
+	"POST /api/user":                  {Tag: "用户", Summary: "创建用户", Body: controllers.CreateUserRequest{}, Response: controllers.UserResponse{}},
+	"PUT /api/user/:id":               {Tag: "用户", Summary: "更新用户", Body: controllers.UpdateUserRequest{}, Response: controllers.UserResponse{}},
+	"DELETE /api/user/:id":            {Tag: "用户", Summary: "删除用户（放入回收站）", Query: deleteParams},
+	"GET /api/user/:id/delete-impact": {Tag: "用户", Summary: "预览删除用户的影响", Response: services.DeletionImpact{}},
+	"GET /api/user/roles":             {Tag: "用户", Summary: "获取所有角色（下拉选择）", Response: []controllers.RoleOption{}},
+	"GET /api/user/list/export": {Tag: "用户", Summary: "导出用户列表", File: fileDownload, Query: params(exportParams, []openapi.Parameter{
+		openapi.Query("username", "string", "用户名（模糊匹配）"),
+		openapi.Query("status", "integer", "状态"),
+	})},
+	"POST /api/user/import": {Tag: "用户", Summary: "批量导入用户", Description: "存在任一错误行时不写入任何数据", Response: services.UserImportResult{},
+		Form: []openapi.FormField{
+			{Name: "file", Description: "CSV 或 XLSX 文件", File: true, Required: true},
+			{Name: "dryRun", Description: "为 true 时只校验不写入"},
+		}},
+	"GET /api/user/import/template": {Tag: "用户", Summary: "下载导入模板", File: fileDownload,
+		Query: []openapi.Parameter{openapi.Query("format", "string", "csv（默认）或 xlsx")}},
+	"GET /api/user/import/:id/report": {Tag: "用户", Summary: "下载导入结果报告", File: fileDownload},
+
+	// 角色
+	"GET /api/role/list": {Tag: "角色", Summary: "获取角色列表", Response: controllers.RoleListItem{}, List: true, Query: params(paginationParams, []openapi.Parameter{
+		openapi.Query("code", "string", "角色编码（模糊匹配）"),
+		openapi.Query("status", "integer", "状态"),
+	})},
+	"POST /api/role":                  {Tag: "角色", Summary: "创建角色", Body: controllers.CreateRoleRequest{}, Response: controllers.RoleResponse{}},
+	"PUT /api/role/:id":               {Tag: "角色", Summary: "更新角色", Body: controllers.UpdateRoleRequest{}, Response: controllers.RoleResponse{}},
+	"DELETE /api/role/:id":            {Tag: "角色", Summary: "删除角色（放入回收站）", Query: deleteParams},
+	"GET /api/role/:id/delete-impact": {Tag: "角色", Summary: "预览删除角色的影响", Response: services.DeletionImpact{}},
+	"GET /api/role/menus":             {Tag: "角色", Summary: "获取所有菜单（角色分配菜单用）", Response: []controllers.MenuOption{}},
+
+	// 回收站
+	"GET /api/recycle": {Tag: "回收站", Summary: "获取回收站列表", Response: controllers.RecycleEntryResponse{}, List: true, Query: params(paginationParams, []openapi.Parameter{
+		openapi.Query("kind", "string", "user、role、course 或 rule，为空时查询全部"),
+	})},
+	"POST /api/recycle/:kind/:id/restore": {Tag: "回收站", Summary: "恢复数据", Description: "唯一字段冲突时返回 RESTORE_CONFLICT，data.conflicts 为冲突字段", Body: controllers.RestoreRecycleRequest{}},
+	"DELETE /api/recycle/:kind/:id":       {Tag: "回收站", Summary: "彻底删除"},
+
+	// 课程管理
+	"GET /api/course/list": {Tag: "课程管理", Summary: "获取课程列表", Description: "讲师只能看到自己负责或协作的课程", Response: controllers.CourseResponse{}, List: true, Query: params(paginationParams, []openapi.Parameter{
+		openapi.Query("title", "string", "标题（模糊匹配）"),
+		openapi.Query("status", "integer", "课程状态"),
+	})},
+	"GET /api/course/list/export": {Tag: "课程管理", Summary: "导出课程列表", File: fileDownload, Query: params(exportParams, []openapi.Parameter{
+		openapi.Query("title", "string", "标题（模糊匹配）"),
+		openapi.Query("status", "integer", "课程状态"),
+	})},
+	"GET /api/course/:id":               {Tag: "课程管理", Summary: "获取课程详情", Response: controllers.CourseDetailResponse{}},
+	"POST /api/course":                  {Tag: "课程管理", Summary: "创建课程", Body: controllers.CreateCourseRequest{}, Response: controllers.CourseResponse{}},
+	"PUT /api/course/:id":               {Tag: "课程管理", Summary: "更新课程", Body: controllers.UpdateCourseRequest{}, Response: controllers.UpdateCourseResponse{}},
+	"DELETE /api/course/:id":            {Tag: "课程管理", Summary: "删除课程（放入回收站）", Query: deleteParams},
+	"GET /api/course/:id/delete-impact": {Tag: "课程管理", Summary: "预览删除课程的影响", Response: services.DeletionImpact{}},
+	"POST /api/course/:id/publish":      {Tag: "课程管理", Summary: "发布或下架课程", Body: controllers.PublishCourseRequest{}, Response: controllers.CourseResponse{}},
+	"PUT /api/course/:id/schedule":      {Tag: "课程管理", Summary: "设置定时发布/下架时间", Body: controllers.ScheduleCourseRequest{}, Response: controllers.CourseResponse{}},
+	"GET /api/course/:id/instructors":   {Tag: "课程管理", Summary: "获取课程负责人和协作讲师", Response: controllers.CourseInstructorsResponse{}},
+	"PUT /api/course/:id/instructors":   {Tag: "课程管理", Summary: "修改课程负责人和协作讲师", Body: controllers.UpdateInstructorsRequest{}, Response: controllers.CourseInstructorsResponse{}},
+	"POST /api/course/scorm/import": {Tag: "课程管理", Summary: "导入 SCORM 课件包", Response: controllers.ScormPackageResponse{},
+		Form: []openapi.FormField{
+			{Name: "file", Description: "SCORM 课件包（zip）", File: true, Required: true},
+			{Name: "courseId", Description: "关联的课程 ID", Required: true},
+		}},
+	"GET /api/course/:id/export": {Tag: "课程管理", Summary: "导出课程包", File: zipFile},
+	"POST /api/course/import": {Tag: "课程管理", Summary: "导入课程包（草稿）", Response: services.CourseImportResult{},
+		Form: []openapi.FormField{{Name: "file", Description: "课程包（zip）", File: true, Required: true}}},
+
+	// 课程草稿和修订版本
+	"GET /api/course/:id/draft":    {Tag: "课程修订", Summary: "获取课程草稿", Response: controllers.DraftResponse{}},
+	"DELETE /api/course/:id/draft": {Tag: "课程修订", Summary: "丢弃课程草稿"},
+	"GET /api/course/:id/draft/diff": {Tag: "课程修订", Summary: "比较草稿与线上内容", Response: controllers.DraftDiffResponse{},
+		Query: []openapi.Parameter{openapi.Query("revision", "integer", "与指定修订版本比较")}},
+	"POST /api/course/:id/draft/publish":                {Tag: "课程修订", Summary: "将草稿发布为新的修订版本", Description: "已发布或待定时发布的课程提交草稿审核，审核通过后才生成修订版本", Body: controllers.PublishDraftRequest{}, Response: controllers.PublishDraftResponse{}},
+	"GET /api/course/:id/revisions":                     {Tag: "课程修订", Summary: "获取修订版本列表", Response: []controllers.RevisionListItem{}},
+	"GET /api/course/:id/revisions/:revision":           {Tag: "课程修订", Summary: "获取指定修订版本内容", Response: controllers.RevisionResponse{}},
+	"POST /api/course/:id/revisions/:revision/rollback": {Tag: "课程修订", Summary: "回滚到指定修订版本", Description: "已发布或待定时发布的课程以该版本内容替换草稿并提交审核", Response: controllers.PublishDraftResponse{}},
+
+	// 课程发布审核
+	"POST /api/course/:id/review/submit":   {Tag: "课程审核", Summary: "提交发布审核", Body: controllers.SubmitReviewRequest{}, Response: controllers.CourseResponse{}},
+	"POST /api/course/:id/review/withdraw": {Tag: "课程审核", Summary: "撤回审核", Response: controllers.CourseResponse{}},
+	"GET /api/course/review/pending":       {Tag: "课程审核", Summary: "获取待当前用户审核的课程", Query: paginationParams, Response: controllers.CourseResponse{}, List: true},
+	"POST /api/course/:id/review/approve":  {Tag: "课程审核", Summary: "审核通过", Description: "线上课程审核已提交的草稿，通过后生成新的修订版本", Body: controllers.ReviewCommentRequest{}, Response: controllers.CourseResponse{}},
+	"POST /api/course/:id/review/reject":   {Tag: "课程审核", Summary: "审核驳回", Body: controllers.ReviewCommentRequest{}, Response: controllers.CourseResponse{}},
+	"GET /api/course/:id/review/history":   {Tag: "课程审核", Summary: "获取课程状态流转记录", Response: controllers.ReviewHistoryResponse{}},
+
+	// 学习进度和统计
+	"GET /api/admin/course/:id/progress": {Tag: "学习数据", Summary: "查看课程学习进度", Response: controllers.CourseProgressItem{}, List: true, Query: params(paginationParams, []openapi.Parameter{
+		openapi.Query("username", "string", "用户名（模糊匹配）"),
+	})},
+	"GET /api/admin/course/:id/progress/export": {Tag: "学习数据", Summary: "导出课程学习进度", File: fileDownload, Query: params(exportParams, []openapi.Parameter{
+		openapi.Query("username", "string", "用户名（模糊匹配）"),
+	})},
+	"GET /api/admin/user/:id/progress":        {Tag: "学习数据", Summary: "查看用户学习进度", Response: controllers.UserProgressItem{}, List: true, Query: paginationParams},
+	"GET /api/admin/user/:id/progress/export": {Tag: "学习数据", Summary: "导出用户学习进度", File: fileDownload, Query: exportParams},
+	"GET /api/admin/analytics/courses":        {Tag: "学习数据", Summary: "课程完成率统计", Query: analyticsParams, Response: []services.CourseCompletionStat{}},
+	"GET /api/admin/analytics/departments":    {Tag: "学习数据", Summary: "部门完成率统计", Query: analyticsParams, Response: []services.DepartmentCompletionStat{}},
+	"GET /api/admin/analytics/active-learners": {Tag: "学习数据", Summary: "活跃学员趋势", Response: controllers.ActiveLearnersResponse{}, Query: params(analyticsParams, []openapi.Parameter{
+		openapi.Query("granularity", "string", "day（默认，最近 30 天）或 week（最近 12 周）"),
+	})},
+	"GET /api/admin/analytics/dropoff": {Tag: "学习数据", Summary: "学习进度分布", Query: analyticsParams, Response: []services.ProgressBucket{}},
+	"GET /api/admin/analytics/rankings": {Tag: "学习数据", Summary: "完成率排名前后的课程", Response: controllers.CourseRankingsResponse{}, Query: params(analyticsParams, []openapi.Parameter{
+		openapi.Query("limit", "integer", "前后各取的数量，默认 5"),
+		openapi.Query("minLearners", "integer", "参与排名的最少学员数，默认 1"),
+	})},
+	"GET /api/admin/learning/events": {Tag: "学习数据", Summary: "查询学习事件", Response: controllers.LearningEventResponse{}, List: true, Query: params(paginationParams, []openapi.Parameter{
+		openapi.Query("userId", "integer", "用户 ID"),
+		openapi.Query("courseId", "integer", "课程 ID"),
+		openapi.Query("verb", "string", "事件类型"),
+		openapi.Query("startTime", "string", "开始时间"),
+		openapi.Query("endTime", "string", "结束时间"),
+	})},
+	"GET /api/admin/learning/events/summary": {Tag: "学习数据", Summary: "学习事件汇总", Response: services.LearningEventSummary{}, Query: []openapi.Parameter{
+		openapi.Query("userId", "integer", "用户 ID"),
+		openapi.Query("courseId", "integer", "课程 ID"),
+		openapi.Query("startTime", "string", "开始时间"),
+		openapi.Query("endTime", "string", "结束时间"),
+	}},
+
+	// 导出任务
+	"GET /api/export/jobs":              {Tag: "导出任务", Summary: "获取当前用户的导出任务", Response: []controllers.ExportJobResponse{}},
+	"GET /api/export/jobs/:id":          {Tag: "导出任务", Summary: "获取导出任务状态", Response: controllers.ExportJobResponse{}},
+	"GET /api/export/jobs/:id/download": {Tag: "导出任务", Summary: "下载导出文件", File: fileDownload},
+
+	// 学习（员工端）
+	"GET /api/learn/courses": {Tag: "学习", Summary: "获取可学习的课程列表", Response: controllers.LearnCourseItem{}, List: true, Query: params(paginationParams, []openapi.Parameter{
+		openapi.Query("title", "string", "标题（模糊匹配）"),
+	})},
+	"GET /api/learn/course/:id":               {Tag: "学习", Summary: "获取课程详情和学习记录", Response: controllers.LearnCourseDetail{}},
+	"POST /api/learn/course/:id/progress":     {Tag: "学习", Summary: "更新学习进度", Body: controllers.UpdateProgressRequest{}, Response: controllers.LearningRecordResponse{}},
+	"POST /api/learn/course/:id/complete":     {Tag: "学习", Summary: "标记课程完成", Body: controllers.CompleteCourseRequest{}, Response: controllers.LearningRecordResponse{}},
+	"POST /api/learn/course/:id/event":        {Tag: "学习", Summary: "上报学习事件（开始、暂停、拖动）", Body: controllers.RecordEventRequest{}, Response: controllers.LearningEventResponse{}},
+	"GET /api/learn/course/:id/scorm":         {Tag: "SCORM", Summary: "获取课件启动信息和运行时数据", Response: controllers.ScormSessionResponse{}},
+	"POST /api/learn/course/:id/scorm/commit": {Tag: "SCORM", Summary: "保存课件运行时数据", Body: controllers.ScormCommitRequest{}, Response: controllers.ScormCommitResponse{}},
+
+	// xAPI 管理
+	"GET /api/xapi/clients":        {Tag: "xAPI", Summary: "获取 xAPI 客户端列表", Response: []controllers.XAPIClientResponse{}},
+	"POST /api/xapi/clients":       {Tag: "xAPI", Summary: "创建 xAPI 客户端", Description: "密钥只在创建时返回一次", Body: controllers.CreateXAPIClientRequest{}, Response: controllers.CreateXAPIClientResponse{}},
+	"DELETE /api/xapi/clients/:id": {Tag: "xAPI", Summary: "删除 xAPI 客户端"},
+	"GET /api/xapi/activities": {Tag: "xAPI", Summary: "获取 xAPI 活动与课程的映射", Response: []controllers.XAPIActivityResponse{},
+		Query: []openapi.Parameter{openapi.Query("courseId", "integer", "课程 ID")}},
+	"POST /api/xapi/activities":       {Tag: "xAPI", Summary: "创建或更新 xAPI 活动映射", Body: controllers.SaveXAPIActivityRequest{}, Response: controllers.XAPIActivityResponse{}},
+	"DELETE /api/xapi/activities/:id": {Tag: "xAPI", Summary: "删除 xAPI 活动映射"},
+}
+
+// documentErrorCodes 在错误响应结构中列出全部错误码及对应的 HTTP 状态码
+func documentErrorCodes(doc *openapi.Document) {
+	schema, ok := doc.Components.Schemas["Response"]
+	if !ok {
+		return
+	}
+	field, ok := schema.Properties["errorCode"]
+	if !ok {
+		return
+	}
+	var lines []string
+	field.Enum = nil
+	for _, code := range utils.ErrorCodes {
+		field.Enum = append(field.Enum, code.Code)
+		line := fmt.Sprintf("- %s（%d）：%s", code.Code, code.Status, code.Description)
+		if code.Business {
+			line += "，api_compat 开启时以 200 返回"
+		}
+		lines = append(lines, line)
+	}
+	field.Description = "错误码：\n" + strings.Join(lines, "\n")
+}
@@ -0,0 +1,98 @@
+package routes
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"learn-hub-backend/database"
+	"learn-hub-backend/logging"
+	"learn-hub-backend/models"
+	"learn-hub-backend/testutil"
+	"learn-hub-backend/utils"
+
+	"gorm.io/gorm/logger"
+)
+
+// statementLog 执行的 SQL 及其 context 中的请求 ID
+type statementLog struct {
+	requestID string
+	sql       string
+}
+
+// statementRecorder 记录每条 SQL 执行时 context 中的请求 ID（SQL 日志和慢查询日志从该 context 取 request_id）
+type statementRecorder struct {
+	statements []statementLog
+}
+
+func (r *statementRecorder) LogMode(logger.LogLevel) logger.Interface { return r }
+
+func (r *statementRecorder) Info(context.Context, string, ...interface{}) {}
+
+func (r *statementRecorder) Warn(context.Context, string, ...interface{}) {}
+
+func (r *statementRecorder) Error(context.Context, string, ...interface{}) {}
+
+func (r *statementRecorder) Trace(ctx context.Context, _ time.Time, fc func() (string, int64), _ error) {
+	sql, _ := fc()
+	r.statements = append(r.statements, statementLog{requestID: logging.RequestID(ctx), sql: sql})
+}
+
+// 接口中执行的 SQL 都应使用带 request_id 的请求 context
+func TestRequestSQLLogsCarryRequestID(t *testing.T) {
+	r := router(t)
+	db := testutil.SetupDB(t)
+	recorder := &statementRecorder{}
+	db.Logger = recorder
+
+	var admin models.User
+	if err := database.DB.Where("username = ?", "admin").First(&admin).Error; err != nil {
+		t.Fatal(err)
+	}
+	token, err := utils.GenerateToken(admin.ID, admin.Username, admin.Access)
+	if err != nil {
+		t.Fatal(err)
+	}
+	course := testutil.CreateCourse(t, "go-basics", admin.ID)
+	courseID := strconv.FormatUint(uint64(course.ID), 10)
+
+	// 各接口的主查询表，用于确认服务层查询使用了请求 context
+	endpoints := map[string]string{
+		"/api/course/list":                            "sys_course",
+		"/api/course/" + courseID + "/draft":          "sys_course_draft",
+		"/api/course/" + courseID + "/revisions":      "sys_course_revision",
+		"/api/course/" + courseID + "/delete-impact":  "sys_course_record",
+		"/api/course/" + courseID + "/review/history": "sys_course_transition",
+		"/api/admin/analytics/active-learners":        "sys_learning_event",
+		"/api/admin/learning/events":                  "sys_learning_event",
+		"/api/recycle":                                "sys_recycle_bin",
+		"/api/user/list":                              "sys_user",
+	}
+	for path, table := range endpoints {
+		seen := len(recorder.statements)
+		requestID := "req-" + strings.ReplaceAll(strings.Trim(path, "/"), "/", "-")
+		req := httptest.NewRequest(http.MethodGet, path, nil)
+		req.Header.Set("Authorization", "Bearer "+token)
+		req.Header.Set(logging.RequestIDHeader, requestID)
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		if w.Code != http.StatusOK {
+			t.Fatalf("GET %s 返回 %d: %s", path, w.Code, w.Body.String())
+		}
+
+		found := false
+		for _, statement := range recorder.statements[seen:] {
+			if statement.requestID != requestID {
+				t.Errorf("GET %s 的 SQL 没有带上 request_id: %s", path, statement.sql)
+			}
+			found = found || strings.Contains(statement.sql, "`"+table+"`") || strings.Contains(statement.sql, `"`+table+`"`)
+		}
+		if !found {
+			t.Errorf("GET %s 没有执行 %s 的查询", path, table)
+		}
+	}
+}
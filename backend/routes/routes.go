@@ -1,30 +1,35 @@
 package routes
 
 import (
+	"log/slog"
+
+	"learn-hub-backend/config"
 	"learn-hub-backend/controllers"
 	"learn-hub-backend/middleware"
+	"learn-hub-backend/openapi"
+	"learn-hub-backend/utils"
 
 	"github.com/gin-gonic/gin"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
 )
 
 // SetupRoutes 设置路由
 func SetupRoutes() *gin.Engine {
-	r := gin.Default()
+	// gin 的调试输出不是结构化日志，统一关闭；debug 日志级别下由下方输出路由表
+	gin.SetMode(gin.ReleaseMode)
+	r := gin.New()
+
+	// 请求 ID、链路追踪、panic 恢复和结构化访问日志（替代 gin 默认的文本日志）
+	r.Use(middleware.RequestIDMiddleware(), middleware.TracingMiddleware(), middleware.RecoveryMiddleware(), middleware.AccessLogMiddleware())
 
 	// CORS中间件
-	r.Use(func(c *gin.Context) {
-		c.Writer.Header().Set("Access-Control-Allow-Origin", "*")
-		c.Writer.Header().Set("Access-Control-Allow-Credentials", "true")
-		c.Writer.Header().Set("Access-Control-Allow-Headers", "Content-Type, Content-Length, Accept-Encoding, X-CSRF-Token, Authorization, accept, origin, Cache-Control, X-Requested-With")
-		c.Writer.Header().Set("Access-Control-Allow-Methods", "POST, OPTIONS, GET, PUT, DELETE")
-
-		if c.Request.Method == "OPTIONS" {
-			c.AbortWithStatus(204)
-			return
-		}
+	r.Use(middleware.CORSMiddleware(config.AppConfig.CORSOrigins))
 
-		c.Next()
-	})
+	// Prometheus 指标：请求数和耗时按路由统计，/metrics 供 Prometheus 抓取
+	if config.AppConfig.MetricsEnabled {
+		r.Use(middleware.MetricsMiddleware())
+		r.GET("/metrics", gin.WrapH(promhttp.Handler()))
+	}
 
 	// 初始化控制器
 	authCtrl := controllers.NewAuthController()
@@ -35,6 +40,38 @@ func SetupRoutes() *gin.Engine {
 	courseCtrl := controllers.NewCourseController()
 	learnCtrl := controllers.NewLearnController()
 	progressCtrl := controllers.NewProgressController()
+	eventCtrl := controllers.NewLearningEventController()
+	xapiCtrl := controllers.NewXAPIController()
+	scormCtrl := controllers.NewScormController()
+	revisionCtrl := controllers.NewCourseRevisionController()
+	reviewCtrl := controllers.NewCourseReviewController()
+	analyticsCtrl := controllers.NewAnalyticsController()
+	exportCtrl := controllers.NewExportController()
+	userImportCtrl := controllers.NewUserImportController()
+	recycleCtrl := controllers.NewRecycleController()
+	deletionCtrl := controllers.NewDeletionController()
+	healthCtrl := controllers.NewHealthController()
+	docCtrl := controllers.NewOpenAPIController(func() *openapi.Document {
+		doc := openapi.Build(apiInfo, r.Routes(), apiDocs, utils.Response{})
+		documentErrorCodes(doc)
+		return doc
+	})
+
+	// 存活和就绪检查（供 Kubernetes 探针使用，不需要认证）
+	r.GET("/healthz", healthCtrl.Healthz)
+	r.GET("/readyz", healthCtrl.Readyz)
+
+	// SCORM 课件静态资源（由课件 iframe 直接加载，不携带 token）
+	r.GET("/scorm/content/:packageId/*filepath", scormCtrl.ServeAsset)
+
+	// xAPI 学习记录存储（LRS），使用客户端 Basic 认证
+	xapi := r.Group("/xapi")
+	xapi.Use(middleware.XAPIMiddleware())
+	{
+		xapi.POST("/statements", xapiCtrl.PostStatements)
+		xapi.PUT("/statements", xapiCtrl.PutStatement)
+		xapi.GET("/statements", xapiCtrl.GetStatements)
+	}
 
 	// API路由组
 	api := r.Group("/api")
@@ -46,6 +83,10 @@ func SetupRoutes() *gin.Engine {
 			auth.GET("/captcha", authCtrl.GetCaptcha)
 		}
 
+		// 接口文档（不需要token）
+		api.GET("/openapi.json", docCtrl.GetSpec)
+		api.GET("/docs", docCtrl.SwaggerUI)
+
 		// 需要认证的路由
 		api.Use(middleware.AuthMiddleware())
 		{
@@ -66,34 +107,131 @@ func SetupRoutes() *gin.Engine {
 			api.POST("/user", userCtrl.CreateUser)
 			api.PUT("/user/:id", userCtrl.UpdateUser)
 			api.DELETE("/user/:id", userCtrl.DeleteUser)
+			api.GET("/user/:id/delete-impact", deletionCtrl.GetUserImpact)
 			api.GET("/user/roles", userCtrl.GetAllRoles)
+			api.GET("/user/list/export", middleware.AdminMiddleware(), exportCtrl.ExportUsers)
+
+			// 批量导入会修改密码和角色（仅管理员）
+			userImport := api.Group("/user/import", middleware.AdminMiddleware())
+			{
+				userImport.POST("", userImportCtrl.ImportUsers)
+				userImport.GET("/template", userImportCtrl.GetImportTemplate)
+				userImport.GET("/:id/report", userImportCtrl.DownloadReport)
+			}
 
 			// 角色管理相关
 			api.GET("/role/list", roleCtrl.GetRoleList)
 			api.POST("/role", roleCtrl.CreateRole)
 			api.PUT("/role/:id", roleCtrl.UpdateRole)
 			api.DELETE("/role/:id", roleCtrl.DeleteRole)
+			api.GET("/role/:id/delete-impact", deletionCtrl.GetRoleImpact)
 			api.GET("/role/menus", roleCtrl.GetAllMenus)
 
-			// 课程管理相关（管理员）
-			api.GET("/course/list", courseCtrl.GetCourseList)
-			api.GET("/course/:id", courseCtrl.GetCourseDetail)
-			api.POST("/course", courseCtrl.CreateCourse)
-			api.PUT("/course/:id", courseCtrl.UpdateCourse)
-			api.DELETE("/course/:id", courseCtrl.DeleteCourse)
-			api.POST("/course/:id/publish", courseCtrl.PublishCourse)
+			// 回收站（仅管理员）
+			recycle := api.Group("/recycle", middleware.AdminMiddleware())
+			{
+				recycle.GET("", recycleCtrl.GetRecycleList)
+				recycle.POST("/:kind/:id/restore", recycleCtrl.RestoreRecycle)
+				recycle.DELETE("/:kind/:id", recycleCtrl.PurgeRecycle)
+			}
+
+			// 课程管理相关（管理员和讲师，讲师只能管理自己负责或协作的课程）
+			courses := api.Group("", middleware.CourseScopeMiddleware(), middleware.CourseOwnerMiddleware())
+			{
+				courses.GET("/course/list", courseCtrl.GetCourseList)
+				courses.GET("/course/list/export", exportCtrl.ExportCourses)
+				courses.GET("/course/:id", courseCtrl.GetCourseDetail)
+				courses.POST("/course", courseCtrl.CreateCourse)
+				courses.PUT("/course/:id", courseCtrl.UpdateCourse)
+				courses.DELETE("/course/:id", courseCtrl.DeleteCourse)
+				courses.GET("/course/:id/delete-impact", deletionCtrl.GetCourseImpact)
+				courses.POST("/course/:id/publish", courseCtrl.PublishCourse)
+				courses.PUT("/course/:id/schedule", courseCtrl.ScheduleCourse)
+				courses.GET("/course/:id/instructors", courseCtrl.GetInstructors)
+				courses.PUT("/course/:id/instructors", courseCtrl.UpdateInstructors)
+				courses.POST("/course/scorm/import", scormCtrl.ImportPackage)
+				courses.GET("/course/:id/export", courseCtrl.ExportCourse)
+				courses.POST("/course/import", courseCtrl.ImportCourse)
+
+				// 课程草稿和修订版本
+				courses.GET("/course/:id/draft", revisionCtrl.GetDraft)
+				courses.DELETE("/course/:id/draft", revisionCtrl.DiscardDraft)
+				courses.GET("/course/:id/draft/diff", revisionCtrl.DiffDraft)
+				courses.POST("/course/:id/draft/publish", revisionCtrl.PublishDraft)
+				courses.GET("/course/:id/revisions", revisionCtrl.GetRevisionList)
+				courses.GET("/course/:id/revisions/:revision", revisionCtrl.GetRevisionDetail)
+				courses.POST("/course/:id/revisions/:revision/rollback", revisionCtrl.RollbackRevision)
+
+				// 提交和撤回发布审核
+				courses.POST("/course/:id/review/submit", reviewCtrl.SubmitReview)
+				courses.POST("/course/:id/review/withdraw", reviewCtrl.WithdrawReview)
+
+				// 课程学习进度
+				courses.GET("/admin/course/:id/progress", progressCtrl.GetCourseProgress)
+				courses.GET("/admin/course/:id/progress/export", exportCtrl.ExportCourseProgress)
+
+				// 学习数据统计（讲师只统计自己的课程）
+				courses.GET("/admin/analytics/courses", analyticsCtrl.GetCourseCompletion)
+				courses.GET("/admin/analytics/departments", analyticsCtrl.GetDepartmentCompletion)
+				courses.GET("/admin/analytics/active-learners", analyticsCtrl.GetActiveLearners)
+				courses.GET("/admin/analytics/dropoff", analyticsCtrl.GetDropOff)
+				courses.GET("/admin/analytics/rankings", analyticsCtrl.GetCourseRankings)
+			}
+
+			// 课程发布审核（审核人）
+			api.GET("/course/review/pending", reviewCtrl.GetPendingReviews)
+			api.POST("/course/:id/review/approve", reviewCtrl.ApproveReview)
+			api.POST("/course/:id/review/reject", reviewCtrl.RejectReview)
+			api.GET("/course/:id/review/history", reviewCtrl.GetReviewHistory)
 
 			// 学习相关（员工端）
 			api.GET("/learn/courses", learnCtrl.GetCourses)
 			api.GET("/learn/course/:id", learnCtrl.GetCourseDetail)
 			api.POST("/learn/course/:id/progress", learnCtrl.UpdateProgress)
 			api.POST("/learn/course/:id/complete", learnCtrl.CompleteCourse)
-
-			// 学习进度查看（管理员）
-			api.GET("/admin/course/:id/progress", progressCtrl.GetCourseProgress)
-			api.GET("/admin/user/:id/progress", progressCtrl.GetUserProgress)
+			api.POST("/learn/course/:id/event", learnCtrl.RecordEvent)
+			api.GET("/learn/course/:id/scorm", scormCtrl.Initialize)
+			api.POST("/learn/course/:id/scorm/commit", scormCtrl.Commit)
+
+			// 用户学习进度查看（讲师只能看到自己课程的记录）
+			api.GET("/admin/user/:id/progress", middleware.CourseScopeMiddleware(), progressCtrl.GetUserProgress)
+			api.GET("/admin/user/:id/progress/export", middleware.CourseScopeMiddleware(), exportCtrl.ExportUserProgress)
+
+			// 后台导出任务
+			api.GET("/export/jobs", exportCtrl.GetJobList)
+			api.GET("/export/jobs/:id", exportCtrl.GetJob)
+			api.GET("/export/jobs/:id/download", exportCtrl.DownloadJob)
+
+			// 学习事件查询（仅管理员）
+			events := api.Group("/admin/learning/events", middleware.AdminMiddleware())
+			{
+				events.GET("", eventCtrl.GetEventList)
+				events.GET("/summary", eventCtrl.GetEventSummary)
+			}
+
+			// xAPI 客户端和活动映射管理（仅管理员）
+			xapiAdmin := api.Group("/xapi", middleware.AdminMiddleware())
+			{
+				xapiAdmin.GET("/clients", xapiCtrl.GetClientList)
+				xapiAdmin.POST("/clients", xapiCtrl.CreateClient)
+				xapiAdmin.DELETE("/clients/:id", xapiCtrl.DeleteClient)
+				xapiAdmin.GET("/activities", xapiCtrl.GetActivityList)
+				xapiAdmin.POST("/activities", xapiCtrl.SaveActivity)
+				xapiAdmin.DELETE("/activities/:id", xapiCtrl.DeleteActivity)
+			}
 		}
 	}
 
+	// 未注册的路由和方法同样返回统一错误结构
+	r.HandleMethodNotAllowed = true
+	r.NoRoute(func(c *gin.Context) { utils.Fail(c, utils.CodeNotFound, "") })
+	r.NoMethod(func(c *gin.Context) { utils.Fail(c, utils.CodeMethodNotAllowed, "") })
+
+	for _, route := range r.Routes() {
+		slog.Debug("注册路由", "method", route.Method, "path", route.Path, "handler", route.Handler)
+	}
+	if missing := openapi.Undocumented(r.Routes(), apiDocs); len(missing) > 0 {
+		slog.Warn("以下路由缺少接口文档，请在 routes/openapi.go 中登记", "routes", missing)
+	}
 	return r
 }
@@ -0,0 +1,113 @@
+package routes
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"learn-hub-backend/config"
+	"learn-hub-backend/models"
+	"learn-hub-backend/openapi"
+	"learn-hub-backend/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+var testRouter *gin.Engine
+
+// router 构建完整路由表（包括 /metrics），指标中间件只能注册一次，所有测试共用同一个路由
+func router(t *testing.T) *gin.Engine {
+	t.Helper()
+	if testRouter == nil {
+		cfg := config.Default()
+		cfg.MetricsEnabled = true
+		config.AppConfig = cfg
+		testRouter = SetupRoutes()
+	}
+	return testRouter
+}
+
+// login 为用户签发访问令牌
+func login(t *testing.T, user *models.User) string {
+	t.Helper()
+	token, err := utils.GenerateToken(user.ID, user.Username, user.Access)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return token
+}
+
+// serve 以 token 对应的用户身份请求接口，body 为 JSON 字符串，为空时不带请求体
+func serve(t *testing.T, method, path, token, body string) *httptest.ResponseRecorder {
+	t.Helper()
+	var reader io.Reader
+	if body != "" {
+		reader = strings.NewReader(body)
+	}
+	req := httptest.NewRequest(method, path, reader)
+	if body != "" {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	w := httptest.NewRecorder()
+	router(t).ServeHTTP(w, req)
+	return w
+}
+
+// errorCode 解析响应中的错误码，成功时为空
+func errorCode(t *testing.T, w *httptest.ResponseRecorder) string {
+	t.Helper()
+	var resp utils.Response
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("响应不是合法的 JSON（%d）: %s", w.Code, w.Body.String())
+	}
+	return resp.ErrorCode
+}
+
+// 每个路由都必须在 apiDocs 中登记
+func TestRoutesDocumented(t *testing.T) {
+	r := router(t)
+	if missing := openapi.Undocumented(r.Routes(), apiDocs); len(missing) > 0 {
+		t.Fatalf("以下路由缺少接口文档，请在 routes/openapi.go 中登记: %v", missing)
+	}
+}
+
+// apiDocs 中不能保留已删除路由的说明
+func TestDocumentedRoutesExist(t *testing.T) {
+	registered := make(map[string]bool)
+	for _, route := range router(t).Routes() {
+		registered[route.Method+" "+route.Path] = true
+	}
+	for key := range apiDocs {
+		if !registered[key] {
+			t.Errorf("接口文档 %q 没有对应的路由", key)
+		}
+	}
+}
+
+func TestOpenAPISpec(t *testing.T) {
+	w := httptest.NewRecorder()
+	router(t).ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/openapi.json", nil))
+	if w.Code != http.StatusOK {
+		t.Fatalf("GET /api/openapi.json 返回 %d", w.Code)
+	}
+
+	var doc struct {
+		OpenAPI string                     `json:"openapi"`
+		Paths   map[string]json.RawMessage `json:"paths"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &doc); err != nil {
+		t.Fatalf("文档不是合法的 JSON: %v", err)
+	}
+	if doc.OpenAPI == "" || len(doc.Paths) == 0 {
+		t.Fatalf("文档缺少 openapi 版本或 paths: %s", w.Body.String())
+	}
+	if _, ok := doc.Paths["/api/course/{id}/draft/publish"]; !ok {
+		t.Fatal("路由模板应转换为 OpenAPI 路径参数格式")
+	}
+}
@@ -0,0 +1,84 @@
+package routes
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"learn-hub-backend/database"
+	"learn-hub-backend/models"
+	"learn-hub-backend/testutil"
+	"learn-hub-backend/tracing"
+	"learn-hub-backend/utils"
+
+	"go.opentelemetry.io/otel"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// 接口中执行的 SQL 语句都应挂在该请求的 span 下（服务层使用请求 context 查询）
+func TestRequestSQLSpansUseRequestContext(t *testing.T) {
+	r := router(t)
+	db := testutil.SetupDB(t)
+	if err := tracing.InstrumentDB(db); err != nil {
+		t.Fatal(err)
+	}
+
+	recorder := tracetest.NewSpanRecorder()
+	previous := otel.GetTracerProvider()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+	t.Cleanup(func() { otel.SetTracerProvider(previous) })
+
+	var admin models.User
+	if err := database.DB.Where("username = ?", "admin").First(&admin).Error; err != nil {
+		t.Fatal(err)
+	}
+	token, err := utils.GenerateToken(admin.ID, admin.Username, admin.Access)
+	if err != nil {
+		t.Fatal(err)
+	}
+	testutil.CreateCourse(t, "go-basics", admin.ID)
+
+	// 各接口的主查询，用于确认服务层查询使用了请求 context
+	endpoints := map[string]string{
+		"/api/course/list":             "gorm.query sys_course",
+		"/api/admin/analytics/courses": "gorm.row r",
+		"/api/course/review/pending":   "gorm.query sys_course",
+		"/api/currentUser":             "gorm.query sys_user",
+	}
+	for path, mainQuery := range endpoints {
+		seen := len(recorder.Ended())
+		req := httptest.NewRequest(http.MethodGet, path, nil)
+		req.Header.Set("Authorization", "Bearer "+token)
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		if w.Code != http.StatusOK {
+			t.Fatalf("GET %s 返回 %d: %s", path, w.Code, w.Body.String())
+		}
+
+		var request trace.SpanContext
+		var queries []sdktrace.ReadOnlySpan
+		for _, span := range recorder.Ended()[seen:] {
+			if span.SpanKind() == trace.SpanKindServer {
+				request = span.SpanContext()
+			} else if strings.HasPrefix(span.Name(), "gorm.") {
+				queries = append(queries, span)
+			}
+		}
+		if !request.IsValid() {
+			t.Fatalf("GET %s 没有记录请求 span", path)
+		}
+		found := false
+		for _, span := range queries {
+			if span.Parent().SpanID() != request.SpanID() {
+				t.Errorf("GET %s 的 %s 不在请求 span 下", path, span.Name())
+			}
+			found = found || span.Name() == mainQuery
+		}
+		if !found {
+			t.Errorf("GET %s 没有记录 %s（服务层未使用请求 context）", path, mainQuery)
+		}
+	}
+}
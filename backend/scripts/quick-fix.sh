@@ -20,9 +20,10 @@ SELECT '密码更新完成' as result, username, status FROM sys_user WHERE user
 EOF
 
 echo ""
-echo "2. 验证密码哈希..."
-cd /Users/xuanxuanzi/home/s/javapub/learn-hub-v2/backend
-go run scripts/test-password.go
+echo "2. 通过管理命令重置密码并启用账号..."
+cd "$(dirname "$0")/.." || exit 1
+go run . user reset-password admin -password admin123 || exit 1
+go run . user unlock admin || exit 1
 
 echo ""
 echo "✅ 修复完成！现在可以使用 admin/admin123 登录"
@@ -0,0 +1,462 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"math"
+	"sort"
+	"time"
+
+	"learn-hub-backend/config"
+	"learn-hub-backend/database"
+	"learn-hub-backend/models"
+
+	"gorm.io/gorm"
+)
+
+// 活跃学员统计粒度
+const (
+	GranularityDay  = "day"
+	GranularityWeek = "week"
+)
+
+// maxAnalyticsBuckets 单次统计最多的时间段数量
+const maxAnalyticsBuckets = 400
+
+const statDateLayout = "2006-01-02"
+
+// ErrInvalidAnalyticsRange 统计时间范围不合法
+var ErrInvalidAnalyticsRange = errors.New("统计时间范围不合法")
+
+// AnalyticsFilter 统计筛选条件
+type AnalyticsFilter struct {
+	Scope     *CourseScope // 可管理的课程范围，讲师只统计自己的课程
+	CourseID  uint
+	StartTime *time.Time // 学习记录按开始学习时间、学习事件按发生时间筛选
+	EndTime   *time.Time
+}
+
+// CourseCompletionStat 课程完成率统计
+type CourseCompletionStat struct {
+	CourseID            uint    `json:"courseId"`
+	Title               string  `json:"title"`
+	Learners            int64   `json:"learners"`
+	Completed           int64   `json:"completed"`
+	CompletionRate      float64 `json:"completionRate"`      // 完成率(%)
+	AvgProgress         float64 `json:"avgProgress"`         // 平均进度
+	AvgCompleteDuration float64 `json:"avgCompleteDuration"` // 已完成学员的平均学习时长(秒)
+}
+
+// DepartmentCompletionStat 部门（用户所属组织）完成率统计
+type DepartmentCompletionStat struct {
+	GroupID        uint    `json:"groupId"`
+	Learners       int64   `json:"learners"` // 参与学习的人数
+	Records        int64   `json:"records"`  // 学习记录数（人 x 课程）
+	Completed      int64   `json:"completed"`
+	CompletionRate float64 `json:"completionRate"`
+}
+
+// ActiveLearnerPoint 某个时间段的活跃学员数
+type ActiveLearnerPoint struct {
+	Period         string `json:"period"` // 按天为当天日期，按周为当周周一日期
+	ActiveLearners int64  `json:"activeLearners"`
+}
+
+// ProgressBucket 未完成学员的进度分布（流失点）
+type ProgressBucket struct {
+	Bucket   string  `json:"bucket"` // 如 0-9、90-99、completed
+	Learners int64   `json:"learners"`
+	Percent  float64 `json:"percent"`
+}
+
+// AnalyticsService 学习数据统计服务
+type AnalyticsService struct{}
+
+// CourseCompletion 按课程统计学习人数、完成率和平均完成时长
+func (s *AnalyticsService) CourseCompletion(ctx context.Context, f AnalyticsFilter) ([]CourseCompletionStat, error) {
+	stats := []CourseCompletionStat{}
+	err := s.records(ctx, f).
+		Select("r.course_id AS course_id, c.title AS title, COUNT(*) AS learners, " +
+			"SUM(CASE WHEN r.is_completed THEN 1 ELSE 0 END) AS completed, " +
+			"COALESCE(AVG(r.progress), 0) AS avg_progress, " +
+			"COALESCE(AVG(CASE WHEN r.is_completed THEN r.duration END), 0) AS avg_complete_duration").
+		Group("r.course_id, c.title").
+		Order("r.course_id").
+		Scan(&stats).Error
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range stats {
+		stats[i].CompletionRate = percent(stats[i].Completed, stats[i].Learners)
+		stats[i].AvgProgress = round2(stats[i].AvgProgress)
+		stats[i].AvgCompleteDuration = round2(stats[i].AvgCompleteDuration)
+	}
+	return stats, nil
+}
+
+// DepartmentCompletion 按用户所属组织统计完成率
+func (s *AnalyticsService) DepartmentCompletion(ctx context.Context, f AnalyticsFilter) ([]DepartmentCompletionStat, error) {
+	stats := []DepartmentCompletionStat{}
+	err := s.records(ctx, f).
+		Joins("JOIN sys_user AS u ON u.id = r.user_id AND u.deleted_at IS NULL").
+		Select("u.group_id AS group_id, COUNT(DISTINCT r.user_id) AS learners, COUNT(*) AS records, " +
+			"SUM(CASE WHEN r.is_completed THEN 1 ELSE 0 END) AS completed").
+		Group("u.group_id").
+		Order("u.group_id").
+		Scan(&stats).Error
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range stats {
+		stats[i].CompletionRate = percent(stats[i].Completed, stats[i].Records)
+	}
+	return stats, nil
+}
+
+// ActiveLearners 按天或按周统计有学习事件的去重学员数
+// 各时间段在一条 SQL 中分组统计；启用日汇总时，按天统计的历史日期优先读取汇总表
+func (s *AnalyticsService) ActiveLearners(ctx context.Context, f AnalyticsFilter, granularity string) ([]ActiveLearnerPoint, error) {
+	if f.StartTime == nil || f.EndTime == nil || !f.EndTime.After(*f.StartTime) {
+		return nil, ErrInvalidAnalyticsRange
+	}
+
+	start := startOfDay(*f.StartTime)
+	step := func(t time.Time) time.Time { return t.AddDate(0, 0, 1) }
+	if granularity == GranularityWeek {
+		// 周一为一周的开始
+		start = start.AddDate(0, 0, -((int(start.Weekday()) + 6) % 7))
+		step = func(t time.Time) time.Time { return t.AddDate(0, 0, 7) }
+	} else if granularity != GranularityDay {
+		return nil, fmt.Errorf("%w: 不支持的统计粒度 %s", ErrInvalidAnalyticsRange, granularity)
+	}
+
+	var buckets []time.Time
+	for t := start; t.Before(*f.EndTime); t = step(t) {
+		buckets = append(buckets, t)
+		if len(buckets) > maxAnalyticsBuckets {
+			return nil, fmt.Errorf("%w: 时间段数量超过 %d", ErrInvalidAnalyticsRange, maxAnalyticsBuckets)
+		}
+	}
+
+	rollup := map[string]int64{}
+	if granularity == GranularityDay && config.AppConfig.AnalyticsRollup && (f.Scope == nil || f.Scope.All || f.CourseID > 0) {
+		var stats []models.LearningDailyStat
+		err := database.WithContext(ctx).Where("course_id = ? AND stat_date >= ? AND stat_date <= ?",
+			f.CourseID, start.Format(statDateLayout), f.EndTime.Format(statDateLayout)).
+			Find(&stats).Error
+		if err != nil {
+			return nil, err
+		}
+		for _, stat := range stats {
+			rollup[stat.StatDate] = stat.ActiveLearners
+		}
+	}
+
+	end := step(buckets[len(buckets)-1])
+	expr, args := periodExpr(database.DB.Dialector.Name(), granularity, start)
+	var rows []ActiveLearnerPoint
+	err := s.events(ctx, f).
+		Select(expr+" AS period, COUNT(DISTINCT user_id) AS active_learners", args...).
+		Where("occurred_at >= ? AND occurred_at < ?", start, end).
+		Group("period").
+		Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+	counts := make(map[string]int64, len(rows))
+	for _, row := range rows {
+		counts[row.Period] = row.ActiveLearners
+	}
+
+	// 没有学习事件的时间段补 0
+	today := startOfDay(time.Now())
+	points := make([]ActiveLearnerPoint, 0, len(buckets))
+	for _, from := range buckets {
+		period := from.Format(statDateLayout)
+		count, ok := rollup[period]
+		if !ok || !from.Before(today) {
+			count = counts[period]
+		}
+		points = append(points, ActiveLearnerPoint{Period: period, ActiveLearners: count})
+	}
+	return points, nil
+}
+
+// periodExpr 返回学习事件所属时间段的 SQL 表达式（格式同 statDateLayout，按周为当周周一）
+// MySQL 连接使用本地时区读写时间，直接取日期；SQLite 和 PostgreSQL 按统计起始时间的时区偏移换算为本地日期
+func periodExpr(dialect, granularity string, start time.Time) (string, []interface{}) {
+	_, offset := start.Zone()
+	week := granularity == GranularityWeek
+	switch dialect {
+	case database.DriverMySQL:
+		if week {
+			return "DATE_FORMAT(DATE_SUB(DATE(occurred_at), INTERVAL WEEKDAY(occurred_at) DAY), '%Y-%m-%d')", nil
+		}
+		return "DATE_FORMAT(occurred_at, '%Y-%m-%d')", nil
+	case database.DriverPostgres:
+		local := "(occurred_at AT TIME ZONE 'UTC' + make_interval(secs => ?))"
+		if week {
+			return "to_char(date_trunc('week', " + local + "), 'YYYY-MM-DD')", []interface{}{offset}
+		}
+		return "to_char(" + local + ", 'YYYY-MM-DD')", []interface{}{offset}
+	default:
+		modifier := fmt.Sprintf("%+d seconds", offset)
+		if week {
+			// weekday 0 移到本周日（当天为周日时不变），再减 6 天即为周一
+			return "date(occurred_at, ?, 'weekday 0', '-6 days')", []interface{}{modifier}
+		}
+		return "date(occurred_at, ?)", []interface{}{modifier}
+	}
+}
+
+// DropOff 统计学习记录的进度分布：未完成的按 10% 分段，已完成单独一段
+func (s *AnalyticsService) DropOff(ctx context.Context, f AnalyticsFilter) ([]ProgressBucket, error) {
+	bucketExpr := "CASE WHEN r.is_completed THEN 10"
+	for i := 9; i >= 1; i-- {
+		bucketExpr += fmt.Sprintf(" WHEN r.progress >= %d THEN %d", i*10, i)
+	}
+	bucketExpr += " ELSE 0 END"
+
+	var rows []struct {
+		Bucket   int
+		Learners int64
+	}
+	err := s.records(ctx, f).
+		Select(bucketExpr + " AS bucket, COUNT(*) AS learners").
+		Group("bucket").
+		Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+
+	counts := make([]int64, 11)
+	var total int64
+	for _, row := range rows {
+		if row.Bucket >= 0 && row.Bucket <= 10 {
+			counts[row.Bucket] = row.Learners
+			total += row.Learners
+		}
+	}
+
+	buckets := make([]ProgressBucket, 0, 11)
+	for i, count := range counts {
+		name := fmt.Sprintf("%d-%d", i*10, i*10+9)
+		if i == 10 {
+			name = "completed"
+		}
+		buckets = append(buckets, ProgressBucket{Bucket: name, Learners: count, Percent: percent(count, total)})
+	}
+	return buckets, nil
+}
+
+// Rankings 按完成率返回排名靠前和靠后的课程，学习人数少于 minLearners 的课程不参与排名
+func (s *AnalyticsService) Rankings(ctx context.Context, f AnalyticsFilter, limit int, minLearners int64) (top, bottom []CourseCompletionStat, err error) {
+	stats, err := s.CourseCompletion(ctx, f)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	ranked := make([]CourseCompletionStat, 0, len(stats))
+	for _, stat := range stats {
+		if stat.Learners >= minLearners {
+			ranked = append(ranked, stat)
+		}
+	}
+	sort.SliceStable(ranked, func(i, j int) bool {
+		if ranked[i].CompletionRate != ranked[j].CompletionRate {
+			return ranked[i].CompletionRate > ranked[j].CompletionRate
+		}
+		return ranked[i].Learners > ranked[j].Learners
+	})
+
+	if limit > len(ranked) {
+		limit = len(ranked)
+	}
+	top = append([]CourseCompletionStat{}, ranked[:limit]...)
+	bottom = make([]CourseCompletionStat, 0, limit)
+	for i := len(ranked) - 1; i >= len(ranked)-limit; i-- {
+		bottom = append(bottom, ranked[i])
+	}
+	return top, bottom, nil
+}
+
+// RollupDay 汇总指定日期的学习事件，覆盖当天已有的汇总结果，可重复执行
+func (s *AnalyticsService) RollupDay(ctx context.Context, day time.Time) error {
+	from := startOfDay(day)
+	to := from.AddDate(0, 0, 1)
+	statDate := from.Format(statDateLayout)
+
+	type dailyStat struct {
+		CourseID       uint
+		ActiveLearners int64
+		Events         int64
+		Completions    int64
+	}
+	const columns = "COUNT(DISTINCT user_id) AS active_learners, COUNT(*) AS events, " +
+		"COALESCE(SUM(CASE WHEN verb = ? THEN 1 ELSE 0 END), 0) AS completions"
+	dayEvents := func() *gorm.DB {
+		return database.WithContext(ctx).Model(&models.LearningEvent{}).Where("occurred_at >= ? AND occurred_at < ?", from, to)
+	}
+
+	var total dailyStat
+	if err := dayEvents().Select(columns, models.VerbCompleted).Scan(&total).Error; err != nil {
+		return err
+	}
+	var perCourse []dailyStat
+	err := dayEvents().
+		Select("course_id, "+columns, models.VerbCompleted).
+		Group("course_id").
+		Scan(&perCourse).Error
+	if err != nil {
+		return err
+	}
+
+	return database.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("stat_date = ?", statDate).Delete(&models.LearningDailyStat{}).Error; err != nil {
+			return err
+		}
+		if total.Events == 0 {
+			return nil
+		}
+
+		rows := []models.LearningDailyStat{{
+			StatDate:       statDate,
+			ActiveLearners: total.ActiveLearners,
+			Events:         total.Events,
+			Completions:    total.Completions,
+		}}
+		for _, stat := range perCourse {
+			rows = append(rows, models.LearningDailyStat{
+				StatDate:       statDate,
+				CourseID:       stat.CourseID,
+				ActiveLearners: stat.ActiveLearners,
+				Events:         stat.Events,
+				Completions:    stat.Completions,
+			})
+		}
+		return tx.Create(&rows).Error
+	})
+}
+
+// RollupPending 汇总从最近一次汇总日期（重新计算以包含迟到的事件）到昨天的数据
+// 首次运行从最早的学习事件开始，返回汇总的天数
+func (s *AnalyticsService) RollupPending(ctx context.Context, now time.Time) (int, error) {
+	yesterday := startOfDay(now).AddDate(0, 0, -1)
+
+	var start time.Time
+	var latest models.LearningDailyStat
+	err := database.WithContext(ctx).Order("stat_date DESC").First(&latest).Error
+	switch {
+	case err == nil:
+		start, err = time.ParseInLocation(statDateLayout, latest.StatDate, time.Local)
+		if err != nil {
+			return 0, err
+		}
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		var first models.LearningEvent
+		err := database.WithContext(ctx).Order("occurred_at ASC").First(&first).Error
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return 0, nil
+		}
+		if err != nil {
+			return 0, err
+		}
+		start = startOfDay(first.OccurredAt)
+	default:
+		return 0, err
+	}
+
+	days := 0
+	for day := start; !day.After(yesterday); day = day.AddDate(0, 0, 1) {
+		if err := s.RollupDay(ctx, day); err != nil {
+			return days, fmt.Errorf("汇总 %s 失败: %w", day.Format(statDateLayout), err)
+		}
+		days++
+	}
+	return days, nil
+}
+
+// StartRollup 在后台定期执行日汇总，ctx 取消后退出；返回的通道在任务退出后关闭
+func (s *AnalyticsService) StartRollup(ctx context.Context, interval time.Duration) <-chan struct{} {
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		run := func(now time.Time) {
+			if days, err := s.RollupPending(ctx, now); err != nil {
+				slog.Error("学习数据日汇总失败", "error", err)
+			} else if days > 0 {
+				slog.Info("学习数据日汇总完成", "days", days)
+			}
+		}
+
+		run(time.Now())
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case now := <-ticker.C:
+				run(now)
+			}
+		}
+	}()
+	return done
+}
+
+// records 学习记录基础查询（别名 r），已关联未删除的课程（别名 c）
+func (s *AnalyticsService) records(ctx context.Context, f AnalyticsFilter) *gorm.DB {
+	query := database.WithContext(ctx).Table("sys_course_record AS r").
+		Joins("JOIN sys_course AS c ON c.id = r.course_id AND c.deleted_at IS NULL").
+		Where("r.deleted_at IS NULL")
+
+	if f.Scope != nil {
+		query = query.Scopes(f.Scope.Courses("r.course_id"))
+	}
+	if f.CourseID > 0 {
+		query = query.Where("r.course_id = ?", f.CourseID)
+	}
+	if f.StartTime != nil {
+		query = query.Where("r.created_at >= ?", *f.StartTime)
+	}
+	if f.EndTime != nil {
+		query = query.Where("r.created_at <= ?", *f.EndTime)
+	}
+	return query
+}
+
+// events 学习事件基础查询，只按课程范围筛选，时间条件由调用方添加
+func (s *AnalyticsService) events(ctx context.Context, f AnalyticsFilter) *gorm.DB {
+	query := database.WithContext(ctx).Model(&models.LearningEvent{})
+	if f.Scope != nil {
+		query = query.Scopes(f.Scope.Courses("course_id"))
+	}
+	if f.CourseID > 0 {
+		query = query.Where("course_id = ?", f.CourseID)
+	}
+	return query
+}
+
+// startOfDay 返回本地时区当天零点
+func startOfDay(t time.Time) time.Time {
+	y, m, d := t.In(time.Local).Date()
+	return time.Date(y, m, d, 0, 0, 0, 0, time.Local)
+}
+
+// percent 计算百分比，保留两位小数
+func percent(part, total int64) float64 {
+	if total == 0 {
+		return 0
+	}
+	return round2(float64(part) * 100 / float64(total))
+}
+
+func round2(v float64) float64 {
+	return math.Round(v*100) / 100
+}
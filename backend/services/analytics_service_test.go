@@ -0,0 +1,98 @@
+package services
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"learn-hub-backend/database"
+	"learn-hub-backend/models"
+	"learn-hub-backend/testutil"
+
+	"gorm.io/gorm"
+)
+
+func TestActiveLearnersGroupsByPeriod(t *testing.T) {
+	previous := time.Local
+	time.Local = time.FixedZone("UTC+8", 8*3600)
+	t.Cleanup(func() { time.Local = previous })
+
+	db := testutil.SetupDB(t)
+	ctx := context.Background()
+	var service AnalyticsService
+
+	// 统计学习事件表的查询次数，每次统计只应执行一条
+	queries := 0
+	count := func(db *gorm.DB) {
+		if db.Statement.Table == "sys_learning_event" {
+			queries++
+		}
+	}
+	if err := db.Callback().Row().After("gorm:row").Register("test:count_row", count); err != nil {
+		t.Fatal(err)
+	}
+	if err := db.Callback().Query().After("gorm:query").Register("test:count_query", count); err != nil {
+		t.Fatal(err)
+	}
+
+	admin := testutil.CreateUser(t, "teacher")
+	course := testutil.CreateCourse(t, "go-basics", admin.ID)
+	local := func(day, hour, min int) time.Time { return time.Date(2026, 10, day, hour, min, 0, 0, time.Local) }
+	events := []struct {
+		userID uint
+		at     time.Time
+	}{
+		{1, local(12, 9, 0)},
+		{1, local(12, 10, 0)},
+		{2, local(12, 23, 30)},
+		// UTC 10-12 16:30 为本地时间 10-13 00:30
+		{2, time.Date(2026, 10, 12, 16, 30, 0, 0, time.UTC)},
+		{3, local(19, 8, 0)},
+	}
+	for _, e := range events {
+		event := models.LearningEvent{UserID: e.userID, CourseID: course.ID, Verb: models.VerbProgressed, OccurredAt: e.at}
+		if err := database.DB.Create(&event).Error; err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	start, end := local(12, 0, 0), local(20, 0, 0)
+	filter := AnalyticsFilter{StartTime: &start, EndTime: &end}
+
+	days, err := service.ActiveLearners(ctx, filter, GranularityDay)
+	if err != nil {
+		t.Fatal(err)
+	}
+	wantDays := []ActiveLearnerPoint{
+		{"2026-10-12", 2}, {"2026-10-13", 1}, {"2026-10-14", 0}, {"2026-10-15", 0},
+		{"2026-10-16", 0}, {"2026-10-17", 0}, {"2026-10-18", 0}, {"2026-10-19", 1},
+	}
+	assertPoints(t, "按天", days, wantDays)
+	if queries != 1 {
+		t.Fatalf("按天统计查询学习事件 %d 次，期望 1 次", queries)
+	}
+
+	// 周三开始时从当周周一统计
+	queries = 0
+	start = local(14, 0, 0)
+	weeks, err := service.ActiveLearners(ctx, filter, GranularityWeek)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertPoints(t, "按周", weeks, []ActiveLearnerPoint{{"2026-10-12", 2}, {"2026-10-19", 1}})
+	if queries != 1 {
+		t.Fatalf("按周统计查询学习事件 %d 次，期望 1 次", queries)
+	}
+}
+
+func assertPoints(t *testing.T, name string, got, want []ActiveLearnerPoint) {
+	t.Helper()
+	if len(got) != len(want) {
+		t.Fatalf("%s统计返回 %v，期望 %v", name, got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("%s统计返回 %v，期望 %v", name, got, want)
+		}
+	}
+}
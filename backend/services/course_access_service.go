@@ -0,0 +1,135 @@
+package services
+
+import (
+	"context"
+	"errors"
+
+	"learn-hub-backend/database"
+	"learn-hub-backend/models"
+
+	"gorm.io/gorm"
+)
+
+var (
+	// ErrNoCourseAccess 没有课程管理权限
+	ErrNoCourseAccess = errors.New("没有课程管理权限")
+	// ErrNotCourseOwner 不是课程负责人
+	ErrNotCourseOwner = errors.New("只有课程负责人或管理员可以修改讲师")
+)
+
+// CourseScope 当前用户可管理的课程范围
+type CourseScope struct {
+	UserID uint
+	All    bool // 管理员可管理全部课程
+}
+
+// Courses 返回限定在可管理课程内的查询条件；column 为课程ID列名，scope 为 nil 时不限制
+func (s *CourseScope) Courses(column string) func(*gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		if s == nil || s.All {
+			return db
+		}
+		// 子查询沿用外层查询的 context
+		sub := db.Session(&gorm.Session{NewDB: true})
+		coInstructed := sub.Model(&models.CourseInstructor{}).Select("course_id").Where("user_id = ?", s.UserID)
+		owned := sub.Model(&models.Course{}).Select("id").Where("owner_id = ?", s.UserID)
+		return db.Where("("+column+" IN (?) OR "+column+" IN (?))", owned, coInstructed)
+	}
+}
+
+// CourseAccessService 课程归属和讲师权限服务
+type CourseAccessService struct{}
+
+// Resolve 根据用户角色获取可管理的课程范围：管理员为全部，讲师为负责或协作的课程，其他用户返回 ErrNoCourseAccess
+func (s *CourseAccessService) Resolve(ctx context.Context, userID uint) (*CourseScope, error) {
+	isAdmin, err := userHasRole(database.WithContext(ctx), userID, models.RoleCodeAdmin)
+	if err != nil {
+		return nil, err
+	}
+	if isAdmin {
+		return &CourseScope{UserID: userID, All: true}, nil
+	}
+
+	isInstructor, err := userHasRole(database.WithContext(ctx), userID, models.RoleCodeInstructor)
+	if err != nil {
+		return nil, err
+	}
+	if !isInstructor {
+		return nil, ErrNoCourseAccess
+	}
+	return &CourseScope{UserID: userID}, nil
+}
+
+// CanManage 判断课程是否在可管理范围内，课程不存在时返回 gorm.ErrRecordNotFound
+func (s *CourseAccessService) CanManage(ctx context.Context, scope *CourseScope, courseID uint) (bool, error) {
+	var course models.Course
+	if err := database.WithContext(ctx).Select("id", "owner_id").First(&course, courseID).Error; err != nil {
+		return false, err
+	}
+	if scope.All || course.OwnerID == scope.UserID {
+		return true, nil
+	}
+
+	var count int64
+	err := database.WithContext(ctx).Model(&models.CourseInstructor{}).
+		Where("course_id = ? AND user_id = ?", courseID, scope.UserID).
+		Count(&count).Error
+	return count > 0, err
+}
+
+// Instructors 获取课程协作讲师用户ID
+func (s *CourseAccessService) Instructors(ctx context.Context, courseID uint) ([]uint, error) {
+	userIDs := []uint{}
+	err := database.WithContext(ctx).Model(&models.CourseInstructor{}).Where("course_id = ?", courseID).Pluck("user_id", &userIDs).Error
+	return userIDs, err
+}
+
+// SetInstructors 修改课程负责人和协作讲师，只有管理员或当前负责人可以操作
+// ownerID 为 nil 时不修改负责人；instructorIDs 整体替换协作讲师
+func (s *CourseAccessService) SetInstructors(ctx context.Context, scope *CourseScope, courseID uint, ownerID *uint, instructorIDs []uint) (*models.Course, error) {
+	var course *models.Course
+	err := database.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var err error
+		if course, err = lockCourse(tx, courseID); err != nil {
+			return err
+		}
+		if !scope.All && course.OwnerID != scope.UserID {
+			return ErrNotCourseOwner
+		}
+
+		if ownerID != nil && *ownerID != course.OwnerID {
+			course.OwnerID = *ownerID
+			if err := tx.Model(course).Update("owner_id", course.OwnerID).Error; err != nil {
+				return err
+			}
+		}
+
+		if err := tx.Where("course_id = ?", courseID).Delete(&models.CourseInstructor{}).Error; err != nil {
+			return err
+		}
+		seen := make(map[uint]bool, len(instructorIDs))
+		for _, userID := range instructorIDs {
+			if userID == 0 || userID == course.OwnerID || seen[userID] {
+				continue
+			}
+			seen[userID] = true
+			instructor := models.CourseInstructor{CourseID: courseID, UserID: userID}
+			if err := tx.Create(&instructor).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	return course, err
+}
+
+// userHasRole 用户是否拥有任一启用的角色
+func userHasRole(db *gorm.DB, userID uint, codes ...string) (bool, error) {
+	var count int64
+	err := db.Table("sys_user_role").
+		Joins("JOIN sys_role ON sys_role.id = sys_user_role.role_id").
+		Where("sys_user_role.user_id = ? AND sys_role.code IN ? AND sys_role.status = ? AND sys_role.deleted_at IS NULL",
+			userID, codes, 1).
+		Count(&count).Error
+	return count > 0, err
+}
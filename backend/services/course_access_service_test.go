@@ -0,0 +1,80 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"learn-hub-backend/database"
+	"learn-hub-backend/models"
+	"learn-hub-backend/testutil"
+)
+
+// 讲师只能管理自己负责或协作的课程，管理员可管理全部课程，其他用户没有课程管理权限
+func TestCourseScopeByRole(t *testing.T) {
+	testutil.SetupDB(t)
+	ctx := context.Background()
+	var service CourseAccessService
+
+	alice := testutil.CreateUser(t, "alice", models.RoleCodeInstructor)
+	bob := testutil.CreateUser(t, "bob", models.RoleCodeInstructor)
+	learner := testutil.CreateUser(t, "learner")
+	owned := testutil.CreateCourse(t, "owned", alice.ID)
+	shared := testutil.CreateCourse(t, "shared", bob.ID)
+	other := testutil.CreateCourse(t, "other", bob.ID)
+	if err := database.DB.Create(&models.CourseInstructor{CourseID: shared.ID, UserID: alice.ID}).Error; err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := service.Resolve(ctx, learner.ID); !errors.Is(err, ErrNoCourseAccess) {
+		t.Fatalf("普通用户解析课程范围返回 %v，期望 ErrNoCourseAccess", err)
+	}
+
+	scope, err := service.Resolve(ctx, alice.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var ids []uint
+	if err := database.DB.Model(&models.Course{}).Scopes(scope.Courses("id")).Order("id").Pluck("id", &ids).Error; err != nil {
+		t.Fatal(err)
+	}
+	if len(ids) != 2 || ids[0] != owned.ID || ids[1] != shared.ID {
+		t.Fatalf("讲师可管理的课程为 %v，期望 [%d %d]", ids, owned.ID, shared.ID)
+	}
+	for id, want := range map[uint]bool{owned.ID: true, shared.ID: true, other.ID: false} {
+		ok, err := service.CanManage(ctx, scope, id)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if ok != want {
+			t.Errorf("讲师管理课程 %d 返回 %v，期望 %v", id, ok, want)
+		}
+	}
+
+	// 协作讲师不能修改讲师，负责人可以
+	if _, err := service.SetInstructors(ctx, scope, shared.ID, nil, nil); !errors.Is(err, ErrNotCourseOwner) {
+		t.Fatalf("协作讲师修改讲师返回 %v，期望 ErrNotCourseOwner", err)
+	}
+	if _, err := service.SetInstructors(ctx, scope, owned.ID, nil, []uint{bob.ID, bob.ID, alice.ID}); err != nil {
+		t.Fatal(err)
+	}
+	instructors, err := service.Instructors(ctx, owned.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(instructors) != 1 || instructors[0] != bob.ID {
+		t.Fatalf("课程协作讲师为 %v，期望只有 bob", instructors)
+	}
+
+	var admin models.User
+	if err := database.DB.Where("username = ?", "admin").First(&admin).Error; err != nil {
+		t.Fatal(err)
+	}
+	adminScope, err := service.Resolve(ctx, admin.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ok, err := service.CanManage(ctx, adminScope, other.ID); err != nil || !ok {
+		t.Fatalf("管理员管理课程返回 %v, %v", ok, err)
+	}
+}
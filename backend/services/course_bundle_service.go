@@ -0,0 +1,370 @@
+package services
+
+import (
+	"archive/zip"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"learn-hub-backend/config"
+	"learn-hub-backend/database"
+	"learn-hub-backend/models"
+	"learn-hub-backend/utils"
+
+	"gorm.io/gorm"
+)
+
+// 课程包格式
+const (
+	CourseBundleFormat  = "learn-hub-course"
+	CourseBundleVersion = 1
+	courseBundleFile    = "manifest.json"
+)
+
+// ErrInvalidCourseBundle 课程包不合法
+var ErrInvalidCourseBundle = errors.New("课程包不合法")
+
+// CourseBundleService 课程导入导出服务
+type CourseBundleService struct{}
+
+// CourseBundleManifest 课程包清单（manifest.json）
+type CourseBundleManifest struct {
+	Format         string                `json:"format"`
+	Version        int                   `json:"version"`
+	ExportedAt     string                `json:"exportedAt"`
+	Course         CourseBundleCourse    `json:"course"`
+	ScormPackages  []CourseBundleScorm   `json:"scormPackages,omitempty"`
+	XAPIActivities []CourseBundleXAPIRef `json:"xapiActivities,omitempty"`
+}
+
+// CourseBundleCourse 课程元数据和内容
+type CourseBundleCourse struct {
+	ID          uint   `json:"id"`
+	Title       string `json:"title"`
+	Description string `json:"description"`
+	CoverImage  string `json:"coverImage"`
+	ContentType int    `json:"contentType"`
+	VideoURL    string `json:"videoUrl"`
+	TextContent string `json:"textContent"`
+	Duration    int    `json:"duration"`
+	SortOrder   int    `json:"sortOrder"`
+}
+
+// CourseBundleScorm 课件包元数据，文件位于 Path 目录下
+type CourseBundleScorm struct {
+	ID         uint   `json:"id"`
+	Version    string `json:"version"`
+	Identifier string `json:"identifier"`
+	Title      string `json:"title"`
+	EntryHref  string `json:"entryHref"`
+	Path       string `json:"path"`
+}
+
+// CourseBundleXAPIRef xAPI 活动映射
+type CourseBundleXAPIRef struct {
+	ActivityID string `json:"activityId"`
+}
+
+// CourseImportResult 导入结果
+type CourseImportResult struct {
+	CourseID  uint                 `json:"courseId"`
+	Title     string               `json:"title"`
+	IDMap     map[string]IDMapping `json:"idMap"`
+	Conflicts []string             `json:"conflicts"`
+}
+
+// IDMapping 原ID到新ID的映射
+type IDMapping map[uint]uint
+
+// Export 将课程写入 zip 课程包
+func (s *CourseBundleService) Export(ctx context.Context, courseID uint, w io.Writer) error {
+	var course models.Course
+	if err := database.WithContext(ctx).First(&course, courseID).Error; err != nil {
+		return err
+	}
+
+	manifest := CourseBundleManifest{
+		Format:     CourseBundleFormat,
+		Version:    CourseBundleVersion,
+		ExportedAt: time.Now().Format(time.RFC3339),
+		Course: CourseBundleCourse{
+			ID:          course.ID,
+			Title:       course.Title,
+			Description: course.Description,
+			CoverImage:  course.CoverImage,
+			ContentType: course.ContentType,
+			VideoURL:    course.VideoURL,
+			TextContent: course.TextContent,
+			Duration:    course.Duration,
+			SortOrder:   course.SortOrder,
+		},
+	}
+
+	var packages []models.ScormPackage
+	if err := database.WithContext(ctx).Where("course_id = ?", courseID).Order("id ASC").Find(&packages).Error; err != nil {
+		return err
+	}
+	var activities []models.XAPIActivity
+	if err := database.WithContext(ctx).Where("course_id = ?", courseID).Order("id ASC").Find(&activities).Error; err != nil {
+		return err
+	}
+	for _, activity := range activities {
+		manifest.XAPIActivities = append(manifest.XAPIActivities, CourseBundleXAPIRef{ActivityID: activity.ActivityID})
+	}
+
+	zw := zip.NewWriter(w)
+
+	// 附带课件包文件
+	for _, pkg := range packages {
+		dir := fmt.Sprintf("media/scorm/%d", pkg.ID)
+		manifest.ScormPackages = append(manifest.ScormPackages, CourseBundleScorm{
+			ID:         pkg.ID,
+			Version:    pkg.Version,
+			Identifier: pkg.Identifier,
+			Title:      pkg.Title,
+			EntryHref:  pkg.EntryHref,
+			Path:       dir,
+		})
+
+		base := filepath.Join(config.AppConfig.UploadDir, filepath.FromSlash(pkg.StorageDir))
+		if err := addDirToZip(zw, base, dir); err != nil {
+			return err
+		}
+	}
+
+	data, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return err
+	}
+	fw, err := zw.Create(courseBundleFile)
+	if err != nil {
+		return err
+	}
+	if _, err := fw.Write(data); err != nil {
+		return err
+	}
+
+	return zw.Close()
+}
+
+// Import 导入课程包，课程以草稿状态创建、负责人为 ownerID，冲突项跳过并在结果中报告
+func (s *CourseBundleService) Import(ctx context.Context, r io.ReaderAt, size int64, ownerID uint) (*CourseImportResult, error) {
+	zr, err := zip.NewReader(r, size)
+	if err != nil {
+		return nil, fmt.Errorf("%w: 无法读取 zip 文件", ErrInvalidCourseBundle)
+	}
+
+	manifest, err := readCourseBundleManifest(zr)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &CourseImportResult{
+		IDMap: map[string]IDMapping{
+			"course":        {},
+			"scormPackages": {},
+		},
+		Conflicts: []string{},
+	}
+
+	// 同名课程只提示，不阻止导入
+	var sameTitle int64
+	database.WithContext(ctx).Model(&models.Course{}).Where("title = ?", manifest.Course.Title).Count(&sameTitle)
+	if sameTitle > 0 {
+		result.Conflicts = append(result.Conflicts, fmt.Sprintf("已存在同名课程: %s", manifest.Course.Title))
+	}
+
+	// 先解压课件包文件，数据库写入失败时清理
+	var extractedDirs []string
+	cleanup := func() {
+		for _, dir := range extractedDirs {
+			os.RemoveAll(dir)
+		}
+	}
+	storageDirs := make(map[uint]string, len(manifest.ScormPackages))
+	fileSizes := make(map[uint]int64, len(manifest.ScormPackages))
+	for _, pkg := range manifest.ScormPackages {
+		storageDir := path.Join("scorm", strings.ReplaceAll(utils.NewUUID(), "-", ""))
+		targetDir := filepath.Join(config.AppConfig.UploadDir, filepath.FromSlash(storageDir))
+		extractedDirs = append(extractedDirs, targetDir)
+		written, err := extractZipPrefix(zr, pkg.Path, targetDir)
+		if err != nil {
+			cleanup()
+			return nil, err
+		}
+		storageDirs[pkg.ID] = storageDir
+		fileSizes[pkg.ID] = written
+	}
+
+	err = database.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		course := models.Course{
+			Title:       manifest.Course.Title,
+			Description: manifest.Course.Description,
+			CoverImage:  manifest.Course.CoverImage,
+			ContentType: manifest.Course.ContentType,
+			VideoURL:    manifest.Course.VideoURL,
+			TextContent: manifest.Course.TextContent,
+			Duration:    manifest.Course.Duration,
+			Status:      models.CourseStatusDraft,
+			SortOrder:   manifest.Course.SortOrder,
+			OwnerID:     ownerID,
+		}
+		if err := tx.Create(&course).Error; err != nil {
+			return err
+		}
+		result.CourseID = course.ID
+		result.Title = course.Title
+		result.IDMap["course"][manifest.Course.ID] = course.ID
+
+		for _, bundled := range manifest.ScormPackages {
+			pkg := models.ScormPackage{
+				CourseID:   course.ID,
+				Version:    bundled.Version,
+				Identifier: bundled.Identifier,
+				Title:      bundled.Title,
+				EntryHref:  bundled.EntryHref,
+				StorageDir: storageDirs[bundled.ID],
+				FileSize:   fileSizes[bundled.ID],
+			}
+			if err := tx.Create(&pkg).Error; err != nil {
+				return err
+			}
+			result.IDMap["scormPackages"][bundled.ID] = pkg.ID
+		}
+
+		// 活动IRI全局唯一，已映射到其他课程的跳过
+		for _, ref := range manifest.XAPIActivities {
+			var existing models.XAPIActivity
+			if tx.Where("activity_id = ?", ref.ActivityID).First(&existing).Error == nil {
+				result.Conflicts = append(result.Conflicts,
+					fmt.Sprintf("xAPI 活动 %s 已映射到课程 %d，已跳过", ref.ActivityID, existing.CourseID))
+				continue
+			}
+			if err := tx.Create(&models.XAPIActivity{ActivityID: ref.ActivityID, CourseID: course.ID}).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		cleanup()
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// readCourseBundleManifest 读取并校验课程包清单
+func readCourseBundleManifest(zr *zip.Reader) (*CourseBundleManifest, error) {
+	files := make(map[string]bool, len(zr.File))
+	var manifestFile *zip.File
+	for _, f := range zr.File {
+		files[f.Name] = true
+		if f.Name == courseBundleFile {
+			manifestFile = f
+		}
+	}
+	if manifestFile == nil {
+		return nil, fmt.Errorf("%w: 缺少 %s", ErrInvalidCourseBundle, courseBundleFile)
+	}
+
+	rc, err := manifestFile.Open()
+	if err != nil {
+		return nil, fmt.Errorf("%w: 无法读取 %s", ErrInvalidCourseBundle, courseBundleFile)
+	}
+	defer rc.Close()
+
+	var manifest CourseBundleManifest
+	if err := json.NewDecoder(io.LimitReader(rc, 50<<20)).Decode(&manifest); err != nil {
+		return nil, fmt.Errorf("%w: %s 解析失败: %v", ErrInvalidCourseBundle, courseBundleFile, err)
+	}
+
+	if manifest.Format != CourseBundleFormat {
+		return nil, fmt.Errorf("%w: 不支持的格式 %q", ErrInvalidCourseBundle, manifest.Format)
+	}
+	if manifest.Version < 1 || manifest.Version > CourseBundleVersion {
+		return nil, fmt.Errorf("%w: 不支持的版本 %d", ErrInvalidCourseBundle, manifest.Version)
+	}
+	if strings.TrimSpace(manifest.Course.Title) == "" {
+		return nil, fmt.Errorf("%w: 课程标题不能为空", ErrInvalidCourseBundle)
+	}
+	if manifest.Course.ContentType < models.ContentTypeVideo || manifest.Course.ContentType > models.ContentTypeSCORM {
+		return nil, fmt.Errorf("%w: 内容类型错误 %d", ErrInvalidCourseBundle, manifest.Course.ContentType)
+	}
+	for _, pkg := range manifest.ScormPackages {
+		if pkg.Version != models.ScormVersion12 && pkg.Version != models.ScormVersion2004 {
+			return nil, fmt.Errorf("%w: 课件包 %d 版本错误 %q", ErrInvalidCourseBundle, pkg.ID, pkg.Version)
+		}
+		entry := path.Join(pkg.Path, pkg.EntryHref)
+		if !strings.HasPrefix(pkg.Path, "media/") || !files[entry] {
+			return nil, fmt.Errorf("%w: 课件包 %d 缺少启动文件 %s", ErrInvalidCourseBundle, pkg.ID, entry)
+		}
+	}
+
+	return &manifest, nil
+}
+
+// addDirToZip 将本地目录递归写入 zip 的 prefix 目录下
+func addDirToZip(zw *zip.Writer, base, prefix string) error {
+	return filepath.WalkDir(base, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			return nil
+		}
+
+		rel, err := filepath.Rel(base, p)
+		if err != nil {
+			return err
+		}
+		fw, err := zw.Create(path.Join(prefix, filepath.ToSlash(rel)))
+		if err != nil {
+			return err
+		}
+
+		f, err := os.Open(p)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+
+		_, err = io.Copy(fw, f)
+		return err
+	})
+}
+
+// extractZipPrefix 将 zip 中 prefix 目录下的文件解压到目标目录
+func extractZipPrefix(zr *zip.Reader, prefix, targetDir string) (int64, error) {
+	prefix = strings.TrimSuffix(prefix, "/") + "/"
+	var total int64
+	for _, f := range zr.File {
+		if !strings.HasPrefix(f.Name, prefix) || f.FileInfo().IsDir() {
+			continue
+		}
+
+		name := path.Clean("/" + strings.TrimPrefix(f.Name, prefix))
+		if name == "/" {
+			continue
+		}
+		dest := filepath.Join(targetDir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(dest), 0o755); err != nil {
+			return 0, err
+		}
+
+		written, err := extractFile(f, dest, maxScormExtractSize-total)
+		if err != nil {
+			return 0, err
+		}
+		total += written
+	}
+	return total, nil
+}
@@ -0,0 +1,121 @@
+package services
+
+import (
+	"context"
+	"time"
+
+	"learn-hub-backend/database"
+	"learn-hub-backend/models"
+)
+
+// CourseRecordService 学习记录服务（供 xAPI、SCORM 等外部内容标准回写学习结果）
+type CourseRecordService struct{}
+
+// MarkCompleted 将用户的课程学习记录标记为完成，completedAt 为完成时间
+func (s *CourseRecordService) MarkCompleted(ctx context.Context, userID, courseID uint, completedAt time.Time) error {
+	var record models.CourseRecord
+	err := database.WithContext(ctx).Where("user_id = ? AND course_id = ?", userID, courseID).First(&record).Error
+
+	now := time.Now()
+	wasCompleted := err == nil && record.IsCompleted
+	if err != nil {
+		record = models.CourseRecord{
+			UserID:   userID,
+			CourseID: courseID,
+		}
+	}
+
+	record.Progress = 100
+	record.IsCompleted = true
+	if record.CompletedAt == nil {
+		record.CompletedAt = &completedAt
+		record.CompletedRevision = (&CourseRevisionService{}).CurrentRevision(ctx, courseID)
+	}
+	record.LastStudyAt = now
+
+	if err := database.WithContext(ctx).Save(&record).Error; err != nil {
+		return err
+	}
+
+	if wasCompleted {
+		return nil
+	}
+	return (&LearningEventService{}).Record(ctx, &models.LearningEvent{
+		UserID:     userID,
+		CourseID:   courseID,
+		Verb:       models.VerbCompleted,
+		Progress:   record.Progress,
+		Duration:   record.Duration,
+		OccurredAt: completedAt,
+	})
+}
+
+// RuntimeResult 课件运行时上报的学习结果
+type RuntimeResult struct {
+	Progress  *int     // 学习进度(0-100)，nil 表示未上报
+	Completed bool     // 是否已完成
+	Score     *float64 // 成绩(0-100)，nil 表示未上报
+	Duration  int      // 本次新增学习时长(秒)
+}
+
+// ApplyRuntimeResult 将课件运行时上报的结果合并到学习记录，进度只增不减
+func (s *CourseRecordService) ApplyRuntimeResult(ctx context.Context, userID, courseID uint, result RuntimeResult) (*models.CourseRecord, error) {
+	var record models.CourseRecord
+	err := database.WithContext(ctx).Where("user_id = ? AND course_id = ?", userID, courseID).First(&record).Error
+
+	now := time.Now()
+	isNew := err != nil
+	wasCompleted := !isNew && record.IsCompleted
+	if isNew {
+		record = models.CourseRecord{
+			UserID:   userID,
+			CourseID: courseID,
+		}
+	}
+
+	if result.Progress != nil && *result.Progress > record.Progress {
+		record.Progress = *result.Progress
+	}
+	if result.Score != nil {
+		record.Score = result.Score
+	}
+	if result.Duration > 0 {
+		record.Duration += result.Duration
+	}
+	if result.Completed {
+		record.Progress = 100
+		record.IsCompleted = true
+		if record.CompletedAt == nil {
+			record.CompletedAt = &now
+			record.CompletedRevision = (&CourseRevisionService{}).CurrentRevision(ctx, courseID)
+		}
+	}
+	record.LastStudyAt = now
+
+	if err := database.WithContext(ctx).Save(&record).Error; err != nil {
+		return nil, err
+	}
+
+	eventService := &LearningEventService{}
+	verbs := []string{models.VerbProgressed}
+	if isNew {
+		verbs = append([]string{models.VerbStarted}, verbs...)
+	}
+	if record.IsCompleted && !wasCompleted {
+		verbs = append(verbs, models.VerbCompleted)
+	}
+	for _, verb := range verbs {
+		event := models.LearningEvent{
+			UserID:   userID,
+			CourseID: courseID,
+			Verb:     verb,
+			Progress: record.Progress,
+			Duration: record.Duration,
+		}
+		if err := eventService.Record(ctx, &event); err != nil {
+			return &record, err
+		}
+	}
+
+	return &record, nil
+}
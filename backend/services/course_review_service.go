@@ -0,0 +1,390 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"learn-hub-backend/database"
+	"learn-hub-backend/models"
+
+	"gorm.io/gorm"
+)
+
+var (
+	// ErrInvalidCourseTransition 当前状态不允许该操作
+	ErrInvalidCourseTransition = errors.New("当前课程状态不允许该操作")
+	// ErrNotCourseReviewer 不是课程的审核人
+	ErrNotCourseReviewer = errors.New("不是该课程的审核人")
+	// ErrReviewCommentRequired 驳回时缺少审核意见
+	ErrReviewCommentRequired = errors.New("驳回时必须填写审核意见")
+)
+
+// courseTransitionRule 状态流转规则
+type courseTransitionRule struct {
+	from []int
+	to   int
+}
+
+// courseTransitionRules 课程状态机：操作 -> 允许的原状态和目标状态
+var courseTransitionRules = map[string]courseTransitionRule{
+	models.CourseActionSubmit: {
+		from: []int{models.CourseStatusDraft, models.CourseStatusUnpublished, models.CourseStatusRejected},
+		to:   models.CourseStatusPending,
+	},
+	models.CourseActionWithdraw: {
+		from: []int{models.CourseStatusPending},
+		to:   models.CourseStatusDraft,
+	},
+	// 审核通过后进入待发布，没有未到期的定时发布时间时紧接着执行 publish
+	models.CourseActionApprove: {
+		from: []int{models.CourseStatusPending},
+		to:   models.CourseStatusApproved,
+	},
+	models.CourseActionReject: {
+		from: []int{models.CourseStatusPending},
+		to:   models.CourseStatusRejected,
+	},
+	models.CourseActionPublish: {
+		from: []int{models.CourseStatusApproved},
+		to:   models.CourseStatusPublished,
+	},
+	models.CourseActionUnpublish: {
+		from: []int{models.CourseStatusPublished, models.CourseStatusApproved},
+		to:   models.CourseStatusUnpublished,
+	},
+}
+
+// CourseReviewService 课程发布审核服务
+type CourseReviewService struct {
+	revisionService CourseRevisionService
+}
+
+// Submit 提交课程审核；reviewerIDs 不为 nil 时替换课程的指定审核人（空数组表示不指定），指定的审核人必须有审核员或管理员角色
+func (s *CourseReviewService) Submit(ctx context.Context, courseID, userID uint, comment string, reviewerIDs []uint) (*models.Course, error) {
+	var course *models.Course
+	err := database.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var err error
+		if course, err = lockCourse(tx, courseID); err != nil {
+			return err
+		}
+		if err := ValidateCourseContent(models.ContentOf(course)); err != nil {
+			return err
+		}
+
+		if reviewerIDs != nil {
+			if err := tx.Where("course_id = ?", courseID).Delete(&models.CourseReviewer{}).Error; err != nil {
+				return err
+			}
+			for _, reviewerID := range reviewerIDs {
+				if reviewerID == userID {
+					return fmt.Errorf("%w: 不能指定自己为审核人", ErrNotCourseReviewer)
+				}
+				isReviewer, err := userHasRole(tx, reviewerID, models.RoleCodeAdmin, models.RoleCodeCourseReviewer)
+				if err != nil {
+					return err
+				}
+				if !isReviewer {
+					return fmt.Errorf("%w: 用户 %d 没有审核员或管理员角色", ErrNotCourseReviewer, reviewerID)
+				}
+				reviewer := models.CourseReviewer{CourseID: courseID, UserID: reviewerID}
+				if err := tx.Create(&reviewer).Error; err != nil {
+					return err
+				}
+			}
+		}
+
+		return applyCourseTransition(tx, course, models.CourseActionSubmit, userID, comment)
+	})
+	return course, err
+}
+
+// Withdraw 撤回审核，课程回到草稿状态；线上课程撤回草稿审核，草稿保留
+func (s *CourseReviewService) Withdraw(ctx context.Context, courseID, userID uint, comment string) (*models.Course, error) {
+	var course *models.Course
+	err := database.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var err error
+		if course, err = lockCourse(tx, courseID); err != nil {
+			return err
+		}
+		if !isCourseLive(course) {
+			return applyCourseTransition(tx, course, models.CourseActionWithdraw, userID, comment)
+		}
+
+		draft, err := reviewingDraft(tx, course.ID)
+		if err != nil {
+			return err
+		}
+		if err := clearDraftReview(tx, draft); err != nil {
+			return err
+		}
+		return recordCourseTransition(tx, course, models.CourseActionWithdrawRevision, course.Status, userID, comment)
+	})
+	return course, err
+}
+
+// Approve 审核通过：生成线上修订版本并发布，定时发布时间未到时停留在待发布
+// 线上课程审核的是已提交审核的草稿，通过后草稿生成新的修订版本并立即生效
+func (s *CourseReviewService) Approve(ctx context.Context, courseID, reviewerID uint, comment string) (*models.Course, error) {
+	var course *models.Course
+	err := database.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var err error
+		if course, err = lockCourse(tx, courseID); err != nil {
+			return err
+		}
+		if isCourseLive(course) {
+			draft, err := reviewingDraft(tx, course.ID)
+			if err != nil {
+				return err
+			}
+			if err := s.checkReviewer(tx, course, draft.SubmittedBy, reviewerID); err != nil {
+				return err
+			}
+			if _, err := s.revisionService.ApplyDraft(tx, course, draft, reviewerID); err != nil {
+				return err
+			}
+			return recordCourseTransition(tx, course, models.CourseActionApproveRevision, course.Status, reviewerID, comment)
+		}
+
+		submitterID, err := lastSubmitter(tx, course.ID)
+		if err != nil {
+			return err
+		}
+		if err := s.checkReviewer(tx, course, submitterID, reviewerID); err != nil {
+			return err
+		}
+		if err := s.revisionService.EnsurePublishedRevision(tx, course, reviewerID); err != nil {
+			return err
+		}
+
+		if err := applyCourseTransition(tx, course, models.CourseActionApprove, reviewerID, comment); err != nil {
+			return err
+		}
+
+		if course.PublishAt != nil && course.PublishAt.After(time.Now()) {
+			return nil
+		}
+		if course.PublishAt != nil {
+			course.PublishAt = nil
+			if err := tx.Model(course).Update("publish_at", nil).Error; err != nil {
+				return err
+			}
+		}
+		return applyCourseTransition(tx, course, models.CourseActionPublish, reviewerID, "")
+	})
+	return course, err
+}
+
+// Reject 审核驳回，必须填写审核意见
+func (s *CourseReviewService) Reject(ctx context.Context, courseID, reviewerID uint, comment string) (*models.Course, error) {
+	if comment == "" {
+		return nil, ErrReviewCommentRequired
+	}
+
+	var course *models.Course
+	err := database.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var err error
+		if course, err = lockCourse(tx, courseID); err != nil {
+			return err
+		}
+		if isCourseLive(course) {
+			draft, err := reviewingDraft(tx, course.ID)
+			if err != nil {
+				return err
+			}
+			if err := s.checkReviewer(tx, course, draft.SubmittedBy, reviewerID); err != nil {
+				return err
+			}
+			if err := clearDraftReview(tx, draft); err != nil {
+				return err
+			}
+			return recordCourseTransition(tx, course, models.CourseActionRejectRevision, course.Status, reviewerID, comment)
+		}
+
+		submitterID, err := lastSubmitter(tx, course.ID)
+		if err != nil {
+			return err
+		}
+		if err := s.checkReviewer(tx, course, submitterID, reviewerID); err != nil {
+			return err
+		}
+		return applyCourseTransition(tx, course, models.CourseActionReject, reviewerID, comment)
+	})
+	return course, err
+}
+
+// Publish 立即发布已审核通过、等待定时发布的课程
+func (s *CourseReviewService) Publish(ctx context.Context, courseID, userID uint, comment string) (*models.Course, error) {
+	return s.transition(ctx, courseID, models.CourseActionPublish, userID, comment)
+}
+
+// Unpublish 下架课程
+func (s *CourseReviewService) Unpublish(ctx context.Context, courseID, userID uint, comment string) (*models.Course, error) {
+	return s.transition(ctx, courseID, models.CourseActionUnpublish, userID, comment)
+}
+
+// History 获取课程状态流转记录（按时间先后）
+func (s *CourseReviewService) History(ctx context.Context, courseID uint) ([]models.CourseTransition, error) {
+	var transitions []models.CourseTransition
+	err := database.WithContext(ctx).Where("course_id = ?", courseID).Order("id ASC").Find(&transitions).Error
+	return transitions, err
+}
+
+// Reviewers 获取课程指定审核人用户ID
+func (s *CourseReviewService) Reviewers(ctx context.Context, courseID uint) ([]uint, error) {
+	userIDs := []uint{}
+	err := database.WithContext(ctx).Model(&models.CourseReviewer{}).Where("course_id = ?", courseID).Pluck("user_id", &userIDs).Error
+	return userIDs, err
+}
+
+// PendingCourses 获取待当前用户审核的课程，包括草稿已提交审核的线上课程
+// 只对审核员和管理员角色可见，指定了审核人的课程只有指定审核人可见
+func (s *CourseReviewService) PendingCourses(ctx context.Context, userID uint) ([]models.Course, error) {
+	assigned := database.WithContext(ctx).Model(&models.CourseReviewer{}).Select("course_id").Where("user_id = ?", userID)
+	reviewing := database.WithContext(ctx).Model(&models.CourseDraft{}).Select("course_id").Where("submitted_at IS NOT NULL")
+	query := database.WithContext(ctx).Where("status = ? OR id IN (?)", models.CourseStatusPending, reviewing)
+
+	isReviewer, err := userHasRole(database.WithContext(ctx), userID, models.RoleCodeAdmin, models.RoleCodeCourseReviewer)
+	if err != nil {
+		return nil, err
+	}
+	courses := []models.Course{}
+	if !isReviewer {
+		return courses, nil
+	}
+	anyAssigned := database.WithContext(ctx).Model(&models.CourseReviewer{}).Select("course_id")
+	query = query.Where("id IN (?) OR id NOT IN (?)", assigned, anyAssigned)
+
+	err = query.Order("updated_at ASC").Find(&courses).Error
+	return courses, err
+}
+
+// transition 在事务中锁定课程并执行单个状态流转
+func (s *CourseReviewService) transition(ctx context.Context, courseID uint, action string, operatorID uint, comment string) (*models.Course, error) {
+	var course *models.Course
+	err := database.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var err error
+		if course, err = lockCourse(tx, courseID); err != nil {
+			return err
+		}
+		return applyCourseTransition(tx, course, action, operatorID, comment)
+	})
+	return course, err
+}
+
+// checkReviewer 校验审核人：不能审核自己提交的课程，需要审核员或管理员角色；课程有指定审核人时还必须是其中之一
+func (s *CourseReviewService) checkReviewer(tx *gorm.DB, course *models.Course, submitterID, reviewerID uint) error {
+	if submitterID != 0 && submitterID == reviewerID {
+		return fmt.Errorf("%w: 不能审核自己提交的课程", ErrNotCourseReviewer)
+	}
+
+	isReviewer, err := userHasRole(tx, reviewerID, models.RoleCodeAdmin, models.RoleCodeCourseReviewer)
+	if err != nil {
+		return err
+	}
+	if !isReviewer {
+		return ErrNotCourseReviewer
+	}
+
+	var assigned int64
+	if err := tx.Model(&models.CourseReviewer{}).Where("course_id = ?", course.ID).Count(&assigned).Error; err != nil {
+		return err
+	}
+	if assigned > 0 {
+		var count int64
+		err := tx.Model(&models.CourseReviewer{}).
+			Where("course_id = ? AND user_id = ?", course.ID, reviewerID).
+			Count(&count).Error
+		if err != nil {
+			return err
+		}
+		if count == 0 {
+			return ErrNotCourseReviewer
+		}
+	}
+	return nil
+}
+
+// lastSubmitter 获取最近一次提交课程审核的用户ID，没有提交记录时返回 0
+func lastSubmitter(tx *gorm.DB, courseID uint) (uint, error) {
+	var submit models.CourseTransition
+	err := tx.Where("course_id = ? AND action = ?", courseID, models.CourseActionSubmit).
+		Order("id DESC").First(&submit).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, err
+	}
+	return submit.OperatorID, nil
+}
+
+// reviewingDraft 获取线上课程已提交审核的草稿，没有时返回 ErrInvalidCourseTransition
+func reviewingDraft(tx *gorm.DB, courseID uint) (*models.CourseDraft, error) {
+	var draft models.CourseDraft
+	err := tx.Where("course_id = ? AND submitted_at IS NOT NULL", courseID).First(&draft).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, fmt.Errorf("%w: 课程没有待审核的草稿", ErrInvalidCourseTransition)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &draft, nil
+}
+
+// clearDraftReview 撤回或驳回草稿审核，草稿回到可编辑状态
+func clearDraftReview(tx *gorm.DB, draft *models.CourseDraft) error {
+	draft.SubmittedBy = 0
+	draft.SubmittedAt = nil
+	return tx.Model(draft).Select("submitted_by", "submitted_at").Updates(draft).Error
+}
+
+// applyCourseTransition 按状态机校验并执行状态流转，同时写入流转记录
+// 调用方需已在事务中锁定课程行
+func applyCourseTransition(tx *gorm.DB, course *models.Course, action string, operatorID uint, comment string) error {
+	rule, ok := courseTransitionRules[action]
+	if !ok {
+		return fmt.Errorf("%w: 未知操作 %s", ErrInvalidCourseTransition, action)
+	}
+
+	allowed := false
+	for _, status := range rule.from {
+		if course.Status == status {
+			allowed = true
+			break
+		}
+	}
+	if !allowed {
+		return fmt.Errorf("%w: 状态 %d 不能执行 %s", ErrInvalidCourseTransition, course.Status, action)
+	}
+
+	if err := recordCourseTransition(tx, course, action, rule.to, operatorID, comment); err != nil {
+		return err
+	}
+
+	// 课程下架后草稿审核失效，草稿回到可编辑状态
+	wasLive := isCourseLive(course)
+	course.Status = rule.to
+	if wasLive && !isCourseLive(course) {
+		err := tx.Model(&models.CourseDraft{}).Where("course_id = ? AND submitted_at IS NOT NULL", course.ID).
+			Updates(map[string]interface{}{"submitted_by": 0, "submitted_at": nil}).Error
+		if err != nil {
+			return err
+		}
+	}
+	return tx.Model(course).Update("status", course.Status).Error
+}
+
+// recordCourseTransition 写入课程状态流转记录，不修改课程状态
+func recordCourseTransition(tx *gorm.DB, course *models.Course, action string, toStatus int, operatorID uint, comment string) error {
+	transition := models.CourseTransition{
+		CourseID:   course.ID,
+		Action:     action,
+		FromStatus: course.Status,
+		ToStatus:   toStatus,
+		OperatorID: operatorID,
+		Comment:    comment,
+	}
+	return tx.Create(&transition).Error
+}
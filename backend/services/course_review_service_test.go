@@ -0,0 +1,112 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"learn-hub-backend/database"
+	"learn-hub-backend/models"
+	"learn-hub-backend/testutil"
+)
+
+func TestCourseReviewFlow(t *testing.T) {
+	testutil.SetupDB(t)
+	ctx := context.Background()
+	var service CourseReviewService
+
+	instructor := testutil.CreateUser(t, "teacher", models.RoleCodeInstructor)
+	reviewer := testutil.CreateUser(t, "reviewer", models.RoleCodeCourseReviewer)
+	other := testutil.CreateUser(t, "other", "user")
+	course := testutil.CreateCourse(t, "go-basics", instructor.ID)
+
+	if _, err := service.Submit(ctx, course.ID, instructor.ID, "", nil); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := service.Approve(ctx, course.ID, instructor.ID, ""); !errors.Is(err, ErrNotCourseReviewer) {
+		t.Fatalf("提交人审核自己的课程返回 %v，期望 ErrNotCourseReviewer", err)
+	}
+	if _, err := service.Approve(ctx, course.ID, other.ID, ""); !errors.Is(err, ErrNotCourseReviewer) {
+		t.Fatalf("普通用户审核返回 %v，期望 ErrNotCourseReviewer", err)
+	}
+	if _, err := service.Reject(ctx, course.ID, reviewer.ID, ""); !errors.Is(err, ErrReviewCommentRequired) {
+		t.Fatalf("驳回不填意见返回 %v，期望 ErrReviewCommentRequired", err)
+	}
+
+	approved, err := service.Approve(ctx, course.ID, reviewer.ID, "ok")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if approved.Status != models.CourseStatusPublished {
+		t.Fatalf("审核通过后状态为 %d，期望已发布", approved.Status)
+	}
+	if approved.CurrentRevision == 0 {
+		t.Fatal("审核通过后应生成线上修订版本")
+	}
+	if _, err := service.Withdraw(ctx, course.ID, instructor.ID, ""); !errors.Is(err, ErrInvalidCourseTransition) {
+		t.Fatalf("已发布课程撤回审核返回 %v，期望 ErrInvalidCourseTransition", err)
+	}
+
+	history, err := service.History(ctx, course.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(history) != 3 {
+		t.Fatalf("状态流转记录 %d 条，期望 3 条（提交、通过、发布）", len(history))
+	}
+}
+
+// 指定审核人时只能指定审核员或管理员，已指定的用户失去角色后也不能审核
+func TestCourseReviewAssignedReviewers(t *testing.T) {
+	testutil.SetupDB(t)
+	ctx := context.Background()
+	var service CourseReviewService
+
+	instructor := testutil.CreateUser(t, "teacher", models.RoleCodeInstructor)
+	learner := testutil.CreateUser(t, "learner", "user")
+	assigned := testutil.CreateUser(t, "assigned", models.RoleCodeCourseReviewer)
+	other := testutil.CreateUser(t, "other", models.RoleCodeCourseReviewer)
+	course := testutil.CreateCourse(t, "go-basics", instructor.ID)
+
+	if _, err := service.Submit(ctx, course.ID, instructor.ID, "", []uint{learner.ID}); !errors.Is(err, ErrNotCourseReviewer) {
+		t.Fatalf("指定普通用户为审核人返回 %v，期望 ErrNotCourseReviewer", err)
+	}
+	if reviewers, err := service.Reviewers(ctx, course.ID); err != nil || len(reviewers) != 0 {
+		t.Fatalf("提交失败后审核人为 %v（%v），期望为空", reviewers, err)
+	}
+
+	if _, err := service.Submit(ctx, course.ID, instructor.ID, "", []uint{assigned.ID}); err != nil {
+		t.Fatal(err)
+	}
+	// 绕过提交校验直接指定的普通用户（如指定后被移除角色）同样不能审核
+	if err := database.DB.Create(&models.CourseReviewer{CourseID: course.ID, UserID: learner.ID}).Error; err != nil {
+		t.Fatal(err)
+	}
+	if _, err := service.Approve(ctx, course.ID, learner.ID, ""); !errors.Is(err, ErrNotCourseReviewer) {
+		t.Fatalf("没有审核员角色的指定审核人审核返回 %v，期望 ErrNotCourseReviewer", err)
+	}
+	if _, err := service.Approve(ctx, course.ID, other.ID, ""); !errors.Is(err, ErrNotCourseReviewer) {
+		t.Fatalf("未被指定的审核员审核返回 %v，期望 ErrNotCourseReviewer", err)
+	}
+
+	for _, c := range []struct {
+		user *models.User
+		want int
+	}{{learner, 0}, {other, 0}, {assigned, 1}} {
+		pending, err := service.PendingCourses(ctx, c.user.ID)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(pending) != c.want {
+			t.Errorf("%s 的待审核课程 %d 门，期望 %d 门", c.user.Username, len(pending), c.want)
+		}
+	}
+
+	approved, err := service.Approve(ctx, course.ID, assigned.ID, "ok")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if approved.Status != models.CourseStatusPublished {
+		t.Fatalf("审核通过后状态为 %d，期望已发布", approved.Status)
+	}
+}
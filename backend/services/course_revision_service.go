@@ -0,0 +1,299 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"learn-hub-backend/database"
+	"learn-hub-backend/models"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+var (
+	// ErrNoCourseDraft 课程没有草稿
+	ErrNoCourseDraft = errors.New("课程没有待发布的草稿")
+	// ErrInvalidCourseContent 课程内容不合法
+	ErrInvalidCourseContent = errors.New("课程内容不合法")
+	// ErrDraftInReview 草稿已提交审核，不能修改
+	ErrDraftInReview = errors.New("草稿审核中，请先撤回审核再修改")
+)
+
+// CourseRevisionService 课程修订版本和草稿服务
+type CourseRevisionService struct{}
+
+// FieldDiff 字段差异
+type FieldDiff struct {
+	Field string      `json:"field"`
+	From  interface{} `json:"from"`
+	To    interface{} `json:"to"`
+}
+
+// ValidateCourseContent 校验课程内容类型和对应内容
+func ValidateCourseContent(content models.CourseContent) error {
+	if content.Title == "" {
+		return fmt.Errorf("%w: 课程标题不能为空", ErrInvalidCourseContent)
+	}
+	if content.ContentType < models.ContentTypeVideo || content.ContentType > models.ContentTypeSCORM {
+		return fmt.Errorf("%w: 内容类型错误", ErrInvalidCourseContent)
+	}
+	if content.ContentType == models.ContentTypeVideo && content.VideoURL == "" {
+		return fmt.Errorf("%w: 视频类型课程必须提供视频URL", ErrInvalidCourseContent)
+	}
+	if content.ContentType == models.ContentTypeText && content.TextContent == "" {
+		return fmt.Errorf("%w: 文本类型课程必须提供文本内容", ErrInvalidCourseContent)
+	}
+	return nil
+}
+
+// CurrentRevision 获取课程当前线上修订版本号
+func (s *CourseRevisionService) CurrentRevision(ctx context.Context, courseID uint) int {
+	var course models.Course
+	if err := database.WithContext(ctx).Select("id", "current_revision").First(&course, courseID).Error; err != nil {
+		return 0
+	}
+	return course.CurrentRevision
+}
+
+// GetDraft 获取课程草稿，没有草稿时返回 ErrNoCourseDraft
+func (s *CourseRevisionService) GetDraft(ctx context.Context, courseID uint) (*models.CourseDraft, error) {
+	var draft models.CourseDraft
+	err := database.WithContext(ctx).Where("course_id = ?", courseID).First(&draft).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrNoCourseDraft
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &draft, nil
+}
+
+// SaveDraft 保存课程草稿；edit 在当前草稿（没有则为线上内容）基础上修改内容
+func (s *CourseRevisionService) SaveDraft(ctx context.Context, course *models.Course, userID uint, edit func(*models.CourseContent)) (*models.CourseDraft, error) {
+	draft, err := s.GetDraft(ctx, course.ID)
+	if errors.Is(err, ErrNoCourseDraft) {
+		draft = &models.CourseDraft{
+			CourseID:      course.ID,
+			BaseRevision:  course.CurrentRevision,
+			CourseContent: models.ContentOf(course),
+		}
+	} else if err != nil {
+		return nil, err
+	}
+	if draft.InReview() {
+		return nil, ErrDraftInReview
+	}
+
+	edit(&draft.CourseContent)
+	draft.UpdatedBy = userID
+
+	if err := database.WithContext(ctx).Save(draft).Error; err != nil {
+		return nil, err
+	}
+	return draft, nil
+}
+
+// DiscardDraft 丢弃课程草稿，审核中的草稿需先撤回审核
+func (s *CourseRevisionService) DiscardDraft(ctx context.Context, courseID uint) error {
+	draft, err := s.GetDraft(ctx, courseID)
+	if err != nil {
+		return err
+	}
+	if draft.InReview() {
+		return ErrDraftInReview
+	}
+	return database.WithContext(ctx).Delete(draft).Error
+}
+
+// Diff 比较两份课程内容，返回有变化的字段
+func (s *CourseRevisionService) Diff(from, to models.CourseContent) []FieldDiff {
+	diffs := []FieldDiff{}
+	add := func(field string, a, b interface{}) {
+		if a != b {
+			diffs = append(diffs, FieldDiff{Field: field, From: a, To: b})
+		}
+	}
+
+	add("title", from.Title, to.Title)
+	add("description", from.Description, to.Description)
+	add("coverImage", from.CoverImage, to.CoverImage)
+	add("contentType", from.ContentType, to.ContentType)
+	add("videoUrl", from.VideoURL, to.VideoURL)
+	add("textContent", from.TextContent, to.TextContent)
+	add("duration", from.Duration, to.Duration)
+	return diffs
+}
+
+// PublishDraft 发布草稿
+// 线上课程（已发布或待定时发布）的草稿提交审核，审核通过后才生成新的修订版本，此时返回提交审核的草稿；
+// 其他课程直接生成新的修订版本（重新发布时仍需经过课程审核）
+func (s *CourseRevisionService) PublishDraft(ctx context.Context, courseID, userID uint, note string) (*models.CourseRevision, *models.CourseDraft, error) {
+	var revision *models.CourseRevision
+	var submitted *models.CourseDraft
+	err := database.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		course, err := lockCourse(tx, courseID)
+		if err != nil {
+			return err
+		}
+		if course.Status == models.CourseStatusPending {
+			return fmt.Errorf("%w: 课程审核中，请先撤回审核", ErrInvalidCourseTransition)
+		}
+
+		var draft models.CourseDraft
+		if err := tx.Where("course_id = ?", courseID).First(&draft).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return ErrNoCourseDraft
+			}
+			return err
+		}
+		if draft.InReview() {
+			return ErrDraftInReview
+		}
+		if err := ValidateCourseContent(draft.CourseContent); err != nil {
+			return err
+		}
+
+		if isCourseLive(course) {
+			submitted = &draft
+			return submitDraft(tx, course, &draft, userID, note)
+		}
+
+		revision, err = createRevision(tx, course, draft.CourseContent, userID, note)
+		if err != nil {
+			return err
+		}
+		return tx.Delete(&draft).Error
+	})
+	return revision, submitted, err
+}
+
+// EnsurePublishedRevision 课程首次发布时以线上内容创建第 1 个修订版本
+func (s *CourseRevisionService) EnsurePublishedRevision(tx *gorm.DB, course *models.Course, userID uint) error {
+	if course.CurrentRevision > 0 {
+		return nil
+	}
+	_, err := createRevision(tx, course, models.ContentOf(course), userID, "首次发布")
+	return err
+}
+
+// ListRevisions 获取课程的修订版本列表（新版本在前）
+func (s *CourseRevisionService) ListRevisions(ctx context.Context, courseID uint) ([]models.CourseRevision, error) {
+	var revisions []models.CourseRevision
+	err := database.WithContext(ctx).Where("course_id = ?", courseID).Order("revision DESC").Find(&revisions).Error
+	return revisions, err
+}
+
+// GetRevision 获取指定修订版本
+func (s *CourseRevisionService) GetRevision(ctx context.Context, courseID uint, revision int) (*models.CourseRevision, error) {
+	var rev models.CourseRevision
+	if err := database.WithContext(ctx).Where("course_id = ? AND revision = ?", courseID, revision).First(&rev).Error; err != nil {
+		return nil, err
+	}
+	return &rev, nil
+}
+
+// Rollback 回滚到指定修订版本：以该版本内容创建新的修订版本，历史版本保持不变
+// 线上课程以该版本内容替换草稿并提交审核，审核通过后才生成新的修订版本，此时返回提交审核的草稿
+func (s *CourseRevisionService) Rollback(ctx context.Context, courseID uint, revision int, userID uint) (*models.CourseRevision, *models.CourseDraft, error) {
+	var created *models.CourseRevision
+	var submitted *models.CourseDraft
+	err := database.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		course, err := lockCourse(tx, courseID)
+		if err != nil {
+			return err
+		}
+		if course.Status == models.CourseStatusPending {
+			return fmt.Errorf("%w: 课程审核中，请先撤回审核", ErrInvalidCourseTransition)
+		}
+
+		var target models.CourseRevision
+		if err := tx.Where("course_id = ? AND revision = ?", courseID, revision).First(&target).Error; err != nil {
+			return err
+		}
+		note := fmt.Sprintf("回滚到版本 %d", revision)
+
+		if !isCourseLive(course) {
+			created, err = createRevision(tx, course, target.CourseContent, userID, note)
+			return err
+		}
+
+		draft := models.CourseDraft{CourseID: courseID}
+		if err := tx.Where("course_id = ?", courseID).First(&draft).Error; err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+			return err
+		}
+		if draft.InReview() {
+			return ErrDraftInReview
+		}
+		draft.BaseRevision = course.CurrentRevision
+		draft.CourseContent = target.CourseContent
+		draft.UpdatedBy = userID
+		submitted = &draft
+		return submitDraft(tx, course, &draft, userID, note)
+	})
+	return created, submitted, err
+}
+
+// ApplyDraft 审核通过线上课程已提交审核的草稿：生成新的修订版本并删除草稿
+func (s *CourseRevisionService) ApplyDraft(tx *gorm.DB, course *models.Course, draft *models.CourseDraft, reviewerID uint) (*models.CourseRevision, error) {
+	if err := ValidateCourseContent(draft.CourseContent); err != nil {
+		return nil, err
+	}
+	revision, err := createRevision(tx, course, draft.CourseContent, reviewerID, draft.Note)
+	if err != nil {
+		return nil, err
+	}
+	if err := tx.Delete(draft).Error; err != nil {
+		return nil, err
+	}
+	return revision, nil
+}
+
+// isCourseLive 课程内容是否已对学员生效或已审核通过等待定时发布，此时修改内容需重新审核
+func isCourseLive(course *models.Course) bool {
+	return course.Status == models.CourseStatusPublished || course.Status == models.CourseStatusApproved
+}
+
+// submitDraft 保存草稿并提交审核，课程状态不变
+func submitDraft(tx *gorm.DB, course *models.Course, draft *models.CourseDraft, userID uint, note string) error {
+	now := time.Now()
+	draft.SubmittedBy = userID
+	draft.SubmittedAt = &now
+	draft.Note = note
+	if err := tx.Save(draft).Error; err != nil {
+		return err
+	}
+	return recordCourseTransition(tx, course, models.CourseActionSubmitRevision, course.Status, userID, note)
+}
+
+// lockCourse 在事务中锁定课程行，保证修订版本号递增不冲突
+func lockCourse(tx *gorm.DB, courseID uint) (*models.Course, error) {
+	var course models.Course
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&course, courseID).Error; err != nil {
+		return nil, err
+	}
+	return &course, nil
+}
+
+// createRevision 创建下一个修订版本并同步到课程线上内容
+func createRevision(tx *gorm.DB, course *models.Course, content models.CourseContent, userID uint, note string) (*models.CourseRevision, error) {
+	revision := models.CourseRevision{
+		CourseID:      course.ID,
+		Revision:      course.CurrentRevision + 1,
+		PublishedBy:   userID,
+		Note:          note,
+		CourseContent: content,
+	}
+	if err := tx.Create(&revision).Error; err != nil {
+		return nil, err
+	}
+
+	content.ApplyTo(course)
+	course.CurrentRevision = revision.Revision
+	if err := tx.Save(course).Error; err != nil {
+		return nil, err
+	}
+	return &revision, nil
+}
@@ -0,0 +1,153 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"learn-hub-backend/database"
+	"learn-hub-backend/models"
+	"learn-hub-backend/testutil"
+)
+
+// 已发布课程的草稿和回滚需审核通过后才生效
+func TestPublishedCourseDraftRequiresReview(t *testing.T) {
+	testutil.SetupDB(t)
+	ctx := context.Background()
+	var (
+		reviews   CourseReviewService
+		revisions CourseRevisionService
+	)
+
+	instructor := testutil.CreateUser(t, "teacher", models.RoleCodeInstructor)
+	reviewer := testutil.CreateUser(t, "reviewer", models.RoleCodeCourseReviewer)
+	course := testutil.CreateCourse(t, "go-basics", instructor.ID)
+	if _, err := reviews.Submit(ctx, course.ID, instructor.ID, "", nil); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := reviews.Approve(ctx, course.ID, reviewer.ID, ""); err != nil {
+		t.Fatal(err)
+	}
+
+	liveCourse := func() models.Course {
+		t.Helper()
+		var c models.Course
+		if err := database.DB.First(&c, course.ID).Error; err != nil {
+			t.Fatal(err)
+		}
+		return c
+	}
+	editTitle := func(title string) {
+		t.Helper()
+		c := liveCourse()
+		if _, err := revisions.SaveDraft(ctx, &c, instructor.ID, func(content *models.CourseContent) { content.Title = title }); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	editTitle("Go 进阶")
+	revision, draft, err := revisions.PublishDraft(ctx, course.ID, instructor.ID, "更新标题")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if revision != nil || draft == nil || !draft.InReview() {
+		t.Fatalf("线上课程发布草稿应提交审核，得到 revision=%v draft=%v", revision, draft)
+	}
+	if got := liveCourse(); got.Title != "go-basics" || got.CurrentRevision != 1 || got.Status != models.CourseStatusPublished {
+		t.Fatalf("审核通过前线上内容不应变化: %+v", got)
+	}
+
+	c := liveCourse()
+	if _, err := revisions.SaveDraft(ctx, &c, instructor.ID, func(*models.CourseContent) {}); !errors.Is(err, ErrDraftInReview) {
+		t.Fatalf("审核中修改草稿返回 %v，期望 ErrDraftInReview", err)
+	}
+	if err := revisions.DiscardDraft(ctx, course.ID); !errors.Is(err, ErrDraftInReview) {
+		t.Fatalf("审核中丢弃草稿返回 %v，期望 ErrDraftInReview", err)
+	}
+
+	pending, err := reviews.PendingCourses(ctx, reviewer.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(pending) != 1 || pending[0].ID != course.ID {
+		t.Fatalf("待审核列表应包含草稿审核中的课程: %v", pending)
+	}
+
+	if _, err := reviews.Approve(ctx, course.ID, instructor.ID, ""); !errors.Is(err, ErrNotCourseReviewer) {
+		t.Fatalf("提交人审核自己的草稿返回 %v，期望 ErrNotCourseReviewer", err)
+	}
+	if _, err := reviews.Reject(ctx, course.ID, reviewer.ID, "标题不规范"); err != nil {
+		t.Fatal(err)
+	}
+	if draft, err := revisions.GetDraft(ctx, course.ID); err != nil || draft.InReview() {
+		t.Fatalf("驳回后草稿应保留并可编辑: %v %v", draft, err)
+	}
+	if got := liveCourse(); got.Title != "go-basics" {
+		t.Fatalf("驳回后线上内容不应变化: %s", got.Title)
+	}
+
+	if _, _, err := revisions.PublishDraft(ctx, course.ID, instructor.ID, "更新标题"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := reviews.Approve(ctx, course.ID, reviewer.ID, "ok"); err != nil {
+		t.Fatal(err)
+	}
+	if got := liveCourse(); got.Title != "Go 进阶" || got.CurrentRevision != 2 || got.Status != models.CourseStatusPublished {
+		t.Fatalf("审核通过后应生效为修订版本 2: %+v", got)
+	}
+	if _, err := revisions.GetDraft(ctx, course.ID); !errors.Is(err, ErrNoCourseDraft) {
+		t.Fatalf("审核通过后草稿应删除: %v", err)
+	}
+
+	revision, draft, err = revisions.Rollback(ctx, course.ID, 1, instructor.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if revision != nil || draft == nil || draft.Title != "go-basics" {
+		t.Fatalf("线上课程回滚应提交审核，得到 revision=%v draft=%v", revision, draft)
+	}
+	if got := liveCourse(); got.Title != "Go 进阶" {
+		t.Fatalf("回滚审核通过前线上内容不应变化: %s", got.Title)
+	}
+	if _, err := reviews.Approve(ctx, course.ID, reviewer.ID, ""); err != nil {
+		t.Fatal(err)
+	}
+	if got := liveCourse(); got.Title != "go-basics" || got.CurrentRevision != 3 {
+		t.Fatalf("回滚审核通过后应生效为修订版本 3: %+v", got)
+	}
+}
+
+// 下架的课程没有学员在学，草稿直接生成新的修订版本
+func TestUnpublishedCourseDraftPublishesDirectly(t *testing.T) {
+	testutil.SetupDB(t)
+	ctx := context.Background()
+	var (
+		reviews   CourseReviewService
+		revisions CourseRevisionService
+	)
+
+	instructor := testutil.CreateUser(t, "teacher", models.RoleCodeInstructor)
+	reviewer := testutil.CreateUser(t, "reviewer", models.RoleCodeCourseReviewer)
+	course := testutil.CreateCourse(t, "go-basics", instructor.ID)
+	if _, err := reviews.Submit(ctx, course.ID, instructor.ID, "", nil); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := reviews.Approve(ctx, course.ID, reviewer.ID, ""); err != nil {
+		t.Fatal(err)
+	}
+	published, err := reviews.Unpublish(ctx, course.ID, instructor.ID, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := revisions.SaveDraft(ctx, published, instructor.ID, func(content *models.CourseContent) { content.Title = "Go 进阶" }); err != nil {
+		t.Fatal(err)
+	}
+	revision, draft, err := revisions.PublishDraft(ctx, course.ID, instructor.ID, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if revision == nil || draft != nil || revision.Revision != 2 {
+		t.Fatalf("下架课程发布草稿应直接生成修订版本 2，得到 revision=%v draft=%v", revision, draft)
+	}
+}
@@ -0,0 +1,144 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"time"
+
+	"learn-hub-backend/database"
+	"learn-hub-backend/models"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// AvailableCourses 员工端可见课程的查询条件：已发布且处于上下架时间窗口内
+func AvailableCourses(now time.Time) func(*gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		return db.Where("status = ?", models.CourseStatusPublished).
+			Where("publish_at IS NULL OR publish_at <= ?", now).
+			Where("unpublish_at IS NULL OR unpublish_at > ?", now)
+	}
+}
+
+// CourseScheduler 定时上下架调度器
+// 只发布已审核通过（待发布）的课程；到期任务保存在课程表中，每次扫描都从数据库读取，重启后不会遗漏；
+// 每个课程在事务中加行锁并重新检查条件，多实例同时运行也只会执行一次。
+type CourseScheduler struct {
+	Interval time.Duration
+}
+
+// NewCourseScheduler 创建调度器，默认每分钟扫描一次
+func NewCourseScheduler() *CourseScheduler {
+	return &CourseScheduler{Interval: time.Minute}
+}
+
+// Start 在后台运行调度器，ctx 取消后退出；返回的通道在调度器退出后关闭
+func (s *CourseScheduler) Start(ctx context.Context) <-chan struct{} {
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+
+		ticker := time.NewTicker(s.Interval)
+		defer ticker.Stop()
+
+		s.RunOnce(time.Now())
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case now := <-ticker.C:
+				s.RunOnce(now)
+			}
+		}
+	}()
+	return done
+}
+
+// RunOnce 执行所有已到期的定时发布和定时下架
+func (s *CourseScheduler) RunOnce(now time.Time) {
+	var publishIDs []uint
+	if err := database.DB.Model(&models.Course{}).
+		Where("status = ? AND publish_at IS NOT NULL AND publish_at <= ?", models.CourseStatusApproved, now).
+		Pluck("id", &publishIDs).Error; err != nil {
+		slog.Error("查询定时发布课程失败", "error", err)
+	}
+	for _, id := range publishIDs {
+		if err := s.publish(id, now); err != nil {
+			slog.Error("定时发布课程失败", "course_id", id, "error", err)
+		}
+	}
+
+	var unpublishIDs []uint
+	if err := database.DB.Model(&models.Course{}).
+		Where("unpublish_at IS NOT NULL AND unpublish_at <= ?", now).
+		Pluck("id", &unpublishIDs).Error; err != nil {
+		slog.Error("查询定时下架课程失败", "error", err)
+	}
+	for _, id := range unpublishIDs {
+		if err := s.unpublish(id, now); err != nil {
+			slog.Error("定时下架课程失败", "course_id", id, "error", err)
+		}
+	}
+}
+
+// publish 发布单个到期课程
+func (s *CourseScheduler) publish(id uint, now time.Time) error {
+	return database.DB.Transaction(func(tx *gorm.DB) error {
+		var course models.Course
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("status = ? AND publish_at IS NOT NULL AND publish_at <= ?", models.CourseStatusApproved, now).
+			First(&course, id).Error
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			// 已被其他实例处理
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		course.PublishAt = nil
+		if err := tx.Model(&course).Update("publish_at", nil).Error; err != nil {
+			return err
+		}
+		// 下架时间已过的窗口不再发布，直接下架
+		action := models.CourseActionPublish
+		if course.UnpublishAt != nil && !course.UnpublishAt.After(now) {
+			action = models.CourseActionUnpublish
+		}
+		if err := applyCourseTransition(tx, &course, action, 0, "定时发布"); err != nil {
+			return err
+		}
+		slog.Info("课程定时发布已执行", "course_id", course.ID, "action", action)
+		return nil
+	})
+}
+
+// unpublish 下架单个到期课程
+func (s *CourseScheduler) unpublish(id uint, now time.Time) error {
+	return database.DB.Transaction(func(tx *gorm.DB) error {
+		var course models.Course
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("unpublish_at IS NOT NULL AND unpublish_at <= ?", now).
+			First(&course, id).Error
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		if err := tx.Model(&course).Update("unpublish_at", nil).Error; err != nil {
+			return err
+		}
+		if course.Status != models.CourseStatusPublished && course.Status != models.CourseStatusApproved {
+			return nil
+		}
+		if err := applyCourseTransition(tx, &course, models.CourseActionUnpublish, 0, "定时下架"); err != nil {
+			return err
+		}
+		slog.Info("课程已定时下架", "course_id", course.ID)
+		return nil
+	})
+}
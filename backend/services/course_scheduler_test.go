@@ -0,0 +1,78 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"learn-hub-backend/database"
+	"learn-hub-backend/models"
+	"learn-hub-backend/testutil"
+)
+
+// 到期的定时发布和下架各执行一次，未到期和未审核通过的课程不受影响
+func TestCourseSchedulerRunOnce(t *testing.T) {
+	testutil.SetupDB(t)
+	scheduler := NewCourseScheduler()
+	now := time.Now()
+	past, future := now.Add(-time.Hour), now.Add(time.Hour)
+
+	teacher := testutil.CreateUser(t, "teacher", models.RoleCodeInstructor)
+	schedule := func(title string, status int, publishAt, unpublishAt *time.Time) *models.Course {
+		course := testutil.CreateCourse(t, title, teacher.ID)
+		err := database.DB.Model(course).Updates(map[string]interface{}{
+			"status": status, "publish_at": publishAt, "unpublish_at": unpublishAt,
+		}).Error
+		if err != nil {
+			t.Fatal(err)
+		}
+		return course
+	}
+	due := schedule("due", models.CourseStatusApproved, &past, &future)
+	notYet := schedule("not-yet", models.CourseStatusApproved, &future, nil)
+	pending := schedule("pending", models.CourseStatusPending, &past, nil)
+	expired := schedule("expired", models.CourseStatusApproved, &past, &past)
+	live := schedule("live", models.CourseStatusPublished, nil, &past)
+
+	scheduler.RunOnce(now)
+	// 再次扫描时加锁后重新检查条件，不会重复执行
+	scheduler.RunOnce(now)
+	if err := scheduler.publish(due.ID, now); err != nil {
+		t.Fatal(err)
+	}
+	if err := scheduler.unpublish(live.ID, now); err != nil {
+		t.Fatal(err)
+	}
+
+	want := map[uint]struct {
+		status      int
+		transitions int
+	}{
+		due.ID:     {models.CourseStatusPublished, 1},
+		notYet.ID:  {models.CourseStatusApproved, 0},
+		pending.ID: {models.CourseStatusPending, 0},
+		expired.ID: {models.CourseStatusUnpublished, 1},
+		live.ID:    {models.CourseStatusUnpublished, 1},
+	}
+	for id, w := range want {
+		var course models.Course
+		if err := database.DB.First(&course, id).Error; err != nil {
+			t.Fatal(err)
+		}
+		var transitions int64
+		if err := database.DB.Model(&models.CourseTransition{}).Where("course_id = ?", id).Count(&transitions).Error; err != nil {
+			t.Fatal(err)
+		}
+		if course.Status != w.status || transitions != int64(w.transitions) {
+			t.Errorf("课程 %s 状态 %d、流转记录 %d 条，期望状态 %d、%d 条",
+				course.Title, course.Status, transitions, w.status, w.transitions)
+		}
+	}
+
+	var published models.Course
+	if err := database.DB.First(&published, due.ID).Error; err != nil {
+		t.Fatal(err)
+	}
+	if published.PublishAt != nil || published.UnpublishAt == nil {
+		t.Errorf("发布后 publish_at=%v unpublish_at=%v，期望清空发布时间并保留下架时间", published.PublishAt, published.UnpublishAt)
+	}
+}
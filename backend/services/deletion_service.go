@@ -0,0 +1,401 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"learn-hub-backend/database"
+	"learn-hub-backend/models"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// 删除策略
+const (
+	DeletePolicyBlock    = "block"    // 存在依赖数据时拒绝删除（默认）
+	DeletePolicyCascade  = "cascade"  // 一并删除或解除依赖数据
+	DeletePolicyReassign = "reassign" // 将依赖数据转移给 TargetID
+)
+
+var (
+	ErrDeletionBlocked     = errors.New("数据正在被使用，不能删除")
+	ErrInvalidDeletePolicy = errors.New("不支持的删除策略")
+	ErrReassignTarget      = errors.New("转移目标无效")
+	ErrProtectedRole       = errors.New("内置管理员角色不能删除")
+	ErrLastAdmin           = errors.New("不能删除最后一个管理员")
+	ErrDeleteSelf          = errors.New("不能删除当前登录的用户")
+)
+
+// DeleteOptions 删除参数
+type DeleteOptions struct {
+	Policy     string // block / cascade / reassign，为空时为 block
+	TargetID   uint   // reassign 时的转移目标
+	OperatorID uint
+}
+
+// DeletionImpact 删除影响预览
+type DeletionImpact struct {
+	Kind      string           `json:"kind"`
+	ID        uint             `json:"id"`
+	Name      string           `json:"name"`
+	Counts    map[string]int64 `json:"counts"`    // 各类依赖数据的数量
+	Message   string           `json:"message"`   // 影响说明，如"将影响 42 名学员"
+	Protected bool             `json:"protected"` // 受保护，任何策略都不能删除
+	Reason    string           `json:"reason"`    // 受保护的原因
+	InUse     bool             `json:"inUse"`     // 存在依赖数据，block 策略会拒绝删除
+	Policies  []string         `json:"policies"`  // 可用的删除策略
+
+	protectedErr error
+}
+
+// DeletionService 带依赖检查的删除服务，删除后的数据进入回收站
+type DeletionService struct {
+	recycleService RecycleService
+}
+
+// Impact 预览删除影响
+func (s *DeletionService) Impact(ctx context.Context, kind string, id, operatorID uint) (*DeletionImpact, error) {
+	switch kind {
+	case RecycleKindUser:
+		return s.userImpact(database.WithContext(ctx), id, operatorID)
+	case RecycleKindRole:
+		return s.roleImpact(database.WithContext(ctx), id)
+	case RecycleKindCourse:
+		return s.courseImpact(database.WithContext(ctx), id)
+	default:
+		return nil, ErrRecycleKind
+	}
+}
+
+// DeleteUser 删除用户
+// cascade：解除讲师和审核人分配，负责的课程转为无负责人；reassign：负责的课程和讲师、审核人分配转给目标用户
+func (s *DeletionService) DeleteUser(ctx context.Context, id uint, opts DeleteOptions) error {
+	return database.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		// 锁定管理员角色，串行化管理员的删除，避免并发删除后没有管理员
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("code = ?", models.RoleCodeAdmin).Find(&[]models.Role{}).Error; err != nil {
+			return err
+		}
+
+		impact, err := s.userImpact(tx, id, opts.OperatorID)
+		if err != nil {
+			return err
+		}
+		if err := checkPolicy(impact, opts); err != nil {
+			return err
+		}
+
+		switch opts.Policy {
+		case DeletePolicyCascade:
+			err = deleteWhere(tx, "user_id = ?", id,
+				models.CourseInstructor{}.TableName(), models.CourseReviewer{}.TableName())
+			if err == nil {
+				err = tx.Model(&models.Course{}).Where("owner_id = ?", id).Update("owner_id", 0).Error
+			}
+		case DeletePolicyReassign:
+			err = s.reassignUser(tx, id, opts.TargetID)
+		}
+		if err != nil {
+			return err
+		}
+
+		return s.recycleService.Delete(tx, RecycleKindUser, id, opts.OperatorID)
+	})
+}
+
+// DeleteRole 删除角色，同时清理角色的菜单关联，并重新计算相关用户的访问级别
+// cascade：解除用户与该角色的关联；reassign：用户改为拥有目标角色
+func (s *DeletionService) DeleteRole(ctx context.Context, id uint, opts DeleteOptions) error {
+	return database.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		impact, err := s.roleImpact(tx, id)
+		if err != nil {
+			return err
+		}
+		if err := checkPolicy(impact, opts); err != nil {
+			return err
+		}
+
+		var userIDs []uint
+		if err := tx.Table("sys_user_role").Where("role_id = ?", id).Pluck("user_id", &userIDs).Error; err != nil {
+			return err
+		}
+
+		if opts.Policy == DeletePolicyReassign {
+			var target models.Role
+			if err := tx.Where("id = ? AND id <> ?", opts.TargetID, id).First(&target).Error; err != nil {
+				return fmt.Errorf("%w: 目标角色不存在", ErrReassignTarget)
+			}
+			err := tx.Exec(`INSERT INTO sys_user_role (user_id, role_id)
+				SELECT user_id, ? FROM sys_user_role WHERE role_id = ?
+				AND user_id NOT IN (SELECT user_id FROM (SELECT user_id FROM sys_user_role WHERE role_id = ?) t)`,
+				target.ID, id, target.ID).Error
+			if err != nil {
+				return err
+			}
+		}
+
+		if err := deleteWhere(tx, "role_id = ?", id, "sys_user_role", "sys_role_menu"); err != nil {
+			return err
+		}
+		if err := s.recycleService.Delete(tx, RecycleKindRole, id, opts.OperatorID); err != nil {
+			return err
+		}
+		for _, userID := range userIDs {
+			if err := SyncUserAccess(tx, userID); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// DeleteCourse 删除课程
+// cascade：学习记录随课程一起进入回收站，恢复课程时一并恢复；课程不支持 reassign
+func (s *DeletionService) DeleteCourse(ctx context.Context, id uint, opts DeleteOptions) error {
+	return database.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if _, err := lockCourse(tx, id); err != nil {
+			return err
+		}
+
+		impact, err := s.courseImpact(tx, id)
+		if err != nil {
+			return err
+		}
+		if err := checkPolicy(impact, opts); err != nil {
+			return err
+		}
+
+		if err := s.recycleService.Delete(tx, RecycleKindCourse, id, opts.OperatorID); err != nil {
+			return err
+		}
+		if opts.Policy != DeletePolicyCascade {
+			return nil
+		}
+		// 学习记录的删除时间与课程相同，恢复课程时据此只恢复随课程一起删除的记录
+		deletedAt := tx.Session(&gorm.Session{NewDB: true}).Unscoped().
+			Model(&models.Course{}).Select("deleted_at").Where("id = ?", id)
+		return tx.Model(&models.CourseRecord{}).Where("course_id = ?", id).
+			UpdateColumn("deleted_at", deletedAt).Error
+	})
+}
+
+func (s *DeletionService) userImpact(tx *gorm.DB, id, operatorID uint) (*DeletionImpact, error) {
+	var user models.User
+	if err := tx.Preload("Roles").First(&user, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrUserNotFound
+		}
+		return nil, err
+	}
+
+	impact := &DeletionImpact{
+		Kind:     RecycleKindUser,
+		ID:       user.ID,
+		Name:     user.Username,
+		Counts:   map[string]int64{},
+		Policies: []string{DeletePolicyBlock, DeletePolicyCascade, DeletePolicyReassign},
+	}
+
+	if id == operatorID {
+		impact.protect(ErrDeleteSelf)
+	} else if AccessForRoles(user.Roles) == AccessAdmin {
+		admins, err := countAdmins(tx, id)
+		if err != nil {
+			return nil, err
+		}
+		if admins == 0 {
+			impact.protect(ErrLastAdmin)
+		}
+	}
+
+	counts := []struct {
+		key   string
+		query *gorm.DB
+	}{
+		{"ownedCourses", tx.Model(&models.Course{}).Where("owner_id = ?", id)},
+		{"instructorCourses", tx.Model(&models.CourseInstructor{}).Where("user_id = ?", id)},
+		{"reviewerCourses", tx.Model(&models.CourseReviewer{}).Where("user_id = ?", id)},
+		{"learningRecords", tx.Model(&models.CourseRecord{}).Where("user_id = ?", id)},
+	}
+	for _, c := range counts {
+		var n int64
+		if err := c.query.Count(&n).Error; err != nil {
+			return nil, err
+		}
+		impact.Counts[c.key] = n
+	}
+
+	// 学习记录属于用户本身，随用户进入回收站，不影响删除
+	impact.InUse = impact.Counts["ownedCourses"] > 0 || impact.Counts["instructorCourses"] > 0 || impact.Counts["reviewerCourses"] > 0
+	impact.Message = describeImpact(
+		impactPart{impact.Counts["ownedCourses"], "负责 %d 门课程"},
+		impactPart{impact.Counts["instructorCourses"], "协作 %d 门课程"},
+		impactPart{impact.Counts["reviewerCourses"], "审核 %d 门课程"},
+		impactPart{impact.Counts["learningRecords"], "有 %d 条学习记录"},
+	)
+	return impact, nil
+}
+
+func (s *DeletionService) roleImpact(tx *gorm.DB, id uint) (*DeletionImpact, error) {
+	var role models.Role
+	if err := tx.First(&role, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrRoleNotFound
+		}
+		return nil, err
+	}
+
+	impact := &DeletionImpact{
+		Kind:     RecycleKindRole,
+		ID:       role.ID,
+		Name:     role.Name,
+		Counts:   map[string]int64{},
+		Policies: []string{DeletePolicyBlock, DeletePolicyCascade, DeletePolicyReassign},
+	}
+	if role.Code == models.RoleCodeAdmin {
+		impact.protect(ErrProtectedRole)
+	}
+
+	var users, menus int64
+	if err := tx.Table("sys_user_role").
+		Joins("JOIN sys_user ON sys_user.id = sys_user_role.user_id AND sys_user.deleted_at IS NULL").
+		Where("sys_user_role.role_id = ?", id).Count(&users).Error; err != nil {
+		return nil, err
+	}
+	if err := tx.Table("sys_role_menu").Where("role_id = ?", id).Count(&menus).Error; err != nil {
+		return nil, err
+	}
+	impact.Counts["users"] = users
+	impact.Counts["menus"] = menus
+
+	// 菜单关联属于角色本身，删除时一并清理，不影响删除
+	impact.InUse = users > 0
+	impact.Message = describeImpact(
+		impactPart{users, "影响 %d 个用户"},
+		impactPart{menus, "解除 %d 个菜单权限"},
+	)
+	return impact, nil
+}
+
+func (s *DeletionService) courseImpact(tx *gorm.DB, id uint) (*DeletionImpact, error) {
+	var course models.Course
+	if err := tx.First(&course, id).Error; err != nil {
+		return nil, err
+	}
+
+	impact := &DeletionImpact{
+		Kind:     RecycleKindCourse,
+		ID:       course.ID,
+		Name:     course.Title,
+		Counts:   map[string]int64{},
+		Policies: []string{DeletePolicyBlock, DeletePolicyCascade},
+	}
+
+	var learners, completions int64
+	records := tx.Model(&models.CourseRecord{}).Where("course_id = ?", id)
+	if err := records.Session(&gorm.Session{}).Count(&learners).Error; err != nil {
+		return nil, err
+	}
+	if err := records.Session(&gorm.Session{}).Where("is_completed = ?", true).Count(&completions).Error; err != nil {
+		return nil, err
+	}
+	impact.Counts["learners"] = learners
+	impact.Counts["completions"] = completions
+
+	impact.InUse = learners > 0
+	impact.Message = describeImpact(
+		impactPart{learners, "影响 %d 名学员"},
+		impactPart{completions, "其中 %d 名已完成"},
+	)
+	return impact, nil
+}
+
+// countAdmins 统计拥有管理员角色的启用用户数，不含 excludeID
+func countAdmins(tx *gorm.DB, excludeID uint) (int64, error) {
+	var admins int64
+	err := tx.Model(&models.User{}).
+		Joins("JOIN sys_user_role ur ON ur.user_id = sys_user.id").
+		Joins("JOIN sys_role r ON r.id = ur.role_id AND r.deleted_at IS NULL").
+		Where("r.code = ? AND sys_user.status = ? AND sys_user.id <> ?", models.RoleCodeAdmin, 1, excludeID).
+		Distinct("sys_user.id").Count(&admins).Error
+	return admins, err
+}
+
+// reassignUser 将用户负责的课程和讲师、审核人分配转给目标用户
+func (s *DeletionService) reassignUser(tx *gorm.DB, id, targetID uint) error {
+	var target models.User
+	if err := tx.Where("id = ? AND id <> ? AND status = ?", targetID, id, 1).First(&target).Error; err != nil {
+		return fmt.Errorf("%w: 目标用户不存在或已禁用", ErrReassignTarget)
+	}
+
+	if err := tx.Model(&models.Course{}).Where("owner_id = ?", id).Update("owner_id", target.ID).Error; err != nil {
+		return err
+	}
+	// 目标用户已在同一课程中的分配直接删除，其余转移
+	for _, table := range []string{models.CourseInstructor{}.TableName(), models.CourseReviewer{}.TableName()} {
+		err := tx.Exec("DELETE FROM "+table+" WHERE user_id = ? AND course_id IN (SELECT course_id FROM (SELECT course_id FROM "+table+" WHERE user_id = ?) t)",
+			id, target.ID).Error
+		if err != nil {
+			return err
+		}
+		if err := tx.Exec("UPDATE "+table+" SET user_id = ? WHERE user_id = ?", target.ID, id).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (impact *DeletionImpact) protect(err error) {
+	impact.Protected = true
+	impact.Reason = err.Error()
+	impact.protectedErr = err
+}
+
+// checkPolicy 校验删除策略是否允许删除，策略为空时按 block 处理
+func checkPolicy(impact *DeletionImpact, opts DeleteOptions) error {
+	if impact.Protected {
+		return impact.protectedErr
+	}
+
+	policy := opts.Policy
+	if policy == "" {
+		policy = DeletePolicyBlock
+	}
+	allowed := false
+	for _, p := range impact.Policies {
+		allowed = allowed || p == policy
+	}
+	if !allowed {
+		return fmt.Errorf("%w: %s", ErrInvalidDeletePolicy, policy)
+	}
+
+	if policy == DeletePolicyBlock && impact.InUse {
+		return fmt.Errorf("%w: %s", ErrDeletionBlocked, impact.Message)
+	}
+	if opts.Policy == DeletePolicyReassign && opts.TargetID == 0 {
+		return fmt.Errorf("%w: 未指定转移目标", ErrReassignTarget)
+	}
+	return nil
+}
+
+type impactPart struct {
+	count  int64
+	format string
+}
+
+// describeImpact 拼接影响说明，数量为 0 的部分省略
+func describeImpact(parts ...impactPart) string {
+	var texts []string
+	for _, part := range parts {
+		if part.count > 0 {
+			texts = append(texts, fmt.Sprintf(part.format, part.count))
+		}
+	}
+	if len(texts) == 0 {
+		return "没有关联数据"
+	}
+	return strings.Join(texts, "，")
+}
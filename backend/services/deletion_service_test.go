@@ -0,0 +1,207 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"learn-hub-backend/database"
+	"learn-hub-backend/models"
+	"learn-hub-backend/testutil"
+)
+
+// 不能删除自己和最后一个启用的管理员
+func TestDeleteUserKeepsLastAdmin(t *testing.T) {
+	testutil.SetupDB(t)
+	ctx := context.Background()
+	var service DeletionService
+
+	var admin models.User
+	if err := database.DB.Where("username = ?", "admin").First(&admin).Error; err != nil {
+		t.Fatal(err)
+	}
+	learner := testutil.CreateUser(t, "learner")
+	cascade := func(operatorID uint) DeleteOptions {
+		return DeleteOptions{Policy: DeletePolicyCascade, OperatorID: operatorID}
+	}
+
+	if err := service.DeleteUser(ctx, admin.ID, cascade(admin.ID)); !errors.Is(err, ErrDeleteSelf) {
+		t.Fatalf("删除自己返回 %v，期望 ErrDeleteSelf", err)
+	}
+	if err := service.DeleteUser(ctx, admin.ID, cascade(learner.ID)); !errors.Is(err, ErrLastAdmin) {
+		t.Fatalf("删除唯一的管理员返回 %v，期望 ErrLastAdmin", err)
+	}
+
+	// 禁用的管理员不算在内
+	disabled := testutil.CreateUser(t, "disabled-admin", models.RoleCodeAdmin)
+	if err := database.DB.Model(disabled).Update("status", 0).Error; err != nil {
+		t.Fatal(err)
+	}
+	if err := service.DeleteUser(ctx, admin.ID, cascade(learner.ID)); !errors.Is(err, ErrLastAdmin) {
+		t.Fatalf("只剩禁用的管理员时删除返回 %v，期望 ErrLastAdmin", err)
+	}
+
+	boss := testutil.CreateUser(t, "boss", models.RoleCodeAdmin)
+	impact, err := service.Impact(ctx, RecycleKindUser, admin.ID, boss.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if impact.Protected {
+		t.Fatalf("还有其他管理员时预览为受保护: %s", impact.Reason)
+	}
+	if err := service.DeleteUser(ctx, admin.ID, cascade(boss.ID)); err != nil {
+		t.Fatal(err)
+	}
+	if err := service.DeleteUser(ctx, boss.ID, cascade(learner.ID)); !errors.Is(err, ErrLastAdmin) {
+		t.Fatalf("删除最后一个管理员返回 %v，期望 ErrLastAdmin", err)
+	}
+}
+
+// 用户负责或参与课程时 block 拒绝删除，cascade 解除分配，reassign 转给目标用户
+func TestDeleteUserPolicies(t *testing.T) {
+	testutil.SetupDB(t)
+	ctx := context.Background()
+	var service DeletionService
+
+	alice := testutil.CreateUser(t, "alice", models.RoleCodeInstructor)
+	bob := testutil.CreateUser(t, "bob", models.RoleCodeInstructor)
+	carol := testutil.CreateUser(t, "carol", models.RoleCodeInstructor)
+	owned := testutil.CreateCourse(t, "owned", alice.ID)
+	shared := testutil.CreateCourse(t, "shared", carol.ID)
+	rows := []interface{}{
+		&models.CourseInstructor{CourseID: shared.ID, UserID: alice.ID},
+		&models.CourseInstructor{CourseID: shared.ID, UserID: bob.ID},
+		&models.CourseReviewer{CourseID: owned.ID, UserID: carol.ID},
+	}
+	for _, row := range rows {
+		if err := database.DB.Create(row).Error; err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if err := service.DeleteUser(ctx, alice.ID, DeleteOptions{OperatorID: 1}); !errors.Is(err, ErrDeletionBlocked) {
+		t.Fatalf("block 策略删除返回 %v，期望 ErrDeletionBlocked", err)
+	}
+	reassign := DeleteOptions{Policy: DeletePolicyReassign, OperatorID: 1}
+	if err := service.DeleteUser(ctx, alice.ID, reassign); !errors.Is(err, ErrReassignTarget) {
+		t.Fatalf("未指定转移目标返回 %v，期望 ErrReassignTarget", err)
+	}
+	reassign.TargetID = alice.ID
+	if err := service.DeleteUser(ctx, alice.ID, reassign); !errors.Is(err, ErrReassignTarget) {
+		t.Fatalf("转移给自己返回 %v，期望 ErrReassignTarget", err)
+	}
+
+	// alice 的课程转给 bob，bob 已是 shared 的协作讲师，重复的分配合并
+	reassign.TargetID = bob.ID
+	if err := service.DeleteUser(ctx, alice.ID, reassign); err != nil {
+		t.Fatal(err)
+	}
+	var course models.Course
+	if err := database.DB.First(&course, owned.ID).Error; err != nil {
+		t.Fatal(err)
+	}
+	if course.OwnerID != bob.ID {
+		t.Errorf("转移后课程负责人为 %d，期望 bob", course.OwnerID)
+	}
+	var instructors []uint
+	if err := database.DB.Model(&models.CourseInstructor{}).Where("course_id = ?", shared.ID).Pluck("user_id", &instructors).Error; err != nil {
+		t.Fatal(err)
+	}
+	if len(instructors) != 1 || instructors[0] != bob.ID {
+		t.Errorf("转移后协作讲师为 %v，期望只有 bob", instructors)
+	}
+
+	// carol 负责 shared 并审核 owned，cascade 后课程无负责人、审核分配解除
+	if err := service.DeleteUser(ctx, carol.ID, DeleteOptions{Policy: DeletePolicyCascade, OperatorID: 1}); err != nil {
+		t.Fatal(err)
+	}
+	var orphaned models.Course
+	if err := database.DB.First(&orphaned, shared.ID).Error; err != nil {
+		t.Fatal(err)
+	}
+	var reviewers int64
+	if err := database.DB.Model(&models.CourseReviewer{}).Where("user_id = ?", carol.ID).Count(&reviewers).Error; err != nil {
+		t.Fatal(err)
+	}
+	if orphaned.OwnerID != 0 || reviewers != 0 {
+		t.Errorf("cascade 后课程负责人为 %d、审核分配 %d 条，期望都清空", orphaned.OwnerID, reviewers)
+	}
+}
+
+// 内置管理员角色不能删除；角色有用户时 block 拒绝，reassign 将用户转到目标角色并更新访问级别
+func TestDeleteRolePolicies(t *testing.T) {
+	testutil.SetupDB(t)
+	ctx := context.Background()
+	var service DeletionService
+
+	roleID := func(code string) uint {
+		var role models.Role
+		if err := database.DB.Where("code = ?", code).First(&role).Error; err != nil {
+			t.Fatal(err)
+		}
+		return role.ID
+	}
+	adminRole, instructorRole, reviewerRole := roleID(models.RoleCodeAdmin), roleID(models.RoleCodeInstructor), roleID(models.RoleCodeCourseReviewer)
+
+	for _, policy := range []string{DeletePolicyBlock, DeletePolicyCascade, DeletePolicyReassign} {
+		opts := DeleteOptions{Policy: policy, TargetID: instructorRole, OperatorID: 1}
+		if err := service.DeleteRole(ctx, adminRole, opts); !errors.Is(err, ErrProtectedRole) {
+			t.Fatalf("%s 策略删除管理员角色返回 %v，期望 ErrProtectedRole", policy, err)
+		}
+	}
+
+	teacher := testutil.CreateUser(t, "teacher", models.RoleCodeInstructor)
+	if err := service.DeleteRole(ctx, instructorRole, DeleteOptions{OperatorID: 1}); !errors.Is(err, ErrDeletionBlocked) {
+		t.Fatalf("block 策略删除有用户的角色返回 %v，期望 ErrDeletionBlocked", err)
+	}
+	opts := DeleteOptions{Policy: DeletePolicyReassign, TargetID: adminRole, OperatorID: 1}
+	if err := service.DeleteRole(ctx, instructorRole, opts); err != nil {
+		t.Fatal(err)
+	}
+
+	var user models.User
+	if err := database.DB.Preload("Roles").First(&user, teacher.ID).Error; err != nil {
+		t.Fatal(err)
+	}
+	if len(user.Roles) != 1 || user.Roles[0].ID != adminRole || user.Access != AccessAdmin {
+		t.Fatalf("转移后用户角色为 %+v、访问级别 %s，期望只有管理员角色", user.Roles, user.Access)
+	}
+
+	// 没有用户的角色 block 策略也可以删除
+	if err := service.DeleteRole(ctx, reviewerRole, DeleteOptions{OperatorID: 1}); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// 课程有学员时 block 拒绝删除，课程不支持 reassign
+func TestDeleteCoursePolicies(t *testing.T) {
+	testutil.SetupDB(t)
+	ctx := context.Background()
+	var service DeletionService
+
+	teacher := testutil.CreateUser(t, "teacher", models.RoleCodeInstructor)
+	learner := testutil.CreateUser(t, "learner")
+	course := testutil.CreateCourse(t, "go-basics", teacher.ID)
+	if err := database.DB.Create(&models.CourseRecord{UserID: learner.ID, CourseID: course.ID}).Error; err != nil {
+		t.Fatal(err)
+	}
+
+	impact, err := service.Impact(ctx, RecycleKindCourse, course.ID, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !impact.InUse || impact.Counts["learners"] != 1 || impact.Message != "影响 1 名学员" {
+		t.Fatalf("删除课程影响预览为 %+v", impact)
+	}
+
+	if err := service.DeleteCourse(ctx, course.ID, DeleteOptions{OperatorID: 1}); !errors.Is(err, ErrDeletionBlocked) {
+		t.Fatalf("block 策略删除返回 %v，期望 ErrDeletionBlocked", err)
+	}
+	opts := DeleteOptions{Policy: DeletePolicyReassign, TargetID: teacher.ID, OperatorID: 1}
+	if err := service.DeleteCourse(ctx, course.ID, opts); !errors.Is(err, ErrInvalidDeletePolicy) {
+		t.Fatalf("reassign 策略删除课程返回 %v，期望 ErrInvalidDeletePolicy", err)
+	}
+	if err := service.DeleteCourse(ctx, course.ID, DeleteOptions{Policy: DeletePolicyCascade, OperatorID: 1}); err != nil {
+		t.Fatal(err)
+	}
+}
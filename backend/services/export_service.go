@@ -0,0 +1,475 @@
+package services
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"path"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"learn-hub-backend/config"
+	"learn-hub-backend/database"
+	"learn-hub-backend/models"
+	"learn-hub-backend/utils"
+
+	"github.com/xuri/excelize/v2"
+	"gorm.io/gorm"
+)
+
+// 导出内容
+const (
+	ExportCourseProgress = "course-progress" // 课程学习进度
+	ExportUserProgress   = "user-progress"   // 用户学习进度
+	ExportUsers          = "users"           // 用户列表
+	ExportCourses        = "courses"         // 课程列表
+)
+
+// 导出文件格式
+const (
+	ExportFormatCSV  = "csv"
+	ExportFormatXLSX = "xlsx"
+)
+
+// exportBatchSize 导出时每批读取的行数
+const exportBatchSize = 500
+
+var (
+	// ErrInvalidExport 导出参数不合法
+	ErrInvalidExport = errors.New("导出参数不合法")
+	// ErrExportNotReady 导出文件尚未生成
+	ErrExportNotReady = errors.New("导出文件尚未生成")
+)
+
+// exportSlots 限制同时运行的后台导出任务数
+var exportSlots = make(chan struct{}, 2)
+
+// exportJobs 进行中的后台导出任务，服务退出时等待其完成
+var exportJobs sync.WaitGroup
+
+// exportReport 导出报表定义
+type exportReport struct {
+	name       string // 文件名前缀
+	headers    []string
+	needsScope bool // 是否按课程管理范围过滤
+	query      func(ctx context.Context, p ReportParams, scope *CourseScope) *gorm.DB
+	rows       func(query *gorm.DB, write func([]string) error) error
+}
+
+var exportReports = map[string]exportReport{
+	ExportCourseProgress: {
+		name:       "course-progress",
+		headers:    []string{"用户ID", "用户名", "姓名", "学习进度", "学习时长(秒)", "是否完成", "完成时间", "最后学习时间"},
+		needsScope: true,
+		query:      CourseProgressQuery,
+		rows: func(query *gorm.DB, write func([]string) error) error {
+			var records []models.CourseRecord
+			return eachBatch(query.Preload("User"), &records, func() error {
+				for _, record := range records {
+					err := write([]string{
+						strconv.FormatUint(uint64(record.UserID), 10),
+						record.User.Username,
+						record.User.Name,
+						strconv.Itoa(record.Progress),
+						strconv.Itoa(record.Duration),
+						formatBool(record.IsCompleted),
+						formatTimePtr(record.CompletedAt),
+						record.LastStudyAt.Format(utils.DateTimeLayout),
+					})
+					if err != nil {
+						return err
+					}
+				}
+				return nil
+			})
+		},
+	},
+	ExportUserProgress: {
+		name:       "user-progress",
+		headers:    []string{"课程ID", "课程标题", "学习进度", "学习时长(秒)", "是否完成", "完成时间", "最后学习时间"},
+		needsScope: true,
+		query:      UserProgressQuery,
+		rows: func(query *gorm.DB, write func([]string) error) error {
+			var records []models.CourseRecord
+			return eachBatch(query.Preload("Course"), &records, func() error {
+				for _, record := range records {
+					err := write([]string{
+						strconv.FormatUint(uint64(record.CourseID), 10),
+						record.Course.Title,
+						strconv.Itoa(record.Progress),
+						strconv.Itoa(record.Duration),
+						formatBool(record.IsCompleted),
+						formatTimePtr(record.CompletedAt),
+						record.LastStudyAt.Format(utils.DateTimeLayout),
+					})
+					if err != nil {
+						return err
+					}
+				}
+				return nil
+			})
+		},
+	},
+	ExportUsers: {
+		name:    "users",
+		headers: []string{"ID", "用户名", "姓名", "工号", "邮箱", "手机", "访问级别", "状态", "创建时间"},
+		query: func(ctx context.Context, p ReportParams, _ *CourseScope) *gorm.DB {
+			return UserListQuery(ctx, p)
+		},
+		rows: func(query *gorm.DB, write func([]string) error) error {
+			var users []models.User
+			return eachBatch(query, &users, func() error {
+				for _, user := range users {
+					err := write([]string{
+						strconv.FormatUint(uint64(user.ID), 10),
+						user.Username,
+						user.Name,
+						user.UserID,
+						user.Email,
+						user.Phone,
+						user.Access,
+						strconv.Itoa(user.Status),
+						user.CreatedAt.Format(utils.DateTimeLayout),
+					})
+					if err != nil {
+						return err
+					}
+				}
+				return nil
+			})
+		},
+	},
+	ExportCourses: {
+		name:       "courses",
+		headers:    []string{"ID", "课程标题", "内容类型", "时长(秒)", "状态", "负责人ID", "当前版本", "创建时间", "更新时间"},
+		needsScope: true,
+		query:      CourseListQuery,
+		rows: func(query *gorm.DB, write func([]string) error) error {
+			var courses []models.Course
+			return eachBatch(query, &courses, func() error {
+				for _, course := range courses {
+					err := write([]string{
+						strconv.FormatUint(uint64(course.ID), 10),
+						course.Title,
+						strconv.Itoa(course.ContentType),
+						strconv.Itoa(course.Duration),
+						strconv.Itoa(course.Status),
+						strconv.FormatUint(uint64(course.OwnerID), 10),
+						strconv.Itoa(course.CurrentRevision),
+						course.CreatedAt.Format(utils.DateTimeLayout),
+						course.UpdatedAt.Format(utils.DateTimeLayout),
+					})
+					if err != nil {
+						return err
+					}
+				}
+				return nil
+			})
+		},
+	},
+}
+
+// ExportService 报表导出服务
+type ExportService struct {
+	accessService CourseAccessService
+}
+
+// Validate 校验导出内容和格式
+func (s *ExportService) Validate(kind, format string) error {
+	if _, ok := exportReports[kind]; !ok {
+		return fmt.Errorf("%w: 不支持的导出内容 %s", ErrInvalidExport, kind)
+	}
+	if format != ExportFormatCSV && format != ExportFormatXLSX {
+		return fmt.Errorf("%w: 不支持的文件格式 %s", ErrInvalidExport, format)
+	}
+	return nil
+}
+
+// FileName 生成下载文件名
+func (s *ExportService) FileName(kind, format string) string {
+	return fmt.Sprintf("%s-%s.%s", exportReports[kind].name, time.Now().Format("20060102150405"), format)
+}
+
+// Count 统计导出行数，用于决定同步下载还是后台生成
+func (s *ExportService) Count(ctx context.Context, kind string, p ReportParams, scope *CourseScope) (int64, error) {
+	var count int64
+	err := exportReports[kind].query(ctx, p, scope).Count(&count).Error
+	return count, err
+}
+
+// Export 按批读取数据并流式写入 w，返回写入的数据行数
+func (s *ExportService) Export(ctx context.Context, kind, format string, p ReportParams, scope *CourseScope, w io.Writer) (int64, error) {
+	if err := s.Validate(kind, format); err != nil {
+		return 0, err
+	}
+	report := exportReports[kind]
+
+	tw, err := newTableWriter(format, w)
+	if err != nil {
+		return 0, err
+	}
+	if err := tw.WriteRow(report.headers); err != nil {
+		return 0, err
+	}
+
+	var rows int64
+	err = report.rows(report.query(ctx, p, scope), func(row []string) error {
+		rows++
+		return tw.WriteRow(row)
+	})
+	if err != nil {
+		return rows, err
+	}
+	return rows, tw.Close()
+}
+
+// CreateJob 创建后台导出任务并立即开始生成
+func (s *ExportService) CreateJob(ctx context.Context, userID uint, kind, format string, p ReportParams) (*models.ExportJob, error) {
+	if err := s.Validate(kind, format); err != nil {
+		return nil, err
+	}
+	params, err := json.Marshal(p)
+	if err != nil {
+		return nil, err
+	}
+
+	job := models.ExportJob{
+		UserID:   userID,
+		Kind:     kind,
+		Format:   format,
+		Params:   string(params),
+		Status:   models.ExportJobPending,
+		FileName: s.FileName(kind, format),
+	}
+	if err := database.WithContext(ctx).Create(&job).Error; err != nil {
+		return nil, err
+	}
+
+	// 任务在请求结束后继续执行，只保留请求 context 中的链路和 request_id
+	jobCtx := context.WithoutCancel(ctx)
+	exportJobs.Add(1)
+	go func() {
+		defer exportJobs.Done()
+		s.runJob(jobCtx, job)
+	}()
+	return &job, nil
+}
+
+// ListJobs 获取用户的导出任务（新任务在前）
+func (s *ExportService) ListJobs(ctx context.Context, userID uint, limit int) ([]models.ExportJob, error) {
+	var jobs []models.ExportJob
+	err := database.WithContext(ctx).Where("user_id = ?", userID).Order("id DESC").Limit(limit).Find(&jobs).Error
+	return jobs, err
+}
+
+// GetJob 获取用户的导出任务
+func (s *ExportService) GetJob(ctx context.Context, userID, jobID uint) (*models.ExportJob, error) {
+	var job models.ExportJob
+	if err := database.WithContext(ctx).Where("user_id = ?", userID).First(&job, jobID).Error; err != nil {
+		return nil, err
+	}
+	return &job, nil
+}
+
+// JobFile 获取已完成任务的文件路径
+func (s *ExportService) JobFile(job *models.ExportJob) (string, error) {
+	if job.Status != models.ExportJobDone {
+		return "", ErrExportNotReady
+	}
+	return filepath.Join(config.AppConfig.UploadDir, filepath.FromSlash(job.FilePath)), nil
+}
+
+// RecoverJobs 服务启动时将上次未完成的任务标记为失败（生成中的文件已随进程中断）
+func (s *ExportService) RecoverJobs() error {
+	return database.DB.Model(&models.ExportJob{}).
+		Where("status IN ?", []string{models.ExportJobPending, models.ExportJobRunning}).
+		Updates(map[string]interface{}{
+			"status": models.ExportJobFailed,
+			"error":  "服务重启，任务已中断，请重新导出",
+		}).Error
+}
+
+// WaitJobs 等待进行中的后台导出任务完成，ctx 结束时返回 ctx 的错误（未完成的任务在下次启动时由 RecoverJobs 标记为失败）
+func (s *ExportService) WaitJobs(ctx context.Context) error {
+	done := make(chan struct{})
+	go func() {
+		exportJobs.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// runJob 在后台生成导出文件
+func (s *ExportService) runJob(ctx context.Context, job models.ExportJob) {
+	exportSlots <- struct{}{}
+	defer func() { <-exportSlots }()
+
+	database.WithContext(ctx).Model(&job).Update("status", models.ExportJobRunning)
+
+	filePath := path.Join("exports", strings.ReplaceAll(utils.NewUUID(), "-", "")+"."+job.Format)
+	rows, err := s.writeJobFile(ctx, job, filePath)
+
+	now := time.Now()
+	updates := map[string]interface{}{
+		"row_count":   rows,
+		"finished_at": &now,
+	}
+	if err != nil {
+		slog.Error("导出任务失败", "job_id", job.ID, "error", err)
+		updates["status"] = models.ExportJobFailed
+		updates["error"] = err.Error()
+	} else {
+		updates["status"] = models.ExportJobDone
+		updates["file_path"] = filePath
+	}
+	if err := database.WithContext(ctx).Model(&job).Updates(updates).Error; err != nil {
+		slog.Error("更新导出任务失败", "job_id", job.ID, "error", err)
+	}
+}
+
+// writeJobFile 按任务发起人的权限生成导出文件，失败时删除文件
+func (s *ExportService) writeJobFile(ctx context.Context, job models.ExportJob, filePath string) (int64, error) {
+	var p ReportParams
+	if err := json.Unmarshal([]byte(job.Params), &p); err != nil {
+		return 0, err
+	}
+
+	var scope *CourseScope
+	if exportReports[job.Kind].needsScope {
+		var err error
+		if scope, err = s.accessService.Resolve(ctx, job.UserID); err != nil {
+			return 0, err
+		}
+	}
+
+	target := filepath.Join(config.AppConfig.UploadDir, filepath.FromSlash(filePath))
+	if err := os.MkdirAll(filepath.Dir(target), 0755); err != nil {
+		return 0, err
+	}
+	f, err := os.Create(target)
+	if err != nil {
+		return 0, err
+	}
+
+	rows, err := s.Export(ctx, job.Kind, job.Format, p, scope, f)
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		os.Remove(target)
+	}
+	return rows, err
+}
+
+// tableWriter 表格写入器
+type tableWriter interface {
+	WriteRow(row []string) error
+	Close() error
+}
+
+func newTableWriter(format string, w io.Writer) (tableWriter, error) {
+	switch format {
+	case ExportFormatCSV:
+		// 写入 UTF-8 BOM，避免 Excel 打开中文乱码
+		if _, err := io.WriteString(w, "\xEF\xBB\xBF"); err != nil {
+			return nil, err
+		}
+		return &csvTableWriter{w: csv.NewWriter(w)}, nil
+	case ExportFormatXLSX:
+		f := excelize.NewFile()
+		sw, err := f.NewStreamWriter("Sheet1")
+		if err != nil {
+			f.Close()
+			return nil, err
+		}
+		return &xlsxTableWriter{file: f, stream: sw, w: w}, nil
+	}
+	return nil, fmt.Errorf("%w: 不支持的文件格式 %s", ErrInvalidExport, format)
+}
+
+// csvTableWriter CSV 写入器，每批数据写完后刷新到输出
+type csvTableWriter struct {
+	w    *csv.Writer
+	rows int
+}
+
+func (t *csvTableWriter) WriteRow(row []string) error {
+	if err := t.w.Write(row); err != nil {
+		return err
+	}
+	t.rows++
+	if t.rows%exportBatchSize == 0 {
+		t.w.Flush()
+		return t.w.Error()
+	}
+	return nil
+}
+
+func (t *csvTableWriter) Close() error {
+	t.w.Flush()
+	return t.w.Error()
+}
+
+// xlsxTableWriter XLSX 写入器，行数据由 excelize 流式写入临时文件，关闭时输出
+type xlsxTableWriter struct {
+	file   *excelize.File
+	stream *excelize.StreamWriter
+	w      io.Writer
+	rows   int
+}
+
+func (t *xlsxTableWriter) WriteRow(row []string) error {
+	t.rows++
+	cell, err := excelize.CoordinatesToCellName(1, t.rows)
+	if err != nil {
+		return err
+	}
+	values := make([]interface{}, len(row))
+	for i, v := range row {
+		values[i] = v
+	}
+	return t.stream.SetRow(cell, values)
+}
+
+func (t *xlsxTableWriter) Close() error {
+	defer t.file.Close()
+	if err := t.stream.Flush(); err != nil {
+		return err
+	}
+	return t.file.Write(t.w)
+}
+
+// eachBatch 按主键分批查询，每批查询后调用 fn 处理 dest 中的数据
+func eachBatch(query *gorm.DB, dest interface{}, fn func() error) error {
+	return query.FindInBatches(dest, exportBatchSize, func(tx *gorm.DB, batch int) error {
+		return fn()
+	}).Error
+}
+
+func formatBool(v bool) string {
+	if v {
+		return "是"
+	}
+	return "否"
+}
+
+func formatTimePtr(t *time.Time) string {
+	if t == nil {
+		return ""
+	}
+	return t.Format(utils.DateTimeLayout)
+}
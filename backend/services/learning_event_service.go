@@ -0,0 +1,119 @@
+package services
+
+import (
+	"context"
+	"time"
+
+	"learn-hub-backend/database"
+	"learn-hub-backend/metrics"
+	"learn-hub-backend/models"
+
+	"gorm.io/gorm"
+)
+
+// LearningEventService 学习事件服务
+type LearningEventService struct{}
+
+// LearningEventFilter 学习事件查询条件
+type LearningEventFilter struct {
+	UserID    uint
+	CourseID  uint
+	Verb      string
+	StartTime *time.Time
+	EndTime   *time.Time
+	Offset    int
+	Limit     int
+}
+
+// VerbCount 按动词统计的事件数
+type VerbCount struct {
+	Verb  string `json:"verb"`
+	Count int64  `json:"count"`
+}
+
+// LearningEventSummary 学习事件汇总
+type LearningEventSummary struct {
+	Verbs    []VerbCount `json:"verbs"`
+	Learners int64       `json:"learners"` // 活跃学员数
+	Courses  int64       `json:"courses"`  // 涉及课程数
+}
+
+// Record 追加一条学习事件
+func (s *LearningEventService) Record(ctx context.Context, event *models.LearningEvent) error {
+	if event.OccurredAt.IsZero() {
+		event.OccurredAt = time.Now()
+	}
+	if err := database.WithContext(ctx).Create(event).Error; err != nil {
+		return err
+	}
+
+	switch event.Verb {
+	case models.VerbProgressed:
+		metrics.ProgressUpdates.Inc()
+	case models.VerbCompleted:
+		metrics.CourseCompletions.Inc()
+	}
+	return nil
+}
+
+// Query 按条件分页查询学习事件
+func (s *LearningEventService) Query(ctx context.Context, filter LearningEventFilter) ([]models.LearningEvent, int64, error) {
+	query := s.filtered(ctx, filter)
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var events []models.LearningEvent
+	err := query.Offset(filter.Offset).Limit(filter.Limit).
+		Order("occurred_at DESC, id DESC").
+		Find(&events).Error
+	return events, total, err
+}
+
+// Summary 统计各动词的事件数、活跃学员数和涉及课程数
+func (s *LearningEventService) Summary(ctx context.Context, filter LearningEventFilter) (*LearningEventSummary, error) {
+	verbCounts := []VerbCount{}
+	if err := s.filtered(ctx, filter).
+		Select("verb, COUNT(*) AS count").
+		Group("verb").
+		Scan(&verbCounts).Error; err != nil {
+		return nil, err
+	}
+
+	var learners int64
+	if err := s.filtered(ctx, filter).Distinct("user_id").Count(&learners).Error; err != nil {
+		return nil, err
+	}
+
+	var courses int64
+	if err := s.filtered(ctx, filter).Distinct("course_id").Count(&courses).Error; err != nil {
+		return nil, err
+	}
+
+	return &LearningEventSummary{Verbs: verbCounts, Learners: learners, Courses: courses}, nil
+}
+
+// filtered 构建带过滤条件的查询
+func (s *LearningEventService) filtered(ctx context.Context, filter LearningEventFilter) *gorm.DB {
+	query := database.WithContext(ctx).Model(&models.LearningEvent{})
+
+	if filter.UserID != 0 {
+		query = query.Where("user_id = ?", filter.UserID)
+	}
+	if filter.CourseID != 0 {
+		query = query.Where("course_id = ?", filter.CourseID)
+	}
+	if filter.Verb != "" {
+		query = query.Where("verb = ?", filter.Verb)
+	}
+	if filter.StartTime != nil {
+		query = query.Where("occurred_at >= ?", *filter.StartTime)
+	}
+	if filter.EndTime != nil {
+		query = query.Where("occurred_at < ?", *filter.EndTime)
+	}
+
+	return query
+}
@@ -0,0 +1,524 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"learn-hub-backend/config"
+	"learn-hub-backend/database"
+	"learn-hub-backend/models"
+
+	"gorm.io/gorm"
+)
+
+// 回收站数据类型
+const (
+	RecycleKindUser   = "user"
+	RecycleKindRole   = "role"
+	RecycleKindCourse = "course"
+	RecycleKindRule   = "rule"
+)
+
+var (
+	ErrRecycleKind           = errors.New("不支持的数据类型")
+	ErrRecycleRecordNotFound = errors.New("数据不存在")
+	ErrRecycleNotFound       = errors.New("回收站中不存在该数据")
+	ErrRestoreConflict       = errors.New("唯一键与现有数据冲突")
+)
+
+// RestoreConflictError 恢复时唯一键冲突，Fields 为冲突的字段及其原值
+type RestoreConflictError struct {
+	Fields map[string]string
+}
+
+func (e *RestoreConflictError) Error() string {
+	fields := make([]string, 0, len(e.Fields))
+	for field, value := range e.Fields {
+		fields = append(fields, field+"="+value)
+	}
+	sort.Strings(fields)
+	return ErrRestoreConflict.Error() + ": " + strings.Join(fields, ", ")
+}
+
+func (e *RestoreConflictError) Unwrap() error {
+	return ErrRestoreConflict
+}
+
+// recycleKind 描述一种可进入回收站的数据
+// 软删除时唯一键列被替换为占位值，使原值可以被新数据重新使用；恢复时再写回原值
+type recycleKind struct {
+	model      func() interface{}
+	uniqueKeys []string
+	// name 返回显示名称和唯一键原值（包含已软删除的数据）
+	name func(tx *gorm.DB, id uint) (string, map[string]string, error)
+	// purge 彻底删除前清理关联数据，返回的 cleanup 在事务提交后执行（如删除文件）
+	purge func(tx *gorm.DB, id uint) (cleanup func(), err error)
+	// restoring 恢复前的处理，此时数据仍为删除状态
+	restoring func(tx *gorm.DB, id uint) error
+	// restored 恢复后的处理
+	restored func(tx *gorm.DB, id uint) error
+}
+
+var recycleKinds = map[string]recycleKind{
+	RecycleKindUser: {
+		model:      func() interface{} { return &models.User{} },
+		uniqueKeys: []string{"username", "user_id"},
+		name: func(tx *gorm.DB, id uint) (string, map[string]string, error) {
+			var user models.User
+			if err := tx.Unscoped().First(&user, id).Error; err != nil {
+				return "", nil, err
+			}
+			return user.Name, map[string]string{"username": user.Username, "user_id": user.UserID}, nil
+		},
+		purge:    purgeUserData,
+		restored: SyncUserAccess,
+	},
+	RecycleKindRole: {
+		model:      func() interface{} { return &models.Role{} },
+		uniqueKeys: []string{"code"},
+		name: func(tx *gorm.DB, id uint) (string, map[string]string, error) {
+			var role models.Role
+			if err := tx.Unscoped().First(&role, id).Error; err != nil {
+				return "", nil, err
+			}
+			return role.Name, map[string]string{"code": role.Code}, nil
+		},
+		purge: func(tx *gorm.DB, id uint) (func(), error) {
+			return nil, deleteWhere(tx, "role_id = ?", id, "sys_user_role", "sys_role_menu")
+		},
+		restored: syncRoleUsersAccess,
+	},
+	RecycleKindCourse: {
+		model: func() interface{} { return &models.Course{} },
+		name: func(tx *gorm.DB, id uint) (string, map[string]string, error) {
+			var course models.Course
+			if err := tx.Unscoped().First(&course, id).Error; err != nil {
+				return "", nil, err
+			}
+			return course.Title, nil, nil
+		},
+		purge: purgeCourseData,
+		// 恢复随课程一起删除（删除时间与课程相同）的学习记录，课程删除前单独删除的记录不恢复
+		restoring: func(tx *gorm.DB, id uint) error {
+			deletedAt := tx.Session(&gorm.Session{NewDB: true}).Unscoped().
+				Model(&models.Course{}).Select("deleted_at").Where("id = ?", id)
+			return tx.Unscoped().Model(&models.CourseRecord{}).
+				Where("course_id = ? AND deleted_at = (?)", id, deletedAt).
+				Update("deleted_at", nil).Error
+		},
+	},
+	RecycleKindRule: {
+		model: func() interface{} { return &models.Rule{} },
+		name: func(tx *gorm.DB, id uint) (string, map[string]string, error) {
+			var rule models.Rule
+			if err := tx.Unscoped().First(&rule, id).Error; err != nil {
+				return "", nil, err
+			}
+			return rule.Name, nil, nil
+		},
+	},
+}
+
+// RecycleService 回收站服务
+type RecycleService struct{}
+
+// Delete 软删除数据并放入回收站；tx 为 nil 时使用独立事务
+func (s *RecycleService) Delete(tx *gorm.DB, kind string, id, operatorID uint) error {
+	k, ok := recycleKinds[kind]
+	if !ok {
+		return ErrRecycleKind
+	}
+	if tx == nil {
+		return database.DB.Transaction(func(tx *gorm.DB) error {
+			return s.Delete(tx, kind, id, operatorID)
+		})
+	}
+
+	var count int64
+	if err := tx.Model(k.model()).Where("id = ?", id).Count(&count).Error; err != nil {
+		return err
+	}
+	if count == 0 {
+		return ErrRecycleRecordNotFound
+	}
+
+	if err := tx.Delete(k.model(), id).Error; err != nil {
+		return err
+	}
+	return s.addEntry(tx, kind, k, id, operatorID, time.Now())
+}
+
+// addEntry 为已软删除的数据创建回收站条目，并将唯一键替换为占位值
+func (s *RecycleService) addEntry(tx *gorm.DB, kind string, k recycleKind, id, operatorID uint, deletedAt time.Time) error {
+	name, keys, err := k.name(tx, id)
+	if err != nil {
+		return err
+	}
+
+	if len(k.uniqueKeys) > 0 {
+		updates := make(map[string]interface{}, len(k.uniqueKeys))
+		for _, column := range k.uniqueKeys {
+			if keys[column] != "" {
+				updates[column] = recycleTombstone(id)
+			}
+		}
+		if len(updates) > 0 {
+			if err := tx.Model(k.model()).Unscoped().Where("id = ?", id).UpdateColumns(updates).Error; err != nil {
+				return err
+			}
+		}
+		if name == "" {
+			name = keys[k.uniqueKeys[0]]
+		}
+	}
+
+	uniqueKeys, err := json.Marshal(keys)
+	if err != nil {
+		return err
+	}
+	return tx.Create(&models.RecycleEntry{
+		CreatedAt:  deletedAt,
+		Kind:       kind,
+		RecordID:   id,
+		Name:       name,
+		UniqueKeys: string(uniqueKeys),
+		OperatorID: operatorID,
+	}).Error
+}
+
+// List 分页查询回收站，kind 为空时查询全部类型
+func (s *RecycleService) List(ctx context.Context, kind string, offset, limit int) ([]models.RecycleEntry, int64, error) {
+	query := database.WithContext(ctx).Model(&models.RecycleEntry{})
+	if kind != "" {
+		if _, ok := recycleKinds[kind]; !ok {
+			return nil, 0, ErrRecycleKind
+		}
+		query = query.Where("kind = ?", kind)
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var entries []models.RecycleEntry
+	if err := query.Order("created_at DESC, id DESC").Offset(offset).Limit(limit).Find(&entries).Error; err != nil {
+		return nil, 0, err
+	}
+	return entries, total, nil
+}
+
+// EntryKeys 回收站条目的唯一键原值
+func (s *RecycleService) EntryKeys(entry *models.RecycleEntry) map[string]string {
+	keys := map[string]string{}
+	if entry.UniqueKeys != "" {
+		_ = json.Unmarshal([]byte(entry.UniqueKeys), &keys)
+	}
+	return keys
+}
+
+// Restore 从回收站恢复数据；overrides 可为冲突的唯一键指定新值，
+// 仍有冲突时返回 *RestoreConflictError，不做任何修改
+func (s *RecycleService) Restore(ctx context.Context, kind string, id uint, overrides map[string]string) error {
+	k, ok := recycleKinds[kind]
+	if !ok {
+		return ErrRecycleKind
+	}
+
+	return database.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		entry, err := s.findEntry(tx, kind, id)
+		if err != nil {
+			return err
+		}
+
+		keys := s.EntryKeys(entry)
+		updates := map[string]interface{}{"deleted_at": nil}
+		conflict := &RestoreConflictError{Fields: map[string]string{}}
+		for _, column := range k.uniqueKeys {
+			value := keys[column]
+			if override := strings.TrimSpace(overrides[column]); override != "" {
+				value = override
+			}
+			if value == "" {
+				continue
+			}
+
+			var count int64
+			if err := tx.Model(k.model()).Unscoped().Where(column+" = ? AND id <> ?", value, id).Count(&count).Error; err != nil {
+				return err
+			}
+			if count > 0 {
+				conflict.Fields[column] = value
+			}
+			updates[column] = value
+		}
+		if len(conflict.Fields) > 0 {
+			return conflict
+		}
+
+		if k.restoring != nil {
+			if err := k.restoring(tx, id); err != nil {
+				return err
+			}
+		}
+		if err := tx.Model(k.model()).Unscoped().Where("id = ?", id).UpdateColumns(updates).Error; err != nil {
+			return err
+		}
+		if err := tx.Delete(entry).Error; err != nil {
+			return err
+		}
+		if k.restored != nil {
+			return k.restored(tx, id)
+		}
+		return nil
+	})
+}
+
+// Purge 彻底删除回收站中的数据及其关联数据
+func (s *RecycleService) Purge(ctx context.Context, kind string, id uint) error {
+	k, ok := recycleKinds[kind]
+	if !ok {
+		return ErrRecycleKind
+	}
+
+	var cleanup func()
+	err := database.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		entry, err := s.findEntry(tx, kind, id)
+		if err != nil {
+			return err
+		}
+		if k.purge != nil {
+			if cleanup, err = k.purge(tx, id); err != nil {
+				return err
+			}
+		}
+		if err := tx.Unscoped().Delete(k.model(), id).Error; err != nil {
+			return err
+		}
+		return tx.Delete(entry).Error
+	})
+	if err == nil && cleanup != nil {
+		cleanup()
+	}
+	return err
+}
+
+// PurgeExpired 彻底删除 before 之前进入回收站的数据，返回删除条数
+func (s *RecycleService) PurgeExpired(before time.Time) (int, error) {
+	var entries []models.RecycleEntry
+	if err := database.DB.Where("created_at < ?", before).Find(&entries).Error; err != nil {
+		return 0, err
+	}
+
+	purged := 0
+	for _, entry := range entries {
+		if err := s.Purge(context.Background(), entry.Kind, entry.RecordID); err != nil {
+			if errors.Is(err, ErrRecycleNotFound) {
+				continue
+			}
+			return purged, fmt.Errorf("彻底删除 %s %d 失败: %w", entry.Kind, entry.RecordID, err)
+		}
+		purged++
+	}
+	return purged, nil
+}
+
+// Backfill 为回收站功能上线前已软删除、尚无回收站条目的数据补建条目
+func (s *RecycleService) Backfill() error {
+	for kind, k := range recycleKinds {
+		type deleted struct {
+			ID        uint
+			DeletedAt time.Time
+		}
+		var rows []deleted
+		err := database.DB.Model(k.model()).Unscoped().
+			Select("id", "deleted_at").
+			Where("deleted_at IS NOT NULL").
+			Where("id NOT IN (?)", database.DB.Model(&models.RecycleEntry{}).Select("record_id").Where("kind = ?", kind)).
+			Find(&rows).Error
+		if err != nil {
+			return err
+		}
+
+		for _, row := range rows {
+			err := database.DB.Transaction(func(tx *gorm.DB) error {
+				return s.addEntry(tx, kind, k, row.ID, 0, row.DeletedAt)
+			})
+			if err != nil {
+				return fmt.Errorf("补建回收站条目 %s %d 失败: %w", kind, row.ID, err)
+			}
+		}
+	}
+	return nil
+}
+
+func (s *RecycleService) findEntry(tx *gorm.DB, kind string, id uint) (*models.RecycleEntry, error) {
+	var entry models.RecycleEntry
+	if err := tx.Where("kind = ? AND record_id = ?", kind, id).First(&entry).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrRecycleNotFound
+		}
+		return nil, err
+	}
+	return &entry, nil
+}
+
+// RecyclePurger 回收站定期清理任务：补建历史条目，并彻底删除超过保留天数的数据
+type RecyclePurger struct {
+	Interval      time.Duration
+	RetentionDays int
+
+	recycleService RecycleService
+}
+
+// NewRecyclePurger 创建清理任务，默认每小时运行一次
+func NewRecyclePurger(retentionDays int) *RecyclePurger {
+	return &RecyclePurger{Interval: time.Hour, RetentionDays: retentionDays}
+}
+
+// Start 在后台运行清理任务，ctx 取消后退出；返回的通道在任务退出后关闭
+func (p *RecyclePurger) Start(ctx context.Context) <-chan struct{} {
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+
+		ticker := time.NewTicker(p.Interval)
+		defer ticker.Stop()
+
+		if err := p.recycleService.Backfill(); err != nil {
+			slog.Error("补建回收站条目失败", "error", err)
+		}
+		p.RunOnce(time.Now())
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case now := <-ticker.C:
+				p.RunOnce(now)
+			}
+		}
+	}()
+	return done
+}
+
+// RunOnce 彻底删除超过保留天数的数据，保留天数为 0 时不清理
+func (p *RecyclePurger) RunOnce(now time.Time) {
+	if p.RetentionDays <= 0 {
+		return
+	}
+
+	purged, err := p.recycleService.PurgeExpired(now.AddDate(0, 0, -p.RetentionDays))
+	if err != nil {
+		slog.Error("清理回收站失败", "error", err)
+	}
+	if purged > 0 {
+		slog.Info("回收站已彻底删除过期数据", "purged", purged)
+	}
+}
+
+// recycleTombstone 软删除数据唯一键的占位值
+func recycleTombstone(id uint) string {
+	return fmt.Sprintf("#deleted#%d", id)
+}
+
+// syncRoleUsersAccess 重新计算拥有该角色的用户的访问级别
+func syncRoleUsersAccess(tx *gorm.DB, roleID uint) error {
+	var userIDs []uint
+	if err := tx.Table("sys_user_role").Where("role_id = ?", roleID).Pluck("user_id", &userIDs).Error; err != nil {
+		return err
+	}
+	for _, userID := range userIDs {
+		if err := SyncUserAccess(tx, userID); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// purgeCourseData 清理课程的关联数据，课件文件在事务提交后删除
+func purgeCourseData(tx *gorm.DB, courseID uint) (func(), error) {
+	var packages []models.ScormPackage
+	if err := tx.Unscoped().Where("course_id = ?", courseID).Find(&packages).Error; err != nil {
+		return nil, err
+	}
+	for _, pkg := range packages {
+		if err := tx.Where("package_id = ?", pkg.ID).Delete(&models.ScormRuntimeValue{}).Error; err != nil {
+			return nil, err
+		}
+	}
+	if err := tx.Unscoped().Where("course_id = ?", courseID).Delete(&models.ScormPackage{}).Error; err != nil {
+		return nil, err
+	}
+	if err := tx.Unscoped().Where("course_id = ?", courseID).Delete(&models.CourseRecord{}).Error; err != nil {
+		return nil, err
+	}
+
+	err := deleteWhere(tx, "course_id = ?", courseID,
+		models.CourseInstructor{}.TableName(),
+		models.CourseReviewer{}.TableName(),
+		models.CourseTransition{}.TableName(),
+		models.CourseRevision{}.TableName(),
+		models.CourseDraft{}.TableName(),
+		models.LearningEvent{}.TableName(),
+		models.LearningDailyStat{}.TableName(),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	// 文件删除无法回滚，失败只记录日志
+	return func() {
+		for _, pkg := range packages {
+			dir := filepath.Join(config.AppConfig.UploadDir, filepath.FromSlash(pkg.StorageDir))
+			if err := os.RemoveAll(dir); err != nil {
+				slog.Error("删除课件目录失败", "dir", dir, "error", err)
+			}
+		}
+	}, nil
+}
+
+// purgeUserData 清理用户的关联数据：学习记录（包括已删除的）、学习事件、xAPI 语句、SCORM 运行数据和导出任务，
+// 导出文件在事务提交后删除
+func purgeUserData(tx *gorm.DB, userID uint) (func(), error) {
+	var jobs []models.ExportJob
+	if err := tx.Where("user_id = ? AND file_path <> ''", userID).Find(&jobs).Error; err != nil {
+		return nil, err
+	}
+
+	err := deleteWhere(tx, "user_id = ?", userID, "sys_user_role",
+		models.CourseInstructor{}.TableName(), models.CourseReviewer{}.TableName(),
+		models.CourseRecord{}.TableName(), models.LearningEvent{}.TableName(),
+		models.XAPIStatement{}.TableName(), models.ScormRuntimeValue{}.TableName(),
+		models.ExportJob{}.TableName())
+	if err != nil {
+		return nil, err
+	}
+
+	// 文件删除无法回滚，失败只记录日志
+	return func() {
+		for _, job := range jobs {
+			file := filepath.Join(config.AppConfig.UploadDir, filepath.FromSlash(job.FilePath))
+			if err := os.Remove(file); err != nil && !os.IsNotExist(err) {
+				slog.Error("删除导出文件失败", "file", file, "error", err)
+			}
+		}
+	}, nil
+}
+
+// deleteWhere 按条件从多张表中删除数据
+func deleteWhere(tx *gorm.DB, query string, id uint, tables ...string) error {
+	for _, table := range tables {
+		if err := tx.Exec("DELETE FROM "+table+" WHERE "+query, id).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
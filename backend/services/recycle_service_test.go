@@ -0,0 +1,117 @@
+package services
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"learn-hub-backend/config"
+	"learn-hub-backend/database"
+	"learn-hub-backend/models"
+	"learn-hub-backend/testutil"
+)
+
+// 恢复课程只恢复随课程一起删除的学习记录，之前单独删除的记录保持删除
+func TestRestoreCourseOnlyRestoresRecordsDeletedWithCourse(t *testing.T) {
+	testutil.SetupDB(t)
+	ctx := context.Background()
+	var deletion DeletionService
+	var recycle RecycleService
+
+	teacher := testutil.CreateUser(t, "teacher", models.RoleCodeInstructor)
+	alice := testutil.CreateUser(t, "alice")
+	bob := testutil.CreateUser(t, "bob")
+	course := testutil.CreateCourse(t, "go-basics", teacher.ID)
+
+	removed := models.CourseRecord{UserID: alice.ID, CourseID: course.ID}
+	kept := models.CourseRecord{UserID: bob.ID, CourseID: course.ID}
+	if err := database.DB.Create(&[]*models.CourseRecord{&removed, &kept}).Error; err != nil {
+		t.Fatal(err)
+	}
+	if err := database.DB.Delete(&removed).Error; err != nil {
+		t.Fatal(err)
+	}
+
+	if err := deletion.DeleteCourse(ctx, course.ID, DeleteOptions{Policy: DeletePolicyCascade, OperatorID: 1}); err != nil {
+		t.Fatal(err)
+	}
+	var live int64
+	if err := database.DB.Model(&models.CourseRecord{}).Where("course_id = ?", course.ID).Count(&live).Error; err != nil {
+		t.Fatal(err)
+	}
+	if live != 0 {
+		t.Fatalf("删除课程后仍有 %d 条学习记录", live)
+	}
+
+	if err := recycle.Restore(ctx, RecycleKindCourse, course.ID, nil); err != nil {
+		t.Fatal(err)
+	}
+	var restored []models.CourseRecord
+	if err := database.DB.Where("course_id = ?", course.ID).Find(&restored).Error; err != nil {
+		t.Fatal(err)
+	}
+	if len(restored) != 1 || restored[0].ID != kept.ID {
+		t.Fatalf("恢复课程后的学习记录为 %+v，期望只有 bob 的记录", restored)
+	}
+}
+
+// 彻底删除用户时一并删除其学习记录、学习事件、xAPI 语句、SCORM 运行数据和导出任务及文件
+func TestPurgeUserDeletesLearningData(t *testing.T) {
+	testutil.SetupDB(t)
+	ctx := context.Background()
+	var deletion DeletionService
+	var recycle RecycleService
+
+	teacher := testutil.CreateUser(t, "teacher", models.RoleCodeInstructor)
+	alice := testutil.CreateUser(t, "alice")
+	bob := testutil.CreateUser(t, "bob")
+	course := testutil.CreateCourse(t, "go-basics", teacher.ID)
+	exportFile := func(user *models.User) string {
+		return filepath.Join(config.AppConfig.UploadDir, "exports", user.Username+".csv")
+	}
+
+	for _, user := range []*models.User{alice, bob} {
+		rows := []interface{}{
+			&models.CourseRecord{UserID: user.ID, CourseID: course.ID},
+			&models.LearningEvent{UserID: user.ID, CourseID: course.ID, Verb: models.VerbProgressed},
+			&models.XAPIStatement{StatementID: user.Username, UserID: user.ID, Raw: "{}"},
+			&models.ScormRuntimeValue{UserID: user.ID, PackageID: 1, Element: "cmi.core.lesson_status"},
+			&models.ExportJob{UserID: user.ID, Kind: ExportUsers, Format: ExportFormatCSV, Status: models.ExportJobDone, FilePath: "exports/" + user.Username + ".csv"},
+		}
+		if err := os.MkdirAll(filepath.Dir(exportFile(user)), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(exportFile(user), []byte("id\n"), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		for _, row := range rows {
+			if err := database.DB.Create(row).Error; err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+
+	if err := deletion.DeleteUser(ctx, alice.ID, DeleteOptions{Policy: DeletePolicyCascade, OperatorID: 1}); err != nil {
+		t.Fatal(err)
+	}
+	if err := recycle.Purge(ctx, RecycleKindUser, alice.ID); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, model := range []interface{}{&models.CourseRecord{}, &models.LearningEvent{}, &models.XAPIStatement{}, &models.ScormRuntimeValue{}, &models.ExportJob{}} {
+		var userIDs []uint
+		if err := database.DB.Unscoped().Model(model).Pluck("user_id", &userIDs).Error; err != nil {
+			t.Fatal(err)
+		}
+		if len(userIDs) != 1 || userIDs[0] != bob.ID {
+			t.Errorf("%T 剩余用户 %v，期望只有 bob", model, userIDs)
+		}
+	}
+	if _, err := os.Stat(exportFile(alice)); !os.IsNotExist(err) {
+		t.Errorf("彻底删除用户后导出文件仍然存在: %v", err)
+	}
+	if _, err := os.Stat(exportFile(bob)); err != nil {
+		t.Errorf("其他用户的导出文件被删除: %v", err)
+	}
+}
@@ -0,0 +1,64 @@
+package services
+
+import (
+	"context"
+
+	"learn-hub-backend/database"
+	"learn-hub-backend/models"
+
+	"gorm.io/gorm"
+)
+
+// ReportParams 列表查询和导出共用的筛选条件
+type ReportParams struct {
+	ID       uint   `json:"id,omitempty"` // 课程ID或用户ID
+	Username string `json:"username,omitempty"`
+	Title    string `json:"title,omitempty"`
+	Status   string `json:"status,omitempty"`
+}
+
+// CourseProgressQuery 课程学习进度查询：p.ID 为课程ID，可按用户名模糊搜索，已删除用户的记录不显示
+func CourseProgressQuery(ctx context.Context, p ReportParams, scope *CourseScope) *gorm.DB {
+	query := database.WithContext(ctx).Model(&models.CourseRecord{}).
+		Where("sys_course_record.course_id = ?", p.ID).
+		Where("sys_course_record.user_id IN (?)", database.WithContext(ctx).Model(&models.User{}).Select("id")).
+		Scopes(scope.Courses("sys_course_record.course_id"))
+
+	if p.Username != "" {
+		query = query.Joins("JOIN sys_user ON sys_course_record.user_id = sys_user.id").
+			Scopes(database.Contains("sys_user.username", p.Username))
+	}
+	return query
+}
+
+// UserProgressQuery 用户学习进度查询：p.ID 为用户ID，讲师只能看到自己课程的记录，已删除课程的记录不显示
+func UserProgressQuery(ctx context.Context, p ReportParams, scope *CourseScope) *gorm.DB {
+	return database.WithContext(ctx).Model(&models.CourseRecord{}).
+		Where("user_id = ?", p.ID).
+		Where("course_id IN (?)", database.WithContext(ctx).Model(&models.Course{}).Select("id")).
+		Scopes(scope.Courses("course_id"))
+}
+
+// UserListQuery 用户列表查询，可按用户名和状态筛选
+func UserListQuery(ctx context.Context, p ReportParams) *gorm.DB {
+	query := database.WithContext(ctx).Model(&models.User{})
+	if p.Username != "" {
+		query = query.Scopes(database.Contains("username", p.Username))
+	}
+	if p.Status != "" {
+		query = query.Where("status = ?", p.Status)
+	}
+	return query
+}
+
+// CourseListQuery 课程列表查询，可按标题和状态筛选，讲师只能看到自己负责或协作的课程
+func CourseListQuery(ctx context.Context, p ReportParams, scope *CourseScope) *gorm.DB {
+	query := database.WithContext(ctx).Model(&models.Course{}).Scopes(scope.Courses("id"))
+	if p.Title != "" {
+		query = query.Scopes(database.Contains("title", p.Title))
+	}
+	if p.Status != "" {
+		query = query.Where("status = ?", p.Status)
+	}
+	return query
+}
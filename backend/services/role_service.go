@@ -0,0 +1,159 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"learn-hub-backend/database"
+	"learn-hub-backend/models"
+
+	"gorm.io/gorm"
+)
+
+var (
+	ErrRoleExists   = errors.New("角色代码已存在")
+	ErrMenuNotFound = errors.New("菜单不存在")
+)
+
+// RoleService 角色服务
+type RoleService struct{}
+
+// RoleInput 创建/更新角色参数，更新时空字符串和 nil 表示不修改
+type RoleInput struct {
+	Code        string // 仅创建时使用
+	Name        string
+	Description string
+	Status      *int
+	MenuIDs     []uint // nil 不修改菜单，空切片清空菜单
+}
+
+// CreateRole 创建角色并分配菜单
+func (s *RoleService) CreateRole(ctx context.Context, in RoleInput) (*models.Role, error) {
+	role := models.Role{
+		Code:        in.Code,
+		Name:        in.Name,
+		Description: in.Description,
+		Status:      1,
+	}
+	if in.Status != nil && *in.Status != 0 {
+		role.Status = *in.Status
+	}
+
+	err := database.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var count int64
+		if err := tx.Model(&models.Role{}).Where("code = ?", in.Code).Count(&count).Error; err != nil {
+			return err
+		}
+		if count > 0 {
+			return ErrRoleExists
+		}
+
+		menus, err := findMenus(tx, in.MenuIDs)
+		if err != nil {
+			return err
+		}
+		if err := tx.Create(&role).Error; err != nil {
+			return err
+		}
+		return replaceRoleMenus(tx, &role, menus)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &role, nil
+}
+
+// UpdateRole 更新角色信息，提供 MenuIDs 时替换菜单
+func (s *RoleService) UpdateRole(ctx context.Context, id uint, in RoleInput) (*models.Role, error) {
+	var role models.Role
+	err := database.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.First(&role, id).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return ErrRoleNotFound
+			}
+			return err
+		}
+
+		if in.Name != "" {
+			role.Name = in.Name
+		}
+		if in.Description != "" {
+			role.Description = in.Description
+		}
+		if in.Status != nil {
+			role.Status = *in.Status
+		}
+
+		if in.MenuIDs != nil {
+			menus, err := findMenus(tx, in.MenuIDs)
+			if err != nil {
+				return err
+			}
+			if err := replaceRoleMenus(tx, &role, menus); err != nil {
+				return err
+			}
+		}
+
+		return tx.Omit("Users", "Menus").Save(&role).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &role, nil
+}
+
+// RoleIDsByCodes 根据角色代码查询角色ID，任一代码不存在时返回 ErrRoleNotFound
+func (s *RoleService) RoleIDsByCodes(ctx context.Context, codes []string) ([]uint, error) {
+	if len(codes) == 0 {
+		return nil, nil
+	}
+
+	var roles []models.Role
+	if err := database.WithContext(ctx).Where("code IN ?", codes).Find(&roles).Error; err != nil {
+		return nil, err
+	}
+	byCode := make(map[string]uint, len(roles))
+	for _, role := range roles {
+		byCode[role.Code] = role.ID
+	}
+
+	ids := make([]uint, 0, len(codes))
+	for _, code := range codes {
+		id, ok := byCode[code]
+		if !ok {
+			return nil, fmt.Errorf("%w: %s", ErrRoleNotFound, code)
+		}
+		ids = append(ids, id)
+	}
+	return ids, nil
+}
+
+// findMenus 根据ID查询菜单，任一ID不存在时返回 ErrMenuNotFound
+func findMenus(tx *gorm.DB, ids []uint) ([]models.Menu, error) {
+	if len(ids) == 0 {
+		return nil, nil
+	}
+
+	unique := make(map[uint]bool, len(ids))
+	for _, id := range ids {
+		unique[id] = true
+	}
+
+	var menus []models.Menu
+	if err := tx.Where("id IN ?", ids).Find(&menus).Error; err != nil {
+		return nil, err
+	}
+	if len(menus) != len(unique) {
+		return nil, ErrMenuNotFound
+	}
+	return menus, nil
+}
+
+// replaceRoleMenus 替换角色菜单关联
+func replaceRoleMenus(tx *gorm.DB, role *models.Role, menus []models.Menu) error {
+	if err := tx.Model(role).Association("Menus").Replace(menus); err != nil {
+		return fmt.Errorf("分配菜单失败: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,529 @@
+package services
+
+import (
+	"archive/zip"
+	"context"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"learn-hub-backend/config"
+	"learn-hub-backend/database"
+	"learn-hub-backend/models"
+	"learn-hub-backend/utils"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// maxScormExtractSize 课件包解压后的最大总大小
+const maxScormExtractSize = 1 << 30
+
+var (
+	// ErrScormInvalidPackage 课件包格式不合法
+	ErrScormInvalidPackage = errors.New("SCORM 课件包不合法")
+	// ErrScormReadOnlyElement 试图写入只读的数据模型元素
+	ErrScormReadOnlyElement = errors.New("数据模型元素只读")
+)
+
+// ScormService SCORM 课件导入和运行时服务
+type ScormService struct{}
+
+// scormManifest imsmanifest.xml 中用到的部分
+type scormManifest struct {
+	Identifier    string `xml:"identifier,attr"`
+	SchemaVersion string `xml:"metadata>schemaversion"`
+	Organizations struct {
+		Default       string              `xml:"default,attr"`
+		Organizations []scormOrganization `xml:"organization"`
+	} `xml:"organizations"`
+	Resources []scormResource `xml:"resources>resource"`
+}
+
+type scormOrganization struct {
+	Identifier string      `xml:"identifier,attr"`
+	Title      string      `xml:"title"`
+	Items      []scormItem `xml:"item"`
+}
+
+type scormItem struct {
+	IdentifierRef string      `xml:"identifierref,attr"`
+	Title         string      `xml:"title"`
+	Items         []scormItem `xml:"item"`
+}
+
+type scormResource struct {
+	Identifier string `xml:"identifier,attr"`
+	Href       string `xml:"href,attr"`
+	Base       string `xml:"base,attr"`
+}
+
+// ImportPackage 导入 SCORM 课件包；courseID 为 0 时按清单标题新建草稿课程，负责人为 ownerID
+func (s *ScormService) ImportPackage(ctx context.Context, r io.ReaderAt, size int64, courseID, ownerID uint) (*models.ScormPackage, error) {
+	zr, err := zip.NewReader(r, size)
+	if err != nil {
+		return nil, fmt.Errorf("%w: 无法读取 zip 文件", ErrScormInvalidPackage)
+	}
+
+	manifest, version, err := readScormManifest(zr)
+	if err != nil {
+		return nil, err
+	}
+
+	entryHref, title, err := manifest.launch()
+	if err != nil {
+		return nil, err
+	}
+
+	storageDir := path.Join("scorm", strings.ReplaceAll(utils.NewUUID(), "-", ""))
+	targetDir := filepath.Join(config.AppConfig.UploadDir, filepath.FromSlash(storageDir))
+	extracted, err := extractZip(zr, targetDir)
+	if err != nil {
+		os.RemoveAll(targetDir)
+		return nil, err
+	}
+
+	pkg := models.ScormPackage{
+		Version:    version,
+		Identifier: manifest.Identifier,
+		Title:      title,
+		EntryHref:  entryHref,
+		StorageDir: storageDir,
+		FileSize:   extracted,
+	}
+
+	err = database.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var course models.Course
+		if courseID == 0 {
+			course = models.Course{
+				Title:       title,
+				ContentType: models.ContentTypeSCORM,
+				Status:      models.CourseStatusDraft,
+				OwnerID:     ownerID,
+			}
+			if course.Title == "" {
+				course.Title = manifest.Identifier
+			}
+			if err := tx.Create(&course).Error; err != nil {
+				return err
+			}
+		} else {
+			if err := tx.First(&course, courseID).Error; err != nil {
+				return err
+			}
+			if err := tx.Model(&course).Update("content_type", models.ContentTypeSCORM).Error; err != nil {
+				return err
+			}
+		}
+
+		pkg.CourseID = course.ID
+		return tx.Create(&pkg).Error
+	})
+	if err != nil {
+		os.RemoveAll(targetDir)
+		return nil, err
+	}
+
+	return &pkg, nil
+}
+
+// GetCoursePackage 获取课程当前使用的课件包（最近导入的一个）
+func (s *ScormService) GetCoursePackage(ctx context.Context, courseID uint) (*models.ScormPackage, error) {
+	var pkg models.ScormPackage
+	err := database.WithContext(ctx).Where("course_id = ?", courseID).Order("id DESC").First(&pkg).Error
+	if err != nil {
+		return nil, err
+	}
+	return &pkg, nil
+}
+
+// GetPackage 根据ID获取课件包
+func (s *ScormService) GetPackage(ctx context.Context, id uint) (*models.ScormPackage, error) {
+	var pkg models.ScormPackage
+	if err := database.WithContext(ctx).First(&pkg, id).Error; err != nil {
+		return nil, err
+	}
+	return &pkg, nil
+}
+
+// AssetPath 返回课件包内文件的本地路径，拒绝跳出解压目录的路径
+func (s *ScormService) AssetPath(pkg *models.ScormPackage, name string) (string, bool) {
+	clean := path.Clean("/" + name)
+	if clean == "/" {
+		return "", false
+	}
+	base := filepath.Join(config.AppConfig.UploadDir, filepath.FromSlash(pkg.StorageDir))
+	return filepath.Join(base, filepath.FromSlash(clean)), true
+}
+
+// LaunchURL 返回课件启动地址
+func (s *ScormService) LaunchURL(pkg *models.ScormPackage) string {
+	return fmt.Sprintf("/scorm/content/%d/%s", pkg.ID, pkg.EntryHref)
+}
+
+// Initialize 返回用户在课件包上的运行时数据，包含 LMS 提供的只读元素
+func (s *ScormService) Initialize(ctx context.Context, user *models.User, pkg *models.ScormPackage) (map[string]string, error) {
+	var stored []models.ScormRuntimeValue
+	if err := database.WithContext(ctx).Where("user_id = ? AND package_id = ?", user.ID, pkg.ID).Find(&stored).Error; err != nil {
+		return nil, err
+	}
+
+	values := make(map[string]string, len(stored)+8)
+	for _, v := range stored {
+		values[v.Element] = v.Value
+	}
+
+	learnerID := user.UserID
+	if learnerID == "" {
+		learnerID = user.Username
+	}
+	learnerName := user.Name
+	if learnerName == "" {
+		learnerName = user.Username
+	}
+	entry := "ab-initio"
+	if len(stored) > 0 {
+		entry = "resume"
+	}
+
+	if pkg.Version == models.ScormVersion12 {
+		values["cmi.core.student_id"] = learnerID
+		values["cmi.core.student_name"] = learnerName
+		values["cmi.core.credit"] = "credit"
+		values["cmi.core.lesson_mode"] = "normal"
+		values["cmi.core.entry"] = entry
+		setDefault(values, "cmi.core.lesson_status", "not attempted")
+		setDefault(values, "cmi.core.total_time", "0000:00:00")
+	} else {
+		values["cmi.learner_id"] = learnerID
+		values["cmi.learner_name"] = learnerName
+		values["cmi.credit"] = "credit"
+		values["cmi.mode"] = "normal"
+		values["cmi.entry"] = entry
+		setDefault(values, "cmi.completion_status", "unknown")
+		setDefault(values, "cmi.success_status", "unknown")
+		setDefault(values, "cmi.total_time", "PT0S")
+	}
+
+	return values, nil
+}
+
+// Commit 保存课件上报的数据模型值并回写学习记录；finish 表示课件已结束会话（LMSFinish/Terminate）
+func (s *ScormService) Commit(ctx context.Context, userID uint, pkg *models.ScormPackage, values map[string]string, finish bool) (*models.CourseRecord, error) {
+	for element := range values {
+		if !isWritableScormElement(pkg.Version, element) {
+			return nil, fmt.Errorf("%w: %s", ErrScormReadOnlyElement, element)
+		}
+	}
+
+	// 会话结束时把本次会话时长累加到总时长
+	sessionSeconds := 0
+	sessionKey, totalKey := "cmi.session_time", "cmi.total_time"
+	if pkg.Version == models.ScormVersion12 {
+		sessionKey, totalKey = "cmi.core.session_time", "cmi.core.total_time"
+	}
+	all := make(map[string]string)
+	err := database.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var stored []models.ScormRuntimeValue
+		if err := tx.Where("user_id = ? AND package_id = ?", userID, pkg.ID).Find(&stored).Error; err != nil {
+			return err
+		}
+		for _, v := range stored {
+			all[v.Element] = v.Value
+		}
+		for element, value := range values {
+			all[element] = value
+		}
+
+		updates := make(map[string]string, len(values)+1)
+		for element, value := range values {
+			updates[element] = value
+		}
+		if finish {
+			sessionSeconds = parseScormDuration(all[sessionKey])
+			total := parseScormDuration(all[totalKey]) + sessionSeconds
+			updates[totalKey] = formatScormDuration(pkg.Version, total)
+			all[totalKey] = updates[totalKey]
+			// 清空本次会话时长，避免下次结束会话时重复累加
+			updates[sessionKey] = ""
+		}
+
+		for element, value := range updates {
+			row := models.ScormRuntimeValue{
+				UserID:    userID,
+				PackageID: pkg.ID,
+				Element:   element,
+				Value:     value,
+			}
+			err := tx.Clauses(clause.OnConflict{
+				Columns:   []clause.Column{{Name: "user_id"}, {Name: "package_id"}, {Name: "element"}},
+				DoUpdates: clause.AssignmentColumns([]string{"value", "updated_at"}),
+			}).Create(&row).Error
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	result := scormResult(pkg.Version, all)
+	result.Duration = sessionSeconds
+	return (&CourseRecordService{}).ApplyRuntimeResult(ctx, userID, pkg.CourseID, result)
+}
+
+// scormResult 将数据模型值翻译为学习结果
+func scormResult(version string, values map[string]string) RuntimeResult {
+	var result RuntimeResult
+
+	if version == models.ScormVersion12 {
+		status := values["cmi.core.lesson_status"]
+		result.Completed = status == "completed" || status == "passed"
+		result.Score = scormScore(values["cmi.core.score.raw"], values["cmi.core.score.min"], values["cmi.core.score.max"], "")
+		return result
+	}
+
+	result.Completed = values["cmi.completion_status"] == "completed" || values["cmi.success_status"] == "passed"
+	if measure, err := strconv.ParseFloat(values["cmi.progress_measure"], 64); err == nil && measure >= 0 && measure <= 1 {
+		progress := int(math.Round(measure * 100))
+		result.Progress = &progress
+	}
+	result.Score = scormScore(values["cmi.score.raw"], values["cmi.score.min"], values["cmi.score.max"], values["cmi.score.scaled"])
+	return result
+}
+
+// scormScore 将课件成绩归一化为 0-100，优先使用 scaled（-1..1）
+func scormScore(raw, min, max, scaled string) *float64 {
+	if v, err := strconv.ParseFloat(scaled, 64); err == nil {
+		score := math.Max(0, v) * 100
+		return &score
+	}
+
+	r, err := strconv.ParseFloat(raw, 64)
+	if err != nil {
+		return nil
+	}
+	lo, errMin := strconv.ParseFloat(min, 64)
+	hi, errMax := strconv.ParseFloat(max, 64)
+	if errMax == nil && hi > 0 {
+		if errMin != nil {
+			lo = 0
+		}
+		if hi > lo {
+			score := (r - lo) / (hi - lo) * 100
+			return &score
+		}
+	}
+	return &r
+}
+
+// scormReadOnly 各版本中只能由 LMS 提供的元素
+var scormReadOnly = map[string][]string{
+	models.ScormVersion12: {
+		"cmi._version", "cmi.core.student_id", "cmi.core.student_name", "cmi.core.credit",
+		"cmi.core.entry", "cmi.core.total_time", "cmi.core.lesson_mode", "cmi.launch_data",
+		"cmi.comments_from_lms", "cmi.student_data.mastery_score", "cmi.student_data.max_time_allowed",
+		"cmi.student_data.time_limit_action",
+	},
+	models.ScormVersion2004: {
+		"cmi._version", "cmi.learner_id", "cmi.learner_name", "cmi.credit", "cmi.entry",
+		"cmi.total_time", "cmi.mode", "cmi.launch_data", "cmi.completion_threshold",
+		"cmi.scaled_passing_score", "cmi.max_time_allowed", "cmi.time_limit_action",
+	},
+}
+
+// isWritableScormElement 判断课件是否可以写入该元素
+func isWritableScormElement(version, element string) bool {
+	if !strings.HasPrefix(element, "cmi.") && !strings.HasPrefix(element, "adl.") {
+		return false
+	}
+	if strings.HasSuffix(element, "._children") || strings.HasSuffix(element, "._count") {
+		return false
+	}
+	if strings.HasPrefix(element, "cmi.comments_from_lms") {
+		return false
+	}
+	for _, readOnly := range scormReadOnly[version] {
+		if element == readOnly {
+			return false
+		}
+	}
+	return true
+}
+
+var (
+	scorm12Duration   = regexp.MustCompile(`^(\d{2,4}):(\d{2}):(\d{2})(\.\d{1,2})?$`)
+	scorm2004Duration = regexp.MustCompile(`^P(?:(\d+)Y)?(?:(\d+)M)?(?:(\d+)D)?(?:T(?:(\d+)H)?(?:(\d+)M)?(?:(\d+(?:\.\d+)?)S)?)?$`)
+)
+
+// parseScormDuration 解析 SCORM 1.2 (HHHH:MM:SS.SS) 或 2004 (ISO 8601) 时长，返回秒数
+func parseScormDuration(value string) int {
+	if m := scorm12Duration.FindStringSubmatch(value); m != nil {
+		h, _ := strconv.Atoi(m[1])
+		min, _ := strconv.Atoi(m[2])
+		sec, _ := strconv.Atoi(m[3])
+		return h*3600 + min*60 + sec
+	}
+	if m := scorm2004Duration.FindStringSubmatch(value); m != nil {
+		units := []int{365 * 86400, 30 * 86400, 86400, 3600, 60}
+		total := 0.0
+		for i, unit := range units {
+			if n, err := strconv.Atoi(m[i+1]); err == nil {
+				total += float64(n * unit)
+			}
+		}
+		if sec, err := strconv.ParseFloat(m[6], 64); err == nil {
+			total += sec
+		}
+		return int(total)
+	}
+	return 0
+}
+
+// formatScormDuration 按版本格式化时长
+func formatScormDuration(version string, seconds int) string {
+	h, m, s := seconds/3600, seconds%3600/60, seconds%60
+	if version == models.ScormVersion12 {
+		return fmt.Sprintf("%04d:%02d:%02d", h, m, s)
+	}
+	return fmt.Sprintf("PT%dH%dM%dS", h, m, s)
+}
+
+// readScormManifest 读取并解析根目录下的 imsmanifest.xml，返回清单和 SCORM 版本
+func readScormManifest(zr *zip.Reader) (*scormManifest, string, error) {
+	for _, f := range zr.File {
+		if f.Name != "imsmanifest.xml" {
+			continue
+		}
+
+		rc, err := f.Open()
+		if err != nil {
+			return nil, "", fmt.Errorf("%w: 无法读取 imsmanifest.xml", ErrScormInvalidPackage)
+		}
+		data, err := io.ReadAll(io.LimitReader(rc, 10<<20))
+		rc.Close()
+		if err != nil {
+			return nil, "", fmt.Errorf("%w: 无法读取 imsmanifest.xml", ErrScormInvalidPackage)
+		}
+
+		var manifest scormManifest
+		if err := xml.Unmarshal(data, &manifest); err != nil {
+			return nil, "", fmt.Errorf("%w: imsmanifest.xml 解析失败: %v", ErrScormInvalidPackage, err)
+		}
+
+		version := models.ScormVersion12
+		schemaVersion := strings.TrimSpace(manifest.SchemaVersion)
+		if strings.Contains(schemaVersion, "2004") || strings.Contains(schemaVersion, "CAM 1.3") ||
+			strings.Contains(string(data), "adlcp_v1p3") {
+			version = models.ScormVersion2004
+		}
+		return &manifest, version, nil
+	}
+	return nil, "", fmt.Errorf("%w: 根目录缺少 imsmanifest.xml", ErrScormInvalidPackage)
+}
+
+// launch 返回默认组织中第一个可启动 SCO 的路径和组织标题
+func (m *scormManifest) launch() (string, string, error) {
+	resources := make(map[string]scormResource, len(m.Resources))
+	for _, r := range m.Resources {
+		resources[r.Identifier] = r
+	}
+
+	orgs := m.Organizations.Organizations
+	for i, org := range orgs {
+		if org.Identifier == m.Organizations.Default && i > 0 {
+			orgs[0], orgs[i] = orgs[i], orgs[0]
+			break
+		}
+	}
+
+	for _, org := range orgs {
+		if href := firstLaunchable(org.Items, resources); href != "" {
+			return href, strings.TrimSpace(org.Title), nil
+		}
+	}
+	return "", "", fmt.Errorf("%w: 清单中没有可启动的 SCO", ErrScormInvalidPackage)
+}
+
+// firstLaunchable 深度优先查找第一个引用了带 href 资源的条目
+func firstLaunchable(items []scormItem, resources map[string]scormResource) string {
+	for _, item := range items {
+		if r, ok := resources[item.IdentifierRef]; ok && r.Href != "" {
+			return path.Join(r.Base, r.Href)
+		}
+		if href := firstLaunchable(item.Items, resources); href != "" {
+			return href
+		}
+	}
+	return ""
+}
+
+// extractZip 解压到目标目录，拒绝路径穿越和超出大小上限的包，返回解压总字节数
+func extractZip(zr *zip.Reader, targetDir string) (int64, error) {
+	var total int64
+	for _, f := range zr.File {
+		name := path.Clean("/" + strings.ReplaceAll(f.Name, "\\", "/"))
+		if name == "/" {
+			return 0, fmt.Errorf("%w: 非法文件路径 %s", ErrScormInvalidPackage, f.Name)
+		}
+		dest := filepath.Join(targetDir, filepath.FromSlash(name))
+
+		if f.FileInfo().IsDir() {
+			if err := os.MkdirAll(dest, 0o755); err != nil {
+				return 0, err
+			}
+			continue
+		}
+		if err := os.MkdirAll(filepath.Dir(dest), 0o755); err != nil {
+			return 0, err
+		}
+
+		written, err := extractFile(f, dest, maxScormExtractSize-total)
+		if err != nil {
+			return 0, err
+		}
+		total += written
+	}
+	return total, nil
+}
+
+// extractFile 解压单个文件，最多写入 limit 字节
+func extractFile(f *zip.File, dest string, limit int64) (int64, error) {
+	rc, err := f.Open()
+	if err != nil {
+		return 0, fmt.Errorf("%w: 无法读取 %s", ErrScormInvalidPackage, f.Name)
+	}
+	defer rc.Close()
+
+	out, err := os.OpenFile(dest, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0o644)
+	if err != nil {
+		return 0, err
+	}
+	defer out.Close()
+
+	written, err := io.Copy(out, io.LimitReader(rc, limit+1))
+	if err != nil {
+		return 0, err
+	}
+	if written > limit {
+		return 0, fmt.Errorf("%w: 解压后超过大小上限", ErrScormInvalidPackage)
+	}
+	return written, nil
+}
+
+// setDefault 元素未设置时填入默认值
+func setDefault(values map[string]string, element, value string) {
+	if _, ok := values[element]; !ok {
+		values[element] = value
+	}
+}
@@ -0,0 +1,76 @@
+package services
+
+import (
+	"archive/zip"
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"learn-hub-backend/config"
+	"learn-hub-backend/models"
+	"learn-hub-backend/testutil"
+)
+
+// 解压时带 ../、绝对路径和反斜杠的文件名都只能落在解压目录内
+func TestExtractZipStaysInTargetDir(t *testing.T) {
+	root := t.TempDir()
+	targetDir := filepath.Join(root, "pkg")
+
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	names := []string{"index.html", "../escape.txt", "../../escape2.txt", "/abs.txt", "a/../../up.txt", "..\\win.txt"}
+	for _, name := range names {
+		w, err := zw.Create(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		w.Write([]byte(name))
+	}
+	if err := zw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	zr, err := zip.NewReader(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := extractZip(zr, targetDir); err != nil {
+		t.Fatal(err)
+	}
+
+	entries, err := os.ReadDir(root)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 1 || entries[0].Name() != "pkg" {
+		t.Fatalf("解压目录外出现了文件: %v", entries)
+	}
+	for _, name := range []string{"index.html", "escape.txt", "escape2.txt", "abs.txt", "up.txt", "win.txt"} {
+		if _, err := os.Stat(filepath.Join(targetDir, name)); err != nil {
+			t.Errorf("%s 没有解压到目标目录: %v", name, err)
+		}
+	}
+}
+
+// 课件资源路径不能跳出课件包的解压目录
+func TestScormAssetPathRejectsTraversal(t *testing.T) {
+	testutil.SetupDB(t)
+	var service ScormService
+	pkg := &models.ScormPackage{StorageDir: "scorm/abc"}
+	base := filepath.Join(config.AppConfig.UploadDir, "scorm", "abc")
+
+	for _, name := range []string{"", "/", "..", "../", "a/../.."} {
+		if p, ok := service.AssetPath(pkg, name); ok {
+			t.Errorf("AssetPath(%q) 返回 %s，期望拒绝", name, p)
+		}
+	}
+
+	for _, name := range []string{"index.html", "../../../etc/passwd", "/etc/passwd", "a/../../abc2/x.js"} {
+		p, ok := service.AssetPath(pkg, name)
+		if !ok || !strings.HasPrefix(p, base+string(filepath.Separator)) {
+			t.Errorf("AssetPath(%q) 返回 %s，期望在 %s 之内", name, p, base)
+		}
+	}
+}
@@ -0,0 +1,150 @@
+package services
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"learn-hub-backend/config"
+	"learn-hub-backend/database"
+	"learn-hub-backend/models"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// SequenceUserID 用户工号计数器
+const SequenceUserID = "user_id"
+
+// SequenceService 编号分配服务
+// 计数器行通过 UPDATE value = value + n 原子递增，并发分配时由行锁串行化，不会分配出重复编号
+type SequenceService struct{}
+
+// Allocate 从计数器 name 连续分配 n 个编号，返回第一个编号
+// 在 tx 中调用时行锁持续到外层事务结束；外层事务回滚时编号一并回滚
+func (s *SequenceService) Allocate(tx *gorm.DB, name string, n uint64) (uint64, error) {
+	if n == 0 {
+		return 0, fmt.Errorf("分配数量必须大于 0")
+	}
+	if tx == nil {
+		var first uint64
+		err := database.DB.Transaction(func(tx *gorm.DB) error {
+			var err error
+			first, err = s.Allocate(tx, name, n)
+			return err
+		})
+		return first, err
+	}
+
+	if err := s.ensure(tx, name); err != nil {
+		return 0, err
+	}
+
+	result := tx.Model(&models.Sequence{}).Where("name = ?", name).
+		Update("value", gorm.Expr("value + ?", n))
+	if result.Error != nil {
+		return 0, result.Error
+	}
+	if result.RowsAffected == 0 {
+		return 0, fmt.Errorf("计数器 %s 不存在", name)
+	}
+
+	var seq models.Sequence
+	if err := tx.Where("name = ?", name).First(&seq).Error; err != nil {
+		return 0, err
+	}
+	return seq.Value - n + 1, nil
+}
+
+// AdvanceTo 将计数器推进到至少 value，之后分配的编号都大于 value；计数器已超过 value 时不变
+// 用于写入显式指定的编号（如导入的工号）后避免后续分配出相同编号，应与写入在同一事务中调用
+func (s *SequenceService) AdvanceTo(tx *gorm.DB, name string, value uint64) error {
+	if err := s.ensure(tx, name); err != nil {
+		return err
+	}
+	return tx.Model(&models.Sequence{}).Where("name = ? AND value < ?", name, value).
+		Update("value", value).Error
+}
+
+// ensure 计数器不存在时创建；用户工号计数器以现有最大工号为初始值，兼容已有数据
+func (s *SequenceService) ensure(tx *gorm.DB, name string) error {
+	var count int64
+	if err := tx.Model(&models.Sequence{}).Where("name = ?", name).Count(&count).Error; err != nil {
+		return err
+	}
+	if count > 0 {
+		return nil
+	}
+
+	var initial uint64
+	if name == SequenceUserID {
+		var err error
+		if initial, err = maxNumericUserID(tx); err != nil {
+			return err
+		}
+	}
+
+	// 并发创建时只有一个生效，其余忽略
+	return tx.Clauses(clause.OnConflict{DoNothing: true}).
+		Create(&models.Sequence{Name: name, Value: initial}).Error
+}
+
+// NextUserIDs 分配 n 个用户工号，格式为 前缀 + 按宽度补零的数字
+func (s *SequenceService) NextUserIDs(tx *gorm.DB, n int) ([]string, error) {
+	first, err := s.Allocate(tx, SequenceUserID, uint64(n))
+	if err != nil {
+		return nil, err
+	}
+
+	ids := make([]string, n)
+	for i := range ids {
+		ids[i] = FormatUserID(first + uint64(i))
+	}
+	return ids, nil
+}
+
+// NextUserID 分配一个用户工号
+func (s *SequenceService) NextUserID(tx *gorm.DB) (string, error) {
+	ids, err := s.NextUserIDs(tx, 1)
+	if err != nil {
+		return "", err
+	}
+	return ids[0], nil
+}
+
+// FormatUserID 按配置的前缀和宽度格式化工号
+func FormatUserID(n uint64) string {
+	return fmt.Sprintf("%s%0*d", config.AppConfig.UserIDPrefix, config.AppConfig.UserIDWidth, n)
+}
+
+// maxNumericUserID 获取当前最大的工号数字部分（去掉配置的前缀），非本格式的工号忽略
+func maxNumericUserID(tx *gorm.DB) (uint64, error) {
+	prefix := config.AppConfig.UserIDPrefix
+	query := tx.Model(&models.User{}).Unscoped().Where("user_id != ''")
+	if prefix != "" {
+		query = query.Where("user_id LIKE ?", prefix+"%")
+	}
+
+	var userIDs []string
+	if err := query.Pluck("user_id", &userIDs).Error; err != nil {
+		return 0, err
+	}
+
+	var max uint64
+	for _, id := range userIDs {
+		if n, ok := ParseUserID(id); ok && n > max {
+			max = n
+		}
+	}
+	return max, nil
+}
+
+// ParseUserID 解析工号的数字部分，不是 前缀 + 数字 格式时返回 false
+func ParseUserID(id string) (uint64, bool) {
+	prefix := config.AppConfig.UserIDPrefix
+	if !strings.HasPrefix(id, prefix) {
+		return 0, false
+	}
+	n, err := strconv.ParseUint(strings.TrimPrefix(id, prefix), 10, 64)
+	return n, err == nil
+}
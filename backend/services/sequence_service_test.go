@@ -0,0 +1,90 @@
+package services
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"testing"
+
+	"learn-hub-backend/testutil"
+)
+
+// 并发分配工号时不能重复也不能跳号
+func TestNextUserIDsConcurrent(t *testing.T) {
+	testutil.SetupDB(t)
+	var service SequenceService
+
+	const workers, batch = 20, 5
+	var (
+		wg   sync.WaitGroup
+		mu   sync.Mutex
+		ids  []string
+		errs []error
+	)
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			got, err := service.NextUserIDs(nil, batch)
+			mu.Lock()
+			defer mu.Unlock()
+			if err != nil {
+				errs = append(errs, err)
+				return
+			}
+			ids = append(ids, got...)
+		}()
+	}
+	wg.Wait()
+	if len(errs) > 0 {
+		t.Fatalf("并发分配失败: %v", errs[0])
+	}
+
+	sort.Strings(ids)
+	// 种子数据中的管理员占用了 1
+	for i, id := range ids {
+		if want := FormatUserID(uint64(i + 2)); id != want {
+			t.Fatalf("第 %d 个工号为 %s，期望 %s", i, id, want)
+		}
+	}
+}
+
+func TestCreateUserConcurrent(t *testing.T) {
+	testutil.SetupDB(t)
+	ctx := context.Background()
+	var service UserService
+
+	const workers = 10
+	var wg sync.WaitGroup
+	userIDs := make([]string, workers)
+	errs := make([]error, workers)
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			user, err := service.CreateUser(ctx, CreateUserInput{Username: FormatUserID(uint64(i)) + "-user", Password: "secret123"})
+			if err == nil {
+				userIDs[i] = user.UserID
+			}
+			errs[i] = err
+		}(i)
+	}
+	wg.Wait()
+
+	seen := make(map[string]bool, workers)
+	for i, id := range userIDs {
+		if errs[i] != nil {
+			t.Fatalf("并发创建用户失败: %v", errs[i])
+		}
+		if seen[id] {
+			t.Fatalf("工号 %s 重复分配", id)
+		}
+		seen[id] = true
+	}
+	// 种子数据中的管理员占用了 1
+	for n := uint64(2); n <= workers+1; n++ {
+		if !seen[FormatUserID(n)] {
+			t.Fatalf("并发创建后工号不连续: %v", userIDs)
+		}
+	}
+}
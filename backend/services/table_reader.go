@@ -0,0 +1,73 @@
+package services
+
+import (
+	"bytes"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strings"
+
+	"github.com/xuri/excelize/v2"
+)
+
+// ErrInvalidTableFile 表格文件无法解析
+var ErrInvalidTableFile = errors.New("表格文件格式错误")
+
+// ReadTable 读取 CSV 或 XLSX（第一个工作表）文件的全部行，按文件扩展名判断格式
+func ReadTable(fileName string, r io.Reader, maxRows int) ([][]string, error) {
+	var rows [][]string
+	switch strings.ToLower(filepath.Ext(fileName)) {
+	case ".csv":
+		data, err := io.ReadAll(r)
+		if err != nil {
+			return nil, err
+		}
+		reader := csv.NewReader(bytes.NewReader(bytes.TrimPrefix(data, []byte("\xEF\xBB\xBF"))))
+		reader.FieldsPerRecord = -1
+		reader.TrimLeadingSpace = true
+		for {
+			row, err := reader.Read()
+			if err == io.EOF {
+				break
+			}
+			if err != nil {
+				return nil, fmt.Errorf("%w: %v", ErrInvalidTableFile, err)
+			}
+			rows = append(rows, row)
+			if len(rows) > maxRows {
+				return nil, fmt.Errorf("%w: 行数超过 %d", ErrInvalidTableFile, maxRows)
+			}
+		}
+	case ".xlsx":
+		f, err := excelize.OpenReader(r)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrInvalidTableFile, err)
+		}
+		defer f.Close()
+
+		sheets := f.GetSheetList()
+		if len(sheets) == 0 {
+			return nil, fmt.Errorf("%w: 没有工作表", ErrInvalidTableFile)
+		}
+		iter, err := f.Rows(sheets[0])
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrInvalidTableFile, err)
+		}
+		defer iter.Close()
+		for iter.Next() {
+			row, err := iter.Columns()
+			if err != nil {
+				return nil, fmt.Errorf("%w: %v", ErrInvalidTableFile, err)
+			}
+			rows = append(rows, row)
+			if len(rows) > maxRows {
+				return nil, fmt.Errorf("%w: 行数超过 %d", ErrInvalidTableFile, maxRows)
+			}
+		}
+	default:
+		return nil, fmt.Errorf("%w: 只支持 .csv 和 .xlsx 文件", ErrInvalidTableFile)
+	}
+	return rows, nil
+}
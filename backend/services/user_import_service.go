@@ -0,0 +1,553 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/mail"
+	"os"
+	"path"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"learn-hub-backend/config"
+	"learn-hub-backend/database"
+	"learn-hub-backend/models"
+	"learn-hub-backend/utils"
+
+	"gorm.io/gorm"
+)
+
+// maxUserImportRows 单次导入的最大数据行数
+const maxUserImportRows = 5000
+
+// 导入行操作
+const (
+	ImportActionCreate = "create"
+	ImportActionUpdate = "update"
+)
+
+// ErrInvalidUserImport 导入文件内容不合法
+var ErrInvalidUserImport = errors.New("导入文件不合法")
+
+// userImportColumns 表头别名 -> 字段
+var userImportColumns = map[string]string{
+	"username": "username", "用户名": "username", "账号": "username",
+	"name": "name", "姓名": "name",
+	"email": "email", "邮箱": "email",
+	"phone": "phone", "手机": "phone", "手机号": "phone",
+	"password": "password", "密码": "password",
+	"roles": "roles", "角色": "roles",
+	"status": "status", "状态": "status",
+	"userid": "userid", "工号": "userid",
+	"title": "title", "职位": "title",
+	"groupid": "groupid", "组织id": "groupid", "部门id": "groupid",
+}
+
+// UserImportTemplateHeaders 导入模板表头
+var UserImportTemplateHeaders = []string{"用户名", "姓名", "邮箱", "手机", "密码", "角色", "状态", "工号", "职位", "组织ID"}
+
+// UserImportRow 导入的一行数据及校验结果
+type UserImportRow struct {
+	Row      int      `json:"row"` // 文件中的行号（表头为第 1 行）
+	Username string   `json:"username"`
+	Name     string   `json:"name"`
+	Email    string   `json:"email"`
+	UserID   string   `json:"userid"`
+	Roles    []string `json:"roles"`
+	Action   string   `json:"action"` // create / update
+	Errors   []string `json:"errors"`
+
+	phone    string
+	password string
+	title    string
+	groupID  *uint
+	status   *int
+	hasRoles bool // 文件中是否提供了角色列（为空时不修改已有用户的角色）
+	existing *models.User
+}
+
+// UserImportResult 导入结果
+type UserImportResult struct {
+	ID        uint            `json:"id"` // 导入批次ID，用于下载结果报告
+	DryRun    bool            `json:"dryRun"`
+	Imported  bool            `json:"imported"` // 是否已写入数据库；存在校验错误时不写入任何数据
+	Total     int             `json:"total"`
+	Valid     int             `json:"valid"`
+	Invalid   int             `json:"invalid"`
+	Created   int             `json:"created"`
+	Updated   int             `json:"updated"`
+	Rows      []UserImportRow `json:"rows"`
+	ReportURL string          `json:"reportUrl"`
+}
+
+// UserImportService 批量导入用户服务
+type UserImportService struct {
+	sequenceService SequenceService
+}
+
+// Import 解析并校验导入文件；dryRun 或存在校验错误时只返回预览，否则在一个事务中创建或更新全部用户
+// 按用户名匹配已有用户：不存在则创建（必须提供密码，未提供工号时自动生成），存在则更新非空字段
+func (s *UserImportService) Import(ctx context.Context, operatorID uint, fileName string, r io.Reader, dryRun bool) (*UserImportResult, error) {
+	table, err := ReadTable(fileName, r, maxUserImportRows+1)
+	if err != nil {
+		return nil, err
+	}
+	rows, err := parseUserImportRows(table)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.validate(ctx, rows); err != nil {
+		return nil, err
+	}
+
+	result := &UserImportResult{DryRun: dryRun, Total: len(rows), Rows: rows}
+	for _, row := range rows {
+		if len(row.Errors) > 0 {
+			result.Invalid++
+		} else {
+			result.Valid++
+		}
+	}
+
+	if !dryRun && result.Invalid == 0 && result.Total > 0 {
+		if err := s.apply(ctx, rows); err != nil {
+			return nil, err
+		}
+		result.Imported = true
+	}
+	for _, row := range rows {
+		if len(row.Errors) == 0 && row.Action == ImportActionCreate {
+			result.Created++
+		} else if len(row.Errors) == 0 {
+			result.Updated++
+		}
+	}
+
+	batch := models.UserImport{
+		OperatorID: operatorID,
+		FileName:   fileName,
+		DryRun:     dryRun,
+		Imported:   result.Imported,
+		Total:      result.Total,
+		Failed:     result.Invalid,
+	}
+	if result.Imported {
+		batch.Created = result.Created
+		batch.Updated = result.Updated
+	}
+	if batch.ReportPath, err = writeUserImportReport(result); err != nil {
+		return nil, err
+	}
+	if err := database.WithContext(ctx).Create(&batch).Error; err != nil {
+		return nil, err
+	}
+
+	result.ID = batch.ID
+	result.ReportURL = fmt.Sprintf("/api/user/import/%d/report", batch.ID)
+	return result, nil
+}
+
+// GetImport 获取导入批次
+func (s *UserImportService) GetImport(ctx context.Context, id uint) (*models.UserImport, error) {
+	var batch models.UserImport
+	if err := database.WithContext(ctx).First(&batch, id).Error; err != nil {
+		return nil, err
+	}
+	return &batch, nil
+}
+
+// ReportFile 导入结果报告的文件路径
+func (s *UserImportService) ReportFile(batch *models.UserImport) string {
+	return filepath.Join(config.AppConfig.UploadDir, filepath.FromSlash(batch.ReportPath))
+}
+
+// validate 校验文件内重复、与已有数据冲突和未知角色，并确定每行是创建还是更新
+func (s *UserImportService) validate(ctx context.Context, rows []UserImportRow) error {
+	usernames := make([]string, 0, len(rows))
+	var emails, userIDs, roleCodes []string
+	for _, row := range rows {
+		usernames = append(usernames, row.Username)
+		if row.Email != "" {
+			emails = append(emails, row.Email)
+		}
+		if row.UserID != "" {
+			userIDs = append(userIDs, row.UserID)
+		}
+		roleCodes = append(roleCodes, row.Roles...)
+	}
+
+	existing := map[string]*models.User{}
+	var users []models.User
+	if err := database.WithContext(ctx).Where("username IN ?", usernames).Find(&users).Error; err != nil {
+		return err
+	}
+	for i := range users {
+		existing[users[i].Username] = &users[i]
+	}
+
+	// 已被其他用户占用的邮箱和工号
+	emailOwner := map[string]string{}
+	if len(emails) > 0 {
+		var owners []models.User
+		if err := database.WithContext(ctx).Select("username", "email").Where("email IN ?", emails).Find(&owners).Error; err != nil {
+			return err
+		}
+		for _, u := range owners {
+			emailOwner[strings.ToLower(u.Email)] = u.Username
+		}
+	}
+	userIDOwner := map[string]string{}
+	if len(userIDs) > 0 {
+		var owners []models.User
+		if err := database.WithContext(ctx).Select("username", "user_id").Where("user_id IN ?", userIDs).Find(&owners).Error; err != nil {
+			return err
+		}
+		for _, u := range owners {
+			userIDOwner[u.UserID] = u.Username
+		}
+	}
+
+	knownRoles := map[string]bool{}
+	if len(roleCodes) > 0 {
+		var codes []string
+		if err := database.WithContext(ctx).Model(&models.Role{}).Where("code IN ? AND status = ?", roleCodes, 1).Pluck("code", &codes).Error; err != nil {
+			return err
+		}
+		for _, code := range codes {
+			knownRoles[code] = true
+		}
+	}
+
+	seenUsername := map[string]int{}
+	seenEmail := map[string]int{}
+	seenUserID := map[string]int{}
+	for i := range rows {
+		row := &rows[i]
+		row.existing = existing[row.Username]
+		row.Action = ImportActionCreate
+		if row.existing != nil {
+			row.Action = ImportActionUpdate
+		}
+
+		if first, ok := seenUsername[row.Username]; ok && row.Username != "" {
+			row.Errors = append(row.Errors, fmt.Sprintf("用户名与第 %d 行重复", first))
+		} else {
+			seenUsername[row.Username] = row.Row
+		}
+
+		if row.Email != "" {
+			key := strings.ToLower(row.Email)
+			if first, ok := seenEmail[key]; ok {
+				row.Errors = append(row.Errors, fmt.Sprintf("邮箱与第 %d 行重复", first))
+			} else {
+				seenEmail[key] = row.Row
+			}
+			if owner, ok := emailOwner[key]; ok && owner != row.Username {
+				row.Errors = append(row.Errors, "邮箱已被用户 "+owner+" 使用")
+			}
+		}
+
+		if row.UserID != "" {
+			if first, ok := seenUserID[row.UserID]; ok {
+				row.Errors = append(row.Errors, fmt.Sprintf("工号与第 %d 行重复", first))
+			} else {
+				seenUserID[row.UserID] = row.Row
+			}
+			if owner, ok := userIDOwner[row.UserID]; ok && owner != row.Username {
+				row.Errors = append(row.Errors, "工号已被用户 "+owner+" 使用")
+			}
+		}
+
+		for _, code := range row.Roles {
+			if !knownRoles[code] {
+				row.Errors = append(row.Errors, "未知角色: "+code)
+			}
+		}
+
+		if row.Action == ImportActionCreate && row.password == "" {
+			row.Errors = append(row.Errors, "新用户必须提供密码")
+		}
+	}
+	return nil
+}
+
+// apply 在一个事务中创建或更新全部用户，任一行失败则全部回滚
+func (s *UserImportService) apply(ctx context.Context, rows []UserImportRow) error {
+	// 密码加密较慢，放在事务外完成
+	hashed := make([]string, len(rows))
+	for i, row := range rows {
+		if row.password == "" {
+			continue
+		}
+		hash, err := utils.HashPassword(row.password)
+		if err != nil {
+			return fmt.Errorf("第 %d 行密码加密失败: %w", row.Row, err)
+		}
+		hashed[i] = hash
+	}
+
+	return database.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var roles []models.Role
+		if err := tx.Where("status = ?", 1).Find(&roles).Error; err != nil {
+			return err
+		}
+		roleByCode := make(map[string]models.Role, len(roles))
+		for _, role := range roles {
+			roleByCode[role.Code] = role
+		}
+
+		// 显式指定的工号占用了计数器的编号，先推进计数器，避免之后分配出相同的工号
+		var maxImported uint64
+		missing := 0
+		for _, row := range rows {
+			if n, ok := ParseUserID(row.UserID); ok && n > maxImported {
+				maxImported = n
+			}
+			// 没有指定工号的新用户和原本没有工号的已有用户都需要分配
+			if row.UserID == "" && (row.existing == nil || row.existing.UserID == "") {
+				missing++
+			}
+		}
+		if maxImported > 0 {
+			if err := s.sequenceService.AdvanceTo(tx, SequenceUserID, maxImported); err != nil {
+				return err
+			}
+		}
+
+		// 一次性为缺少工号的用户分配编号
+		var newUserIDs []string
+		if missing > 0 {
+			var err error
+			if newUserIDs, err = s.sequenceService.NextUserIDs(tx, missing); err != nil {
+				return err
+			}
+		}
+
+		for i := range rows {
+			row := &rows[i]
+			user := models.User{Status: 1}
+			if row.existing != nil {
+				user = *row.existing
+			}
+
+			user.Username = row.Username
+			setIfNotEmpty(&user.Name, row.Name)
+			setIfNotEmpty(&user.Email, row.Email)
+			setIfNotEmpty(&user.Phone, row.phone)
+			setIfNotEmpty(&user.Title, row.title)
+			setIfNotEmpty(&user.Password, hashed[i])
+			setIfNotEmpty(&user.UserID, row.UserID)
+			if row.groupID != nil {
+				user.GroupID = *row.groupID
+			}
+			if row.status != nil {
+				user.Status = *row.status
+			}
+			if user.UserID == "" {
+				user.UserID = newUserIDs[0]
+				newUserIDs = newUserIDs[1:]
+			}
+			row.UserID = user.UserID
+
+			var userRoles []models.Role
+			for _, code := range row.Roles {
+				userRoles = append(userRoles, roleByCode[code])
+			}
+			if row.hasRoles || row.existing == nil {
+				user.Access = AccessForRoles(userRoles)
+			}
+
+			if err := tx.Save(&user).Error; err != nil {
+				return fmt.Errorf("第 %d 行保存失败: %w", row.Row, err)
+			}
+			// status 带默认值，新建时零值不会写入
+			if row.existing == nil && user.Status == 0 {
+				if err := tx.Model(&user).Update("status", 0).Error; err != nil {
+					return fmt.Errorf("第 %d 行保存失败: %w", row.Row, err)
+				}
+			}
+			if row.hasRoles {
+				if err := replaceUserRoles(tx, &user, userRoles); err != nil {
+					return fmt.Errorf("第 %d 行%w", row.Row, err)
+				}
+			}
+		}
+
+		// 导入可能移除管理员角色或停用管理员，不能因此没有可用的管理员
+		admins, err := countAdmins(tx, 0)
+		if err != nil {
+			return err
+		}
+		if admins == 0 {
+			return fmt.Errorf("%w: 导入后没有启用的管理员", ErrLastAdmin)
+		}
+		return nil
+	})
+}
+
+// parseUserImportRows 根据表头解析数据行，跳过空行
+func parseUserImportRows(table [][]string) ([]UserImportRow, error) {
+	if len(table) == 0 {
+		return nil, fmt.Errorf("%w: 文件为空", ErrInvalidUserImport)
+	}
+
+	columns := make(map[string]int)
+	for i, header := range table[0] {
+		key := strings.ToLower(strings.TrimSpace(header))
+		if field, ok := userImportColumns[key]; ok {
+			columns[field] = i
+		}
+	}
+	if _, ok := columns["username"]; !ok {
+		return nil, fmt.Errorf("%w: 缺少用户名列", ErrInvalidUserImport)
+	}
+
+	cell := func(record []string, field string) string {
+		i, ok := columns[field]
+		if !ok || i >= len(record) {
+			return ""
+		}
+		return strings.TrimSpace(record[i])
+	}
+
+	var rows []UserImportRow
+	for n, record := range table[1:] {
+		if strings.TrimSpace(strings.Join(record, "")) == "" {
+			continue
+		}
+
+		row := UserImportRow{
+			Row:      n + 2,
+			Username: cell(record, "username"),
+			Name:     cell(record, "name"),
+			Email:    cell(record, "email"),
+			UserID:   cell(record, "userid"),
+			Roles:    []string{},
+			Errors:   []string{},
+			phone:    cell(record, "phone"),
+			password: cell(record, "password"),
+			title:    cell(record, "title"),
+		}
+
+		if row.Username == "" {
+			row.Errors = append(row.Errors, "用户名不能为空")
+		} else if len(row.Username) > 50 {
+			row.Errors = append(row.Errors, "用户名不能超过 50 个字符")
+		}
+		if row.Email != "" {
+			if _, err := mail.ParseAddress(row.Email); err != nil {
+				row.Errors = append(row.Errors, "邮箱格式错误")
+			}
+		}
+
+		if roles := cell(record, "roles"); roles != "" {
+			row.hasRoles = true
+			for _, code := range strings.FieldsFunc(roles, func(r rune) bool { return r == ';' || r == ',' || r == '，' || r == '；' }) {
+				if code = strings.TrimSpace(code); code != "" {
+					row.Roles = append(row.Roles, code)
+				}
+			}
+		}
+
+		switch status := cell(record, "status"); status {
+		case "":
+		case "1", "启用":
+			v := 1
+			row.status = &v
+		case "0", "禁用":
+			v := 0
+			row.status = &v
+		default:
+			row.Errors = append(row.Errors, "状态错误，必须是 1/启用 或 0/禁用")
+		}
+
+		if groupID := cell(record, "groupid"); groupID != "" {
+			v, err := strconv.ParseUint(groupID, 10, 32)
+			if err != nil {
+				row.Errors = append(row.Errors, "组织ID必须是数字")
+			} else {
+				id := uint(v)
+				row.groupID = &id
+			}
+		}
+
+		rows = append(rows, row)
+	}
+
+	if len(rows) > maxUserImportRows {
+		return nil, fmt.Errorf("%w: 数据行数超过 %d", ErrInvalidUserImport, maxUserImportRows)
+	}
+	return rows, nil
+}
+
+// writeUserImportReport 生成 CSV 结果报告，返回相对上传目录的路径
+func writeUserImportReport(result *UserImportResult) (string, error) {
+	reportPath := path.Join("imports", strings.ReplaceAll(utils.NewUUID(), "-", "")+".csv")
+	target := filepath.Join(config.AppConfig.UploadDir, filepath.FromSlash(reportPath))
+	if err := os.MkdirAll(filepath.Dir(target), 0755); err != nil {
+		return "", err
+	}
+	f, err := os.Create(target)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+
+	tw, err := newTableWriter(ExportFormatCSV, f)
+	if err != nil {
+		return "", err
+	}
+	if err := tw.WriteRow([]string{"行号", "用户名", "姓名", "邮箱", "工号", "角色", "操作", "结果"}); err != nil {
+		return "", err
+	}
+	for _, row := range result.Rows {
+		outcome := "校验通过"
+		switch {
+		case len(row.Errors) > 0:
+			outcome = strings.Join(row.Errors, "；")
+		case result.Imported:
+			outcome = "成功"
+		}
+		err := tw.WriteRow([]string{
+			strconv.Itoa(row.Row),
+			row.Username,
+			row.Name,
+			row.Email,
+			row.UserID,
+			strings.Join(row.Roles, ";"),
+			row.Action,
+			outcome,
+		})
+		if err != nil {
+			return "", err
+		}
+	}
+	if err := tw.Close(); err != nil {
+		return "", err
+	}
+	return reportPath, f.Close()
+}
+
+func setIfNotEmpty(field *string, value string) {
+	if value != "" {
+		*field = value
+	}
+}
+
+// WriteTemplate 写入导入模板（仅表头和一行示例）
+func (s *UserImportService) WriteTemplate(format string, w io.Writer) error {
+	tw, err := newTableWriter(format, w)
+	if err != nil {
+		return err
+	}
+	if err := tw.WriteRow(UserImportTemplateHeaders); err != nil {
+		return err
+	}
+	if err := tw.WriteRow([]string{"zhangsan", "张三", "zhangsan@example.com", "13800000000", "Passw0rd", "user", "启用", "", "", ""}); err != nil {
+		return err
+	}
+	return tw.Close()
+}
@@ -0,0 +1,104 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"learn-hub-backend/database"
+	"learn-hub-backend/models"
+	"learn-hub-backend/testutil"
+)
+
+func importUsers(t *testing.T, csv string) *UserImportResult {
+	t.Helper()
+	var service UserImportService
+	result, err := service.Import(context.Background(), 1, "users.csv", strings.NewReader(csv), false)
+	if err != nil {
+		t.Fatalf("导入失败: %v", err)
+	}
+	if !result.Imported {
+		t.Fatalf("导入未写入数据库: %+v", result.Rows)
+	}
+	return result
+}
+
+// 已有用户没有工号且文件中也未提供时，应为其分配工号
+func TestImportAssignsUserIDToExistingUserWithoutOne(t *testing.T) {
+	testutil.SetupDB(t)
+	bob := testutil.CreateUser(t, "bob")
+	if err := database.DB.Model(bob).Update("user_id", "").Error; err != nil {
+		t.Fatal(err)
+	}
+
+	result := importUsers(t, "username,name\nbob,Bobby")
+	if result.Updated != 1 {
+		t.Fatalf("更新 %d 个用户，期望 1 个", result.Updated)
+	}
+	var updated models.User
+	if err := database.DB.First(&updated, bob.ID).Error; err != nil {
+		t.Fatal(err)
+	}
+	if updated.Name != "Bobby" || updated.UserID != "00000002" {
+		t.Fatalf("更新后姓名 %q 工号 %q，期望 Bobby / 00000002", updated.Name, updated.UserID)
+	}
+}
+
+// 导入显式指定的工号后，自动分配的工号不能与之重复
+func TestImportExplicitUserIDAdvancesSequence(t *testing.T) {
+	testutil.SetupDB(t)
+	ctx := context.Background()
+
+	importUsers(t, "username,password,userid\ncarol,secret123,00000050\ndave,secret123,")
+
+	var dave models.User
+	if err := database.DB.Where("username = ?", "dave").First(&dave).Error; err != nil {
+		t.Fatal(err)
+	}
+	if dave.UserID != "00000051" {
+		t.Fatalf("同批次自动分配的工号为 %s，期望 00000051", dave.UserID)
+	}
+
+	var users UserService
+	erin, err := users.CreateUser(ctx, CreateUserInput{Username: "erin", Password: "secret123"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if erin.UserID != "00000052" {
+		t.Fatalf("导入后新建用户工号为 %s，期望 00000052", erin.UserID)
+	}
+
+	// 指定比计数器小的工号不会使计数器回退
+	importUsers(t, "username,password,userid\nfrank,secret123,00000010")
+	next, err := (&SequenceService{}).NextUserID(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if next != "00000053" {
+		t.Fatalf("下一个工号为 %s，期望 00000053", next)
+	}
+}
+
+// 导入不能移除最后一个管理员的管理员角色
+func TestImportKeepsLastAdmin(t *testing.T) {
+	testutil.SetupDB(t)
+	ctx := context.Background()
+	var service UserImportService
+
+	_, err := service.Import(ctx, 1, "users.csv", strings.NewReader("username,roles\nadmin,user"), false)
+	if !errors.Is(err, ErrLastAdmin) {
+		t.Fatalf("移除最后一个管理员的角色返回 %v，期望 ErrLastAdmin", err)
+	}
+	var admin models.User
+	if err := database.DB.Preload("Roles").Where("username = ?", "admin").First(&admin).Error; err != nil {
+		t.Fatal(err)
+	}
+	if AccessForRoles(admin.Roles) != AccessAdmin {
+		t.Fatalf("导入失败后 admin 的角色为 %+v，期望保留管理员角色", admin.Roles)
+	}
+
+	// 还有其他管理员时可以移除
+	testutil.CreateUser(t, "admin2", models.RoleCodeAdmin)
+	importUsers(t, "username,roles\nadmin,user")
+}
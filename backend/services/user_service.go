@@ -1,37 +1,282 @@
 package services
 
 import (
+	"context"
+	"errors"
+	"fmt"
+
 	"learn-hub-backend/database"
 	"learn-hub-backend/models"
 	"learn-hub-backend/utils"
+
+	"gorm.io/gorm"
+)
+
+var (
+	ErrUserExists   = errors.New("用户名已存在")
+	ErrUserNotFound = errors.New("用户不存在")
+	ErrRoleNotFound = errors.New("角色不存在")
+)
+
+// 用户访问级别
+const (
+	AccessAdmin = "admin"
+	AccessUser  = "user"
 )
 
 // UserService 用户服务
-type UserService struct{}
+type UserService struct {
+	sequenceService SequenceService
+}
 
 // GetByUsername 根据用户名获取用户
-func (s *UserService) GetByUsername(username string) (*models.User, error) {
+func (s *UserService) GetByUsername(ctx context.Context, username string) (*models.User, error) {
 	var user models.User
-	err := database.DB.Where("username = ?", username).Preload("Roles").First(&user).Error
+	err := database.WithContext(ctx).Where("username = ?", username).Preload("Roles").First(&user).Error
 	return &user, err
 }
 
 // GetByID 根据ID获取用户
-func (s *UserService) GetByID(id uint) (*models.User, error) {
+func (s *UserService) GetByID(ctx context.Context, id uint) (*models.User, error) {
 	var user models.User
-	err := database.DB.Preload("Roles").First(&user, id).Error
+	err := database.WithContext(ctx).Preload("Roles").First(&user, id).Error
 	return &user, err
 }
 
+// CurrentAccess 根据用户当前角色计算访问级别，禁用的用户视为普通用户
+// 令牌中的 access 是签发时的快照，角色变更后需以此为准
+func (s *UserService) CurrentAccess(ctx context.Context, userID uint) (string, error) {
+	user, err := s.GetByID(ctx, userID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return "", ErrUserNotFound
+	}
+	if err != nil {
+		return "", err
+	}
+	if user.Status != 1 {
+		return AccessUser, nil
+	}
+	return AccessForRoles(user.Roles), nil
+}
+
 // VerifyPassword 验证密码
 func (s *UserService) VerifyPassword(user *models.User, password string) bool {
 	return utils.CheckPassword(password, user.Password)
 }
 
+// CreateUserInput 创建用户参数
+type CreateUserInput struct {
+	Username string
+	Password string
+	Name     string
+	Email    string
+	Phone    string
+	Status   int // 0 表示使用默认值（启用）
+	RoleIDs  []uint
+}
+
+// UpdateUserInput 更新用户参数，空字符串和 nil 表示不修改
+type UpdateUserInput struct {
+	Name     string
+	Email    string
+	Phone    string
+	Password string
+	Status   *int
+	RoleIDs  []uint // nil 不修改角色，空切片清空角色
+}
+
+// CreateUser 创建用户并分配角色，工号分配、用户、角色关联和访问级别在同一事务中写入
+func (s *UserService) CreateUser(ctx context.Context, in CreateUserInput) (*models.User, error) {
+	// 密码加密较慢，放在事务外完成
+	hashedPassword, err := utils.HashPassword(in.Password)
+	if err != nil {
+		return nil, fmt.Errorf("密码加密失败: %w", err)
+	}
+
+	user := models.User{
+		Username: in.Username,
+		Password: hashedPassword,
+		Name:     in.Name,
+		Email:    in.Email,
+		Phone:    in.Phone,
+		Status:   in.Status,
+	}
+	if user.Status == 0 {
+		user.Status = 1
+	}
+
+	err = database.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var count int64
+		if err := tx.Model(&models.User{}).Where("username = ?", in.Username).Count(&count).Error; err != nil {
+			return err
+		}
+		if count > 0 {
+			return ErrUserExists
+		}
+
+		roles, err := findRoles(tx, in.RoleIDs)
+		if err != nil {
+			return err
+		}
+
+		if user.UserID, err = s.sequenceService.NextUserID(tx); err != nil {
+			return fmt.Errorf("生成工号失败: %w", err)
+		}
+		user.Access = AccessForRoles(roles)
+		if err := tx.Create(&user).Error; err != nil {
+			return err
+		}
+		return replaceUserRoles(tx, &user, roles)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &user, nil
+}
+
+// UpdateUser 更新用户信息；提供 RoleIDs 时替换角色并重新计算访问级别
+func (s *UserService) UpdateUser(ctx context.Context, id uint, in UpdateUserInput) (*models.User, error) {
+	var hashedPassword string
+	if in.Password != "" {
+		var err error
+		if hashedPassword, err = utils.HashPassword(in.Password); err != nil {
+			return nil, fmt.Errorf("密码加密失败: %w", err)
+		}
+	}
+
+	var user models.User
+	err := database.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Preload("Roles").First(&user, id).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return ErrUserNotFound
+			}
+			return err
+		}
+
+		if in.Name != "" {
+			user.Name = in.Name
+		}
+		if in.Email != "" {
+			user.Email = in.Email
+		}
+		if in.Phone != "" {
+			user.Phone = in.Phone
+		}
+		if hashedPassword != "" {
+			user.Password = hashedPassword
+		}
+		if in.Status != nil {
+			user.Status = *in.Status
+		}
+
+		if in.RoleIDs != nil {
+			roles, err := findRoles(tx, in.RoleIDs)
+			if err != nil {
+				return err
+			}
+			user.Access = AccessForRoles(roles)
+			if err := replaceUserRoles(tx, &user, roles); err != nil {
+				return err
+			}
+		}
+
+		return tx.Omit("Roles").Save(&user).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &user, nil
+}
+
+// GrantRole 为用户追加角色（按角色代码），已拥有该角色时不做修改，并重新计算访问级别
+func (s *UserService) GrantRole(ctx context.Context, id uint, roleCode string) (*models.User, error) {
+	var user models.User
+	err := database.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Preload("Roles").First(&user, id).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return ErrUserNotFound
+			}
+			return err
+		}
+
+		var role models.Role
+		if err := tx.Where("code = ?", roleCode).First(&role).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return ErrRoleNotFound
+			}
+			return err
+		}
+		for _, r := range user.Roles {
+			if r.ID == role.ID {
+				return nil
+			}
+		}
+
+		if err := tx.Model(&user).Association("Roles").Append(&role); err != nil {
+			return fmt.Errorf("分配角色失败: %w", err)
+		}
+		user.Access = AccessForRoles(user.Roles)
+		return tx.Model(&models.User{}).Where("id = ?", user.ID).Update("access", user.Access).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &user, nil
+}
+
+// AccessForRoles 根据角色计算用户访问级别：拥有 admin 角色为 admin，否则为 user
+// 用户的 Access 字段只应由此函数根据角色得出
+func AccessForRoles(roles []models.Role) string {
+	for _, role := range roles {
+		if role.Code == models.RoleCodeAdmin {
+			return AccessAdmin
+		}
+	}
+	return AccessUser
+}
+
+// SyncUserAccess 根据用户当前角色重新计算并保存访问级别
+func SyncUserAccess(tx *gorm.DB, userID uint) error {
+	var roles []models.Role
+	if err := tx.Model(&models.User{ID: userID}).Association("Roles").Find(&roles); err != nil {
+		return err
+	}
+	return tx.Model(&models.User{}).Where("id = ?", userID).Update("access", AccessForRoles(roles)).Error
+}
+
+// findRoles 根据ID查询角色，任一ID不存在时返回 ErrRoleNotFound
+func findRoles(tx *gorm.DB, ids []uint) ([]models.Role, error) {
+	if len(ids) == 0 {
+		return nil, nil
+	}
+
+	unique := make(map[uint]bool, len(ids))
+	for _, id := range ids {
+		unique[id] = true
+	}
+
+	var roles []models.Role
+	if err := tx.Where("id IN ?", ids).Find(&roles).Error; err != nil {
+		return nil, err
+	}
+	if len(roles) != len(unique) {
+		return nil, ErrRoleNotFound
+	}
+	return roles, nil
+}
+
+// replaceUserRoles 替换用户角色关联
+func replaceUserRoles(tx *gorm.DB, user *models.User, roles []models.Role) error {
+	if err := tx.Model(user).Association("Roles").Replace(roles); err != nil {
+		return fmt.Errorf("分配角色失败: %w", err)
+	}
+	return nil
+}
+
 // GetUserMenus 获取用户菜单列表
-func (s *UserService) GetUserMenus(userID uint) ([]models.MenuVO, error) {
+func (s *UserService) GetUserMenus(ctx context.Context, userID uint) ([]models.MenuVO, error) {
 	var user models.User
-	if err := database.DB.Preload("Roles.Menus").First(&user, userID).Error; err != nil {
+	if err := database.WithContext(ctx).Preload("Roles.Menus").First(&user, userID).Error; err != nil {
 		return nil, err
 	}
 
@@ -95,4 +340,3 @@ func buildMenuTree(menus []models.Menu, parentID uint) []models.MenuVO {
 
 	return tree
 }
-
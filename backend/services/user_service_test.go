@@ -0,0 +1,47 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"learn-hub-backend/testutil"
+)
+
+func TestCreateUserAllocatesSequentialUserIDs(t *testing.T) {
+	testutil.SetupDB(t)
+	ctx := context.Background()
+	var service UserService
+
+	// 种子数据中的管理员工号为 00000001
+	for i, want := range []string{"00000002", "00000003"} {
+		user, err := service.CreateUser(ctx, CreateUserInput{Username: []string{"alice", "bob"}[i], Password: "secret123"})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if user.UserID != want {
+			t.Fatalf("工号为 %s，期望 %s", user.UserID, want)
+		}
+		if user.Status != 1 {
+			t.Fatalf("默认状态为 %d，期望启用", user.Status)
+		}
+	}
+
+	if _, err := service.CreateUser(ctx, CreateUserInput{Username: "alice", Password: "secret123"}); !errors.Is(err, ErrUserExists) {
+		t.Fatalf("重复用户名返回 %v，期望 ErrUserExists", err)
+	}
+}
+
+func TestCreateUserUnknownRole(t *testing.T) {
+	testutil.SetupDB(t)
+	ctx := context.Background()
+	var service UserService
+
+	_, err := service.CreateUser(ctx, CreateUserInput{Username: "alice", Password: "secret123", RoleIDs: []uint{999}})
+	if !errors.Is(err, ErrRoleNotFound) {
+		t.Fatalf("角色不存在时返回 %v，期望 ErrRoleNotFound", err)
+	}
+	if _, err := service.GetByUsername(ctx, "alice"); err == nil {
+		t.Fatal("创建失败时不应写入用户")
+	}
+}
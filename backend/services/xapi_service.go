@@ -0,0 +1,407 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"reflect"
+	"strings"
+	"time"
+
+	"learn-hub-backend/database"
+	"learn-hub-backend/models"
+	"learn-hub-backend/utils"
+
+	"gorm.io/gorm"
+)
+
+// XAPIVersion LRS 支持的 xAPI 版本
+const XAPIVersion = "1.0.3"
+
+var (
+	// ErrXAPIInvalidStatement 语句格式不合法
+	ErrXAPIInvalidStatement = errors.New("xAPI 语句不合法")
+	// ErrXAPIConflict 语句ID已存在且内容不同
+	ErrXAPIConflict = errors.New("语句ID已存在且内容不同")
+	// ErrXAPIUnauthorized 客户端凭证无效
+	ErrXAPIUnauthorized = errors.New("xAPI 客户端凭证无效")
+)
+
+// XAPIService xAPI 学习记录存储（LRS）服务
+type XAPIService struct{}
+
+// XAPIAgent xAPI 参与者
+type XAPIAgent struct {
+	ObjectType  string `json:"objectType,omitempty"`
+	Name        string `json:"name,omitempty"`
+	Mbox        string `json:"mbox,omitempty"`
+	MboxSHA1Sum string `json:"mbox_sha1sum,omitempty"`
+	OpenID      string `json:"openid,omitempty"`
+	Account     *struct {
+		HomePage string `json:"homePage"`
+		Name     string `json:"name"`
+	} `json:"account,omitempty"`
+}
+
+// Key 返回参与者的唯一标识，用于存储和查询
+func (a XAPIAgent) Key() string {
+	switch {
+	case a.Mbox != "":
+		return "mbox:" + strings.ToLower(a.Mbox)
+	case a.MboxSHA1Sum != "":
+		return "mbox_sha1sum:" + strings.ToLower(a.MboxSHA1Sum)
+	case a.OpenID != "":
+		return "openid:" + a.OpenID
+	case a.Account != nil && a.Account.Name != "":
+		return "account:" + a.Account.HomePage + "|" + a.Account.Name
+	}
+	return ""
+}
+
+// xapiStatement 语句中用于校验、索引和完成映射的字段
+type xapiStatement struct {
+	ID    string    `json:"id"`
+	Actor XAPIAgent `json:"actor"`
+	Verb  struct {
+		ID string `json:"id"`
+	} `json:"verb"`
+	Object struct {
+		ObjectType string `json:"objectType"`
+		ID         string `json:"id"`
+	} `json:"object"`
+	Result *struct {
+		Success    *bool `json:"success"`
+		Completion *bool `json:"completion"`
+	} `json:"result"`
+	Context *struct {
+		Registration string `json:"registration"`
+	} `json:"context"`
+	Timestamp string `json:"timestamp"`
+}
+
+// XAPIStatementFilter 语句查询条件（对应 xAPI 标准查询参数）
+type XAPIStatementFilter struct {
+	Agent        string
+	VerbID       string
+	ActivityID   string
+	Registration string
+	Since        *time.Time
+	Until        *time.Time
+	Ascending    bool
+	Offset       int
+	Limit        int
+}
+
+// Authenticate 校验客户端 Basic 认证凭证
+func (s *XAPIService) Authenticate(ctx context.Context, key, secret string) (*models.XAPIClient, error) {
+	var client models.XAPIClient
+	if err := database.WithContext(ctx).Where("client_key = ? AND status = ?", key, 1).First(&client).Error; err != nil {
+		return nil, ErrXAPIUnauthorized
+	}
+	if !utils.CheckPassword(secret, client.Secret) {
+		return nil, ErrXAPIUnauthorized
+	}
+	return &client, nil
+}
+
+// CreateClient 创建客户端，返回只展示一次的明文密钥
+func (s *XAPIService) CreateClient(ctx context.Context, name string, canRead, canWrite bool) (*models.XAPIClient, string, error) {
+	secret := strings.ReplaceAll(utils.NewUUID(), "-", "")
+	hashed, err := utils.HashPassword(secret)
+	if err != nil {
+		return nil, "", err
+	}
+
+	client := models.XAPIClient{
+		Name:     name,
+		Key:      strings.ReplaceAll(utils.NewUUID(), "-", ""),
+		Secret:   hashed,
+		Status:   1,
+		CanRead:  canRead,
+		CanWrite: canWrite,
+	}
+	if err := database.WithContext(ctx).Create(&client).Error; err != nil {
+		return nil, "", err
+	}
+	return &client, secret, nil
+}
+
+// StoreStatements 批量保存语句（POST），返回语句ID列表；任一语句不合法则全部不保存
+func (s *XAPIService) StoreStatements(ctx context.Context, client *models.XAPIClient, raws []json.RawMessage) ([]string, error) {
+	var ids []string
+	var stored []*models.XAPIStatement
+
+	err := database.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		for _, raw := range raws {
+			statement, created, err := s.store(tx, client, raw, "")
+			if err != nil {
+				return err
+			}
+			ids = append(ids, statement.StatementID)
+			if created {
+				stored = append(stored, statement)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	for _, statement := range stored {
+		s.applyCompletion(ctx, statement)
+	}
+	return ids, nil
+}
+
+// PutStatement 以指定ID保存语句（PUT）；已存在且内容一致时视为成功
+func (s *XAPIService) PutStatement(ctx context.Context, client *models.XAPIClient, statementID string, raw json.RawMessage) error {
+	if !utils.IsUUID(statementID) {
+		return ErrXAPIInvalidStatement
+	}
+
+	var statement *models.XAPIStatement
+	var created bool
+	err := database.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var err error
+		statement, created, err = s.store(tx, client, raw, statementID)
+		return err
+	})
+	if err != nil {
+		return err
+	}
+
+	if created {
+		s.applyCompletion(ctx, statement)
+	}
+	return nil
+}
+
+// GetStatement 按ID获取单条语句；voided 为 true 时只返回已作废的语句
+func (s *XAPIService) GetStatement(ctx context.Context, statementID string, voided bool) (*models.XAPIStatement, error) {
+	var statement models.XAPIStatement
+	err := database.WithContext(ctx).Where("statement_id = ? AND voided = ?", statementID, voided).First(&statement).Error
+	if err != nil {
+		return nil, err
+	}
+	return &statement, nil
+}
+
+// QueryStatements 按条件查询未作废的语句，more 表示是否还有更多结果
+func (s *XAPIService) QueryStatements(ctx context.Context, filter XAPIStatementFilter) ([]models.XAPIStatement, bool, error) {
+	query := database.WithContext(ctx).Model(&models.XAPIStatement{}).Where("voided = ?", false)
+
+	if filter.Agent != "" {
+		var agent XAPIAgent
+		if err := json.Unmarshal([]byte(filter.Agent), &agent); err != nil || agent.Key() == "" {
+			return nil, false, ErrXAPIInvalidStatement
+		}
+		query = query.Where("actor_key = ?", agent.Key())
+	}
+	if filter.VerbID != "" {
+		query = query.Where("verb_id = ?", filter.VerbID)
+	}
+	if filter.ActivityID != "" {
+		query = query.Where("activity_id = ?", filter.ActivityID)
+	}
+	if filter.Registration != "" {
+		query = query.Where("registration = ?", filter.Registration)
+	}
+	if filter.Since != nil {
+		query = query.Where("stored > ?", *filter.Since)
+	}
+	if filter.Until != nil {
+		query = query.Where("stored <= ?", *filter.Until)
+	}
+
+	order := "stored DESC, id DESC"
+	if filter.Ascending {
+		order = "stored ASC, id ASC"
+	}
+
+	// 多取一条用于判断是否还有下一页
+	var statements []models.XAPIStatement
+	if err := query.Order(order).Offset(filter.Offset).Limit(filter.Limit + 1).Find(&statements).Error; err != nil {
+		return nil, false, err
+	}
+
+	more := len(statements) > filter.Limit
+	if more {
+		statements = statements[:filter.Limit]
+	}
+	return statements, more, nil
+}
+
+// store 校验并保存一条语句；fixedID 非空时为 PUT 请求指定的ID，created 为 false 表示语句已存在
+func (s *XAPIService) store(tx *gorm.DB, client *models.XAPIClient, raw json.RawMessage, fixedID string) (statement *models.XAPIStatement, created bool, err error) {
+	var parsed xapiStatement
+	if err = json.Unmarshal(raw, &parsed); err != nil {
+		return nil, false, ErrXAPIInvalidStatement
+	}
+	var doc map[string]interface{}
+	if err := json.Unmarshal(raw, &doc); err != nil {
+		return nil, false, ErrXAPIInvalidStatement
+	}
+
+	if parsed.Actor.Key() == "" || parsed.Verb.ID == "" {
+		return nil, false, ErrXAPIInvalidStatement
+	}
+	if parsed.Object.ID == "" {
+		return nil, false, ErrXAPIInvalidStatement
+	}
+
+	// 确定语句ID
+	if fixedID != "" {
+		if parsed.ID != "" && !strings.EqualFold(parsed.ID, fixedID) {
+			return nil, false, ErrXAPIInvalidStatement
+		}
+		parsed.ID = fixedID
+	}
+	if parsed.ID == "" {
+		parsed.ID = utils.NewUUID()
+	} else if !utils.IsUUID(parsed.ID) {
+		return nil, false, ErrXAPIInvalidStatement
+	}
+	parsed.ID = strings.ToLower(parsed.ID)
+
+	// 已存在同ID语句：内容一致视为幂等写入，否则冲突
+	var existing models.XAPIStatement
+	if err := tx.Where("statement_id = ?", parsed.ID).First(&existing).Error; err == nil {
+		if sameStatement(existing.Raw, doc) {
+			return &existing, false, nil
+		}
+		return nil, false, ErrXAPIConflict
+	}
+
+	now := time.Now()
+	timestamp := now
+	if parsed.Timestamp != "" {
+		t, err := time.Parse(time.RFC3339Nano, parsed.Timestamp)
+		if err != nil {
+			return nil, false, ErrXAPIInvalidStatement
+		}
+		timestamp = t
+	}
+
+	// 补全 LRS 负责填写的属性
+	doc["id"] = parsed.ID
+	doc["stored"] = now.UTC().Format(time.RFC3339Nano)
+	if _, ok := doc["timestamp"]; !ok {
+		doc["timestamp"] = doc["stored"]
+	}
+	if _, ok := doc["version"]; !ok {
+		doc["version"] = XAPIVersion
+	}
+	doc["authority"] = map[string]interface{}{
+		"objectType": "Agent",
+		"name":       client.Name,
+		"account": map[string]interface{}{
+			"homePage": "learn-hub",
+			"name":     client.Key,
+		},
+	}
+
+	body, err := json.Marshal(doc)
+	if err != nil {
+		return nil, false, err
+	}
+
+	statement = &models.XAPIStatement{
+		StatementID: parsed.ID,
+		ClientID:    client.ID,
+		ActorKey:    parsed.Actor.Key(),
+		UserID:      s.matchUser(tx, parsed.Actor),
+		VerbID:      parsed.Verb.ID,
+		ActivityID:  parsed.Object.ID,
+		Timestamp:   timestamp,
+		Stored:      now,
+		Raw:         string(body),
+	}
+	if parsed.Context != nil {
+		statement.Registration = strings.ToLower(parsed.Context.Registration)
+	}
+
+	// 作废语句：对象为被作废语句的ID
+	if parsed.Verb.ID == models.XAPIVerbVoided {
+		if parsed.Object.ObjectType != "StatementRef" {
+			return nil, false, ErrXAPIInvalidStatement
+		}
+		result := tx.Model(&models.XAPIStatement{}).
+			Where("statement_id = ? AND verb_id <> ?", strings.ToLower(parsed.Object.ID), models.XAPIVerbVoided).
+			Update("voided", true)
+		if result.Error != nil {
+			return nil, false, result.Error
+		}
+	}
+
+	if err := tx.Create(statement).Error; err != nil {
+		return nil, false, err
+	}
+	return statement, true, nil
+}
+
+// matchUser 根据参与者的 mbox 或 account.name 匹配系统用户
+func (s *XAPIService) matchUser(tx *gorm.DB, agent XAPIAgent) uint {
+	var user models.User
+	switch {
+	case strings.HasPrefix(agent.Mbox, "mailto:"):
+		email := strings.TrimPrefix(agent.Mbox, "mailto:")
+		if tx.Where("email = ?", email).First(&user).Error == nil {
+			return user.ID
+		}
+	case agent.Account != nil && agent.Account.Name != "":
+		if tx.Where("username = ?", agent.Account.Name).First(&user).Error == nil {
+			return user.ID
+		}
+	}
+	return 0
+}
+
+// applyCompletion 将已知活动上的 completed/passed 语句映射为课程完成
+func (s *XAPIService) applyCompletion(ctx context.Context, statement *models.XAPIStatement) {
+	if statement.UserID == 0 {
+		return
+	}
+	if statement.VerbID != models.XAPIVerbCompleted && statement.VerbID != models.XAPIVerbPassed {
+		return
+	}
+
+	var parsed xapiStatement
+	if err := json.Unmarshal([]byte(statement.Raw), &parsed); err != nil {
+		return
+	}
+	if parsed.Result != nil && parsed.Result.Success != nil && !*parsed.Result.Success {
+		return
+	}
+
+	var activity models.XAPIActivity
+	if err := database.WithContext(ctx).Where("activity_id = ?", statement.ActivityID).First(&activity).Error; err != nil {
+		return
+	}
+
+	if err := (&CourseRecordService{}).MarkCompleted(ctx, statement.UserID, activity.CourseID, statement.Timestamp); err != nil {
+		slog.Error("xAPI 语句回写学习记录失败", "statement_id", statement.StatementID, "error", err)
+	}
+}
+
+// sameStatement 比较已保存的语句与新提交的语句是否一致（忽略 LRS 填写的属性）
+func sameStatement(storedRaw string, doc map[string]interface{}) bool {
+	var stored map[string]interface{}
+	if err := json.Unmarshal([]byte(storedRaw), &stored); err != nil {
+		return false
+	}
+
+	candidate := make(map[string]interface{}, len(doc))
+	for k, v := range doc {
+		candidate[k] = v
+	}
+	if _, ok := candidate["timestamp"]; !ok {
+		delete(stored, "timestamp")
+	}
+	for _, key := range []string{"id", "stored", "authority", "version"} {
+		delete(stored, key)
+		delete(candidate, key)
+	}
+	return reflect.DeepEqual(stored, candidate)
+}
@@ -0,0 +1,106 @@
+// Package testutil 测试辅助函数：在 SQLite 内存数据库上执行全部迁移，并提供常用的测试数据
+package testutil
+
+import (
+	"testing"
+
+	"learn-hub-backend/config"
+	"learn-hub-backend/database"
+	"learn-hub-backend/models"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// AdminPassword 种子数据中默认管理员（admin）的密码，测试用户使用相同的密码哈希
+const AdminPassword = "admin123"
+
+// adminPasswordHash AdminPassword 的 bcrypt 哈希，与种子数据一致，避免每个测试用户都计算一次哈希
+const adminPasswordHash = "$2a$10$lZiuaxzQSb.5cuKSbV/F1.5dawptkLSm3p42zwEfY4wWuJDlm.qj2"
+
+// SetupDB 打开新的 SQLite 内存数据库（file::memory:）并执行全部迁移，替换全局配置和 database.DB，测试结束时关闭
+// 内存数据库的每个连接都是独立的数据库，因此与 SQLite 生产配置一样只使用一个连接
+func SetupDB(t testing.TB) *gorm.DB {
+	t.Helper()
+
+	cfg := config.Default()
+	cfg.AppEnv = config.EnvDev
+	cfg.DBDriver = database.DriverSQLite
+	cfg.DBPath = "file::memory:"
+	cfg.UploadDir = t.TempDir()
+	cfg.MetricsEnabled = false
+	config.AppConfig = cfg
+
+	db := Open(t)
+	database.DB = db
+	if _, err := database.Migrate(); err != nil {
+		t.Fatalf("执行迁移失败: %v", err)
+	}
+	return db
+}
+
+// Open 打开新的 SQLite 内存数据库但不执行迁移，用于测试迁移本身
+func Open(t testing.TB) *gorm.DB {
+	t.Helper()
+
+	dialector, err := database.Dialector(&config.Config{DBDriver: database.DriverSQLite, DBPath: "file::memory:"})
+	if err != nil {
+		t.Fatalf("创建数据库驱动失败: %v", err)
+	}
+	db, err := gorm.Open(dialector, &gorm.Config{Logger: logger.Discard})
+	if err != nil {
+		t.Fatalf("打开数据库失败: %v", err)
+	}
+	sqlDB, err := db.DB()
+	if err != nil {
+		t.Fatalf("获取连接池失败: %v", err)
+	}
+	sqlDB.SetMaxOpenConns(1)
+	sqlDB.SetConnMaxLifetime(0)
+	sqlDB.SetConnMaxIdleTime(0)
+
+	t.Cleanup(func() {
+		sqlDB.Close()
+		if database.DB == db {
+			database.DB = nil
+		}
+	})
+	return db
+}
+
+// CreateUser 创建启用状态的用户并分配指定角色（按角色代码），密码为 AdminPassword
+func CreateUser(t testing.TB, username string, roleCodes ...string) *models.User {
+	t.Helper()
+
+	user := models.User{Username: username, Password: adminPasswordHash, Name: username, UserID: "T-" + username, Status: 1}
+	if len(roleCodes) > 0 {
+		if err := database.DB.Where("code IN ?", roleCodes).Find(&user.Roles).Error; err != nil {
+			t.Fatalf("查询角色失败: %v", err)
+		}
+		if len(user.Roles) != len(roleCodes) {
+			t.Fatalf("角色不存在: %v", roleCodes)
+		}
+		user.Access = roleCodes[0]
+	}
+	if err := database.DB.Create(&user).Error; err != nil {
+		t.Fatalf("创建用户 %s 失败: %v", username, err)
+	}
+	return &user
+}
+
+// CreateCourse 创建草稿状态的视频课程，ownerID 为 0 时只有管理员可管理
+func CreateCourse(t testing.TB, title string, ownerID uint) *models.Course {
+	t.Helper()
+
+	course := models.Course{
+		Title:       title,
+		ContentType: models.ContentTypeVideo,
+		VideoURL:    "https://example.com/" + title + ".mp4",
+		Status:      models.CourseStatusDraft,
+		OwnerID:     ownerID,
+	}
+	if err := database.DB.Create(&course).Error; err != nil {
+		t.Fatalf("创建课程 %s 失败: %v", title, err)
+	}
+	return &course
+}
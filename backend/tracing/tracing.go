@@ -0,0 +1,93 @@
+package tracing
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"path"
+
+	"learn-hub-backend/config"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.21.0"
+)
+
+// ServiceName 上报链路时使用的服务名，可通过环境变量 OTEL_SERVICE_NAME 覆盖
+const ServiceName = "learn-hub-backend"
+
+// 链路导出方式
+const (
+	ExporterNone   = "none"
+	ExporterOTLP   = "otlp"
+	ExporterStdout = "stdout"
+)
+
+// stdoutWriter stdout 导出方式的输出目标，测试中替换以检查导出的 span
+var stdoutWriter io.Writer = os.Stdout
+
+// Setup 按配置初始化全局 TracerProvider 和 W3C trace context 传播，返回退出时刷新并关闭导出器的函数
+// 导出方式为 none 时不记录链路，但仍然透传请求中的 traceparent
+func Setup(ctx context.Context, cfg *config.Config) (func(context.Context) error, error) {
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(propagation.TraceContext{}, propagation.Baggage{}))
+
+	var exporter sdktrace.SpanExporter
+	var err error
+	switch cfg.TracingExporter {
+	case ExporterNone:
+		return func(context.Context) error { return nil }, nil
+	case ExporterOTLP:
+		exporter, err = newOTLPExporter(ctx, cfg.OTLPEndpoint)
+	case ExporterStdout:
+		exporter, err = stdouttrace.New(stdouttrace.WithWriter(stdoutWriter))
+	default:
+		err = fmt.Errorf("不支持的链路导出方式: %s", cfg.TracingExporter)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	res, err := resource.New(ctx,
+		resource.WithAttributes(semconv.ServiceName(ServiceName), semconv.DeploymentEnvironment(cfg.AppEnv)),
+		resource.WithTelemetrySDK(),
+		resource.WithFromEnv(),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	provider := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithResource(res),
+		// 上游已决定采样的请求沿用其决定，其余按比例采样
+		sdktrace.WithSampler(sdktrace.ParentBased(sdktrace.TraceIDRatioBased(cfg.TracingSampleRatio))),
+	)
+	otel.SetTracerProvider(provider)
+	return provider.Shutdown, nil
+}
+
+// newOTLPExporter 创建 OTLP/HTTP 导出器，endpoint 为 collector 地址（如 http://otel-collector:4318），链路上报到其下的 /v1/traces
+// endpoint 为空时使用 OTEL_EXPORTER_OTLP_* 环境变量或默认地址 localhost:4318
+func newOTLPExporter(ctx context.Context, endpoint string) (sdktrace.SpanExporter, error) {
+	var opts []otlptracehttp.Option
+	if endpoint != "" {
+		u, err := url.Parse(endpoint)
+		if err != nil {
+			return nil, err
+		}
+		opts = append(opts,
+			otlptracehttp.WithEndpoint(u.Host),
+			otlptracehttp.WithURLPath(path.Join("/", u.Path, "v1/traces")),
+		)
+		if u.Scheme == "http" {
+			opts = append(opts, otlptracehttp.WithInsecure())
+		}
+	}
+	return otlptracehttp.New(ctx, opts...)
+}
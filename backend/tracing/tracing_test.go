@@ -0,0 +1,140 @@
+package tracing
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+
+	"learn-hub-backend/config"
+
+	"github.com/glebarez/sqlite"
+	"go.opentelemetry.io/otel"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// exportedSpan stdout 导出器输出的 span（只解析测试用到的字段）
+type exportedSpan struct {
+	Name        string
+	SpanContext struct{ TraceID, SpanID string }
+	Parent      struct{ TraceID, SpanID string }
+	Attributes  []struct {
+		Key   string
+		Value struct{ Value interface{} }
+	}
+}
+
+func (s exportedSpan) attribute(key string) interface{} {
+	for _, attr := range s.Attributes {
+		if attr.Key == key {
+			return attr.Value.Value
+		}
+	}
+	return nil
+}
+
+// setupStdout 使用 stdout 导出方式初始化链路，返回刷新导出器并解析输出 span 的函数
+func setupStdout(t *testing.T) func() []exportedSpan {
+	t.Helper()
+	var buf bytes.Buffer
+	previousWriter, previousProvider := stdoutWriter, otel.GetTracerProvider()
+	stdoutWriter = &buf
+	t.Cleanup(func() {
+		stdoutWriter = previousWriter
+		otel.SetTracerProvider(previousProvider)
+	})
+
+	cfg := config.Default()
+	cfg.TracingExporter = ExporterStdout
+	shutdown, err := Setup(context.Background(), cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return func() []exportedSpan {
+		t.Helper()
+		if err := shutdown(context.Background()); err != nil {
+			t.Fatal(err)
+		}
+		var spans []exportedSpan
+		dec := json.NewDecoder(&buf)
+		for {
+			var span exportedSpan
+			err := dec.Decode(&span)
+			if errors.Is(err, io.EOF) {
+				return spans
+			}
+			if err != nil {
+				t.Fatalf("解析导出的 span 失败: %v\n%s", err, buf.String())
+			}
+			spans = append(spans, span)
+		}
+	}
+}
+
+func TestStdoutExporterRecordsSQLSpans(t *testing.T) {
+	flush := setupStdout(t)
+
+	db, err := gorm.Open(sqlite.Open("file::memory:"), &gorm.Config{Logger: logger.Discard})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := InstrumentDB(db); err != nil {
+		t.Fatal(err)
+	}
+	type item struct {
+		ID   uint
+		Name string
+	}
+	if err := db.AutoMigrate(&item{}); err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, parent := otel.Tracer("test").Start(context.Background(), "request")
+	if err := db.WithContext(ctx).Create(&item{Name: "a"}).Error; err != nil {
+		t.Fatal(err)
+	}
+	var items []item
+	// 子查询只生成 SQL，不单独记录 span
+	ids := db.WithContext(ctx).Model(&item{}).Select("id")
+	if err := db.WithContext(ctx).Where("name = ? AND id IN (?)", "secret-value", ids).Find(&items).Error; err != nil {
+		t.Fatal(err)
+	}
+	// 没有父 span 的语句不记录
+	if err := db.Find(&items).Error; err != nil {
+		t.Fatal(err)
+	}
+	parent.End()
+
+	spans := flush()
+	var sqlSpans []exportedSpan
+	for _, span := range spans {
+		if strings.HasPrefix(span.Name, "gorm.") {
+			sqlSpans = append(sqlSpans, span)
+		}
+	}
+	if len(sqlSpans) != 2 {
+		t.Fatalf("记录了 %d 个 SQL span，期望 2 个（不含子查询和没有父 span 的语句）: %+v", len(sqlSpans), spans)
+	}
+
+	parentID := parent.SpanContext().SpanID().String()
+	for _, span := range sqlSpans {
+		if span.Parent.SpanID != parentID {
+			t.Errorf("%s 的父 span 为 %s，期望 %s", span.Name, span.Parent.SpanID, parentID)
+		}
+		if span.attribute("db.system") != "sqlite" {
+			t.Errorf("%s 的 db.system 为 %v", span.Name, span.attribute("db.system"))
+		}
+	}
+	if sqlSpans[0].Name != "gorm.create items" || sqlSpans[1].Name != "gorm.query items" {
+		t.Fatalf("SQL span 名称为 %q、%q", sqlSpans[0].Name, sqlSpans[1].Name)
+	}
+	statement, _ := sqlSpans[1].attribute("db.statement").(string)
+	if !strings.Contains(statement, "name = ?") || strings.Contains(statement, "secret-value") {
+		t.Fatalf("db.statement 应为带占位符的 SQL: %q", statement)
+	}
+}
@@ -0,0 +1,68 @@
+package utils
+
+import "net/http"
+
+// ErrorCode 错误码：errorCode 字段的取值及对应的 HTTP 状态码
+type ErrorCode struct {
+	Code        string
+	Status      int
+	Description string
+	Business    bool // 业务错误：兼容模式（api_compat）下以 HTTP 200 返回，与旧版接口一致
+}
+
+// 通用错误
+var (
+	CodeBadRequest       = ErrorCode{Code: "BAD_REQUEST", Status: http.StatusBadRequest, Description: "请求参数错误"}
+	CodeUnauthorized     = ErrorCode{Code: "UNAUTHORIZED", Status: http.StatusUnauthorized, Description: "未登录、token 无效或已过期"}
+	CodeForbidden        = ErrorCode{Code: "FORBIDDEN", Status: http.StatusForbidden, Description: "没有操作权限"}
+	CodeNotFound         = ErrorCode{Code: "NOT_FOUND", Status: http.StatusNotFound, Description: "接口不存在"}
+	CodeMethodNotAllowed = ErrorCode{Code: "METHOD_NOT_ALLOWED", Status: http.StatusMethodNotAllowed, Description: "接口不支持该请求方法"}
+	CodeConflict         = ErrorCode{Code: "CONFLICT", Status: http.StatusConflict, Description: "xAPI 语句 ID 已存在且内容不同"}
+	CodeInternal         = ErrorCode{Code: "INTERNAL_ERROR", Status: http.StatusInternalServerError, Description: "服务器内部错误"}
+)
+
+// 认证和用户
+var (
+	CodeInvalidCredentials = ErrorCode{Code: "INVALID_CREDENTIALS", Status: http.StatusUnauthorized, Description: "用户名或密码错误", Business: true}
+	CodeUserDisabled       = ErrorCode{Code: "USER_DISABLED", Status: http.StatusForbidden, Description: "用户已禁用", Business: true}
+	CodeUserNotFound       = ErrorCode{Code: "USER_NOT_FOUND", Status: http.StatusNotFound, Description: "用户不存在", Business: true}
+	CodeUserExists         = ErrorCode{Code: "USER_EXISTS", Status: http.StatusConflict, Description: "用户名已存在", Business: true}
+	CodeRoleNotFound       = ErrorCode{Code: "ROLE_NOT_FOUND", Status: http.StatusNotFound, Description: "角色不存在", Business: true}
+	CodeRoleExists         = ErrorCode{Code: "ROLE_EXISTS", Status: http.StatusConflict, Description: "角色编码已存在", Business: true}
+	CodeMenuNotFound       = ErrorCode{Code: "MENU_NOT_FOUND", Status: http.StatusNotFound, Description: "菜单不存在", Business: true}
+	CodeRuleNotFound       = ErrorCode{Code: "RULE_NOT_FOUND", Status: http.StatusNotFound, Description: "规则不存在", Business: true}
+)
+
+// 删除和回收站
+var (
+	CodeInUse           = ErrorCode{Code: "IN_USE", Status: http.StatusConflict, Description: "存在关联数据，需指定 policy 为 cascade 或 reassign", Business: true}
+	CodeProtected       = ErrorCode{Code: "PROTECTED", Status: http.StatusForbidden, Description: "内置管理员角色、最后一个管理员或当前登录用户不能删除", Business: true}
+	CodeRecycleNotFound = ErrorCode{Code: "RECYCLE_NOT_FOUND", Status: http.StatusNotFound, Description: "回收站中不存在该数据", Business: true}
+	CodeRestoreConflict = ErrorCode{Code: "RESTORE_CONFLICT", Status: http.StatusConflict, Description: "唯一字段与现有数据冲突，data.conflicts 为冲突字段，可在请求中提供新值", Business: true}
+)
+
+// 课程
+var (
+	CodeCourseNotFound        = ErrorCode{Code: "COURSE_NOT_FOUND", Status: http.StatusNotFound, Description: "课程不存在或已下架", Business: true}
+	CodeInvalidCourseStatus   = ErrorCode{Code: "INVALID_COURSE_STATUS", Status: http.StatusConflict, Description: "课程当前状态不允许该操作", Business: true}
+	CodeReviewRequired        = ErrorCode{Code: "REVIEW_REQUIRED", Status: http.StatusConflict, Description: "课程需提交审核并通过后才能发布", Business: true}
+	CodeRevisionNotFound      = ErrorCode{Code: "REVISION_NOT_FOUND", Status: http.StatusNotFound, Description: "修订版本不存在", Business: true}
+	CodeDraftNotFound         = ErrorCode{Code: "DRAFT_NOT_FOUND", Status: http.StatusNotFound, Description: "课程没有草稿", Business: true}
+	CodeScormPackageNotFound  = ErrorCode{Code: "SCORM_PACKAGE_NOT_FOUND", Status: http.StatusNotFound, Description: "课程未导入课件包", Business: true}
+	CodeExportJobNotFound     = ErrorCode{Code: "EXPORT_JOB_NOT_FOUND", Status: http.StatusNotFound, Description: "导出任务不存在", Business: true}
+	CodeExportNotReady        = ErrorCode{Code: "EXPORT_NOT_READY", Status: http.StatusConflict, Description: "导出任务尚未完成", Business: true}
+	CodeExportFileNotFound    = ErrorCode{Code: "EXPORT_FILE_NOT_FOUND", Status: http.StatusNotFound, Description: "导出文件不存在或已过期", Business: true}
+	CodeImportNotFound        = ErrorCode{Code: "IMPORT_NOT_FOUND", Status: http.StatusNotFound, Description: "导入记录不存在", Business: true}
+	CodeImportReportNotFound  = ErrorCode{Code: "IMPORT_REPORT_NOT_FOUND", Status: http.StatusNotFound, Description: "导入报告不存在", Business: true}
+	CodeXAPIStatementNotFound = ErrorCode{Code: "STATEMENT_NOT_FOUND", Status: http.StatusNotFound, Description: "xAPI 语句不存在"}
+)
+
+// ErrorCodes 全部错误码，用于接口文档
+var ErrorCodes = []ErrorCode{
+	CodeBadRequest, CodeUnauthorized, CodeForbidden, CodeNotFound, CodeMethodNotAllowed, CodeConflict, CodeInternal,
+	CodeInvalidCredentials, CodeUserDisabled, CodeUserNotFound, CodeUserExists, CodeRoleNotFound, CodeRoleExists, CodeMenuNotFound, CodeRuleNotFound,
+	CodeInUse, CodeProtected, CodeRecycleNotFound, CodeRestoreConflict,
+	CodeCourseNotFound, CodeInvalidCourseStatus, CodeReviewRequired, CodeRevisionNotFound, CodeDraftNotFound, CodeScormPackageNotFound,
+	CodeExportJobNotFound, CodeExportNotReady, CodeExportFileNotFound, CodeImportNotFound, CodeImportReportNotFound,
+	CodeXAPIStatementNotFound,
+}
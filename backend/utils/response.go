@@ -2,6 +2,10 @@ package utils
 
 import (
 	"net/http"
+	"strconv"
+
+	"learn-hub-backend/config"
+	"learn-hub-backend/logging"
 
 	"github.com/gin-gonic/gin"
 )
@@ -12,6 +16,50 @@ type Response struct {
 	Data         interface{} `json:"data,omitempty"`
 	ErrorCode    string      `json:"errorCode,omitempty"`
 	ErrorMessage string      `json:"errorMessage,omitempty"`
+	RequestID    string      `json:"requestId,omitempty"` // 请求 ID，与响应头 X-Request-ID 和日志中的 request_id 一致，仅错误响应返回
+}
+
+// PageResponse 分页列表响应，与 Ant Design Pro ProTable 的 request 约定一致
+type PageResponse struct {
+	Success  bool        `json:"success"`
+	Data     interface{} `json:"data"`
+	Total    int64       `json:"total"`
+	Current  int         `json:"current"`
+	PageSize int         `json:"pageSize"`
+}
+
+// Pagination 分页参数
+type Pagination struct {
+	Current  int
+	PageSize int
+}
+
+// DefaultPageSize 未指定 pageSize 时每页的数量
+const DefaultPageSize = 20
+
+// GetPagination 读取查询参数 current 和 pageSize，非法值按第 1 页、每页 DefaultPageSize 条处理
+func GetPagination(c *gin.Context) Pagination {
+	current, _ := strconv.Atoi(c.Query("current"))
+	pageSize, _ := strconv.Atoi(c.Query("pageSize"))
+	if current < 1 {
+		current = 1
+	}
+	if pageSize < 1 {
+		pageSize = DefaultPageSize
+	}
+	return Pagination{Current: current, PageSize: pageSize}
+}
+
+// Offset 当前页第一条数据的偏移量
+func (p Pagination) Offset() int {
+	return (p.Current - 1) * p.PageSize
+}
+
+// Bounds 对已全部加载到内存的 total 条数据分页时，当前页的下标范围 [start, end)
+func (p Pagination) Bounds(total int) (start, end int) {
+	start = min(p.Offset(), total)
+	end = min(start+p.PageSize, total)
+	return start, end
 }
 
 // Success 成功响应
@@ -22,32 +70,65 @@ func Success(c *gin.Context, data interface{}) {
 	})
 }
 
-// Error 错误响应
-func Error(c *gin.Context, code int, errorCode, errorMessage string) {
-	c.JSON(code, Response{
+// SuccessPage 分页列表响应，list 须为切片（为空时返回 []）
+func SuccessPage(c *gin.Context, list interface{}, total int64, page Pagination) {
+	c.JSON(http.StatusOK, PageResponse{
+		Success:  true,
+		Data:     list,
+		Total:    total,
+		Current:  page.Current,
+		PageSize: page.PageSize,
+	})
+}
+
+// Fail 按错误码目录返回错误响应，message 为空时使用错误码的说明
+func Fail(c *gin.Context, code ErrorCode, message string) {
+	FailWithData(c, code, message, nil)
+}
+
+// FailWithData 返回携带附加数据的错误响应（如冲突字段）
+func FailWithData(c *gin.Context, code ErrorCode, message string, data interface{}) {
+	if message == "" {
+		message = code.Description
+	}
+	c.JSON(StatusOf(code), Response{
 		Success:      false,
-		ErrorCode:    errorCode,
-		ErrorMessage: errorMessage,
+		Data:         data,
+		ErrorCode:    code.Code,
+		ErrorMessage: message,
+		RequestID:    logging.RequestID(c.Request.Context()),
 	})
 }
 
+// StatusOf 错误码实际返回的 HTTP 状态码：兼容模式下业务错误返回 200
+func StatusOf(code ErrorCode) int {
+	if code.Business && CompatMode() {
+		return http.StatusOK
+	}
+	return code.Status
+}
+
+// CompatMode 是否以兼容旧版前端的方式响应（配置项 api_compat）
+func CompatMode() bool {
+	return config.AppConfig != nil && config.AppConfig.APICompat
+}
+
 // Unauthorized 未授权
 func Unauthorized(c *gin.Context, message string) {
-	Error(c, http.StatusUnauthorized, "UNAUTHORIZED", message)
+	Fail(c, CodeUnauthorized, message)
 }
 
 // Forbidden 禁止访问
 func Forbidden(c *gin.Context, message string) {
-	Error(c, http.StatusForbidden, "FORBIDDEN", message)
+	Fail(c, CodeForbidden, message)
 }
 
 // BadRequest 错误请求
 func BadRequest(c *gin.Context, message string) {
-	Error(c, http.StatusBadRequest, "BAD_REQUEST", message)
+	Fail(c, CodeBadRequest, message)
 }
 
 // InternalError 服务器错误
 func InternalError(c *gin.Context, message string) {
-	Error(c, http.StatusInternalServerError, "INTERNAL_ERROR", message)
+	Fail(c, CodeInternal, message)
 }
-
@@ -0,0 +1,23 @@
+package utils
+
+import (
+	"errors"
+	"time"
+)
+
+// DateTimeLayout 接口统一使用的时间格式
+const DateTimeLayout = "2006-01-02 15:04:05"
+
+// ParseDateTime 解析时间字符串，支持 "2006-01-02 15:04:05"、"2006-01-02" 和 RFC3339 格式
+func ParseDateTime(value string) (time.Time, error) {
+	if t, err := time.ParseInLocation(DateTimeLayout, value, time.Local); err == nil {
+		return t, nil
+	}
+	if t, err := time.ParseInLocation("2006-01-02", value, time.Local); err == nil {
+		return t, nil
+	}
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t, nil
+	}
+	return time.Time{}, errors.New("时间格式错误，应为 2006-01-02 15:04:05")
+}
@@ -110,6 +110,7 @@ declare namespace API {
     access?: string;
     status?: number;
     roles?: string[];
+    roleIds?: number[];
     createdAt?: string;
     updatedAt?: string;
   };